@@ -0,0 +1,81 @@
+// Comando glpi-mock sobe um GLPI falso (High-Level API v2) numa porta local, com os dados de uma
+// fixture JSON. Serve para rodar o glpi-tui e os subcomandos sem um servidor de verdade:
+//
+//	go run ./cmd/glpi-mock -addr 127.0.0.1:8080 -fixture minha-fixture.json -fail-rate 0.1
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"glpi-tui/internal/glpimock"
+)
+
+func main() {
+	addr := flag.String("addr", "127.0.0.1:8080", "endereço em que o servidor escuta")
+	prefixo := flag.String("prefix", "/api.php/v2", "caminho base da API, como no GLPI de verdade")
+	arquivo := flag.String("fixture", "", "fixture JSON com usuários, grupos, chamados e falhas (padrão: a embutida)")
+	latencia := flag.Duration("latency", 0, "atraso de cada resposta (ex: 300ms)")
+	taxa := flag.Float64("fail-rate", 0, "fração das requisições que recebem HTTP 503 (0 a 1)")
+	flag.Parse()
+
+	fx := glpimock.DefaultFixture()
+	if *arquivo != "" {
+		var err error
+		if fx, err = glpimock.LoadFixture(*arquivo); err != nil {
+			fmt.Fprintf(os.Stderr, "Erro: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if *taxa < 0 || *taxa > 1 {
+		fmt.Fprintln(os.Stderr, "Erro: -fail-rate deve estar entre 0 e 1")
+		os.Exit(2)
+	}
+
+	srv := glpimock.New(fx)
+	srv.Latency = *latencia
+	srv.FailRate = *taxa
+
+	base := strings.TrimSuffix(*prefixo, "/")
+	mux := http.NewServeMux()
+	mux.Handle(base+"/", http.StripPrefix(base, registrar(srv)))
+
+	fmt.Fprintf(os.Stderr, "GLPI falso em http://%s%s\n", *addr, base)
+	if len(fx.Users) > 0 {
+		u := fx.Users[0]
+		cliente := ""
+		if len(fx.Clients) > 0 {
+			cliente = fmt.Sprintf(" GLPI_CLIENT_ID=%s GLPI_CLIENT_SECRET=%s", fx.Clients[0].ID, fx.Clients[0].Secret)
+		}
+		fmt.Fprintf(os.Stderr, "  GLPI_BASE_URL=http://%s%s%s GLPI_USER=%s GLPI_PASS=%s glpi-tui\n",
+			*addr, base, cliente, u.Username, u.Password)
+	}
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// registrar loga método, caminho, status e duração de cada requisição
+func registrar(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		inicio := time.Now()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(sw, r)
+		log.Printf("%s %s → %d (%s)", r.Method, r.URL.RequestURI(), sw.status, time.Since(inicio).Round(time.Millisecond))
+	})
+}
+
+type statusWriter struct {
+	http.ResponseWriter
+	status int
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
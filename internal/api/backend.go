@@ -0,0 +1,72 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"glpi-tui/internal/config"
+	"glpi-tui/internal/domain"
+)
+
+// Backend são as operações do GLPI usadas pela TUI e pela CLI. Há duas implementações:
+// Client, para a API v2 (api.php/v2, OAuth2), e V1Client, para a API REST legada
+// (apirest.php, Session-Token) dos servidores GLPI 9.x/10.x.
+type Backend interface {
+	// Sessão e usuário autenticado
+	Login() error
+	LoginContext(ctx context.Context) error
+	Interactive() bool
+	SetInteract(f func(instrucoes string))
+	GetMyID() error
+	GetMyIDContext(ctx context.Context) error
+	GetMyGroups() error
+	GetMyGroupsContext(ctx context.Context) error
+	MyUserID() int     // 0 enquanto GetMyID não tiver rodado
+	MyGroupIDs() []int // nil enquanto GetMyGroups não tiver rodado
+
+	// Chamados
+	GetTicketsPage(q TicketQuery) (TicketPage, error)
+	GetTicketsPageContext(ctx context.Context, q TicketQuery) (TicketPage, error)
+	GetTicketContext(ctx context.Context, ticketID int) (domain.Chamado, error)
+	CreateTicket(in TicketInput) (int, error)
+	CreateTicketContext(ctx context.Context, in TicketInput) (int, error)
+	AssignTicketViaUpdate(ticketID, entityID int) error
+	AssignTicketViaUpdateContext(ctx context.Context, ticketID, entityID int) error
+	ChangeTicketStatus(ticketID, entityID, from, to int) error
+	ChangeTicketStatusContext(ctx context.Context, ticketID, entityID, from, to int) error
+
+	// Atores e timeline
+	GetTicketActorsContext(ctx context.Context, ticketID int) ([]domain.TicketActor, error)
+	GetTicketFollowupsContext(ctx context.Context, ticketID int) ([]domain.TicketFollowup, error)
+	GetTicketTimelineContext(ctx context.Context, ticketID int) ([]domain.TimelineItem, error)
+	CreateTicketFollowup(ticketID int, content string) error
+	CreateTicketFollowupContext(ctx context.Context, ticketID int, content string) error
+	CreateTicketTask(ticketID, entityID int, in TaskInput) error
+
+	// Soluções
+	CreateTicketSolution(ticketID, entityID int, in SolutionInput) error
+	ApproveTicketSolution(ticketID, entityID, solutionID int) error
+	RefuseTicketSolution(ticketID, entityID, solutionID int, reason string) error
+	GetSolutionTypes() ([]domain.SolutionType, error)
+	GetSolutionTemplates() ([]domain.SolutionTemplate, error)
+
+	// Documentos
+	DownloadDocumentToDir(ctx context.Context, doc domain.TicketDocument, dir string, progress ProgressFunc) (string, error)
+	UploadDocumentContext(ctx context.Context, in UploadInput, progress ProgressFunc) error
+}
+
+var (
+	_ Backend = (*Client)(nil)
+	_ Backend = (*V1Client)(nil)
+)
+
+// New cria o client da versão de API da configuração (já resolvida pelo config: v1 ou v2)
+func New(cfg *config.Config) (Backend, error) {
+	switch cfg.API {
+	case config.APIV1:
+		return NewV1Client(cfg), nil
+	case config.APIV2:
+		return NewClient(cfg), nil
+	}
+	return nil, fmt.Errorf("versão de API desconhecida: %q", cfg.API)
+}
@@ -2,27 +2,47 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"glpi-tui/internal/config"
 	"glpi-tui/internal/domain"
+	"glpi-tui/internal/markdown"
 )
 
+// tokenExpirySkew é a margem de segurança usada para renovar o token antes do vencimento real
+const tokenExpirySkew = 60 * time.Second
+
 // TokenResponse mapeia a resposta do endpoint /token
 type TokenResponse struct {
-	AccessToken string `json:"access_token"`
+	AccessToken  string `json:"access_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int    `json:"expires_in"` // Segundos até o vencimento do access_token
+	RefreshToken string `json:"refresh_token"`
 }
 type Client struct {
-	cfg        *config.Config
-	HTTPClient *http.Client
-	Token      string
-	UserID     int // <--- NOVO CAMPO: Guarda seu ID após o login
+	cfg          *config.Config
+	HTTPClient   *http.Client
+	Token        string
+	RefreshToken string
+	TokenExpiry  time.Time // Zero quando o servidor não informa expires_in
+	UserID       int       // <--- NOVO CAMPO: Guarda seu ID após o login
+	GroupIDs     []int     // Grupos do usuário autenticado (preenchido por GetMyGroups)
+
+	// Interact mostra ao usuário o que fazer nos logins authorization_code/device_code (URL,
+	// código). nil = sem usuário por perto (ex.: dentro da TUI): o login pede o auth login.
+	Interact func(instrucoes string)
+
+	// authMu protege Token/RefreshToken/TokenExpiry, já que os comandos da TUI rodam em goroutines
+	authMu sync.Mutex
 }
 type FollowupInput struct {
 	Content       string `json:"content"`         // Obrigatório
@@ -44,10 +64,15 @@ type TeamMemberPayload struct {
 type UserMeResponse struct {
 	ID int `json:"id"`
 }
+type GroupResponse struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
 
 func NewClient(cfg *config.Config) *Client {
 	return &Client{
-		cfg: cfg,
+		cfg:          cfg,
+		RefreshToken: cfg.RefreshToken, // Sessão guardada pelo auth login, se houver
 		HTTPClient: &http.Client{
 			Timeout: 10 * time.Second,
 		},
@@ -56,8 +81,46 @@ func NewClient(cfg *config.Config) *Client {
 
 // Login realiza a autenticação (mantido conforme original, assumindo que /token está correto no doc)
 func (c *Client) Login() error {
-	// Conforme doc.json: tokenUrl: "/api.php/token"
-	url := c.cfg.BaseURL + "/token"
+	return c.LoginContext(context.Background())
+}
+
+// LoginContext é a variante de Login que respeita cancelamento e prazo do ctx.
+// Com um access_token ainda válido não faz nada: o login interativo feito antes da TUI
+// não é repetido, nem um refresh_token rotacionado é gasto à toa.
+func (c *Client) LoginContext(ctx context.Context) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	if c.tokenValidoLocked() {
+		return nil
+	}
+	return c.loginLocked(ctx)
+}
+
+// tokenValidoLocked diz se há um access_token que não vence nos próximos instantes.
+// Deve ser chamado com authMu travado.
+func (c *Client) tokenValidoLocked() bool {
+	return c.Token != "" && (c.TokenExpiry.IsZero() || time.Now().Add(tokenExpirySkew).Before(c.TokenExpiry))
+}
+
+// loginLocked executa o grant "password". Sem senha configurada (o auth login guardou só a
+// sessão), entra com o refresh_token salvo. Com auth authorization_code/device_code usa os fluxos
+// interativos de oauth.go. Deve ser chamado com authMu travado.
+func (c *Client) loginLocked(ctx context.Context) error {
+	if c.cfg.Interactive() {
+		if err := c.loginInterativoLocked(ctx); err != nil {
+			return fmt.Errorf("login falhou: %w", err)
+		}
+		return nil
+	}
+	if c.cfg.Password == "" {
+		if c.RefreshToken == "" {
+			return fmt.Errorf("login falhou: sem senha nem sessão salva; rode glpi-tui auth login")
+		}
+		if err := c.refreshGrantLocked(ctx); err != nil {
+			return fmt.Errorf("login falhou (sessão salva expirada? rode glpi-tui auth login): %w", err)
+		}
+		return nil
+	}
 
 	payload := map[string]string{
 		"grant_type":    "password",     // Conforme 'securitySchemes' -> 'password'
@@ -68,12 +131,64 @@ func (c *Client) Login() error {
 		"scope":         "api user", // Escopos listados no doc
 	}
 
+	if err := c.requestTokenLocked(ctx, payload); err != nil {
+		return fmt.Errorf("login falhou: %w", err)
+	}
+	return nil
+}
+
+// refreshLocked renova o access_token usando o refresh_token.
+// Se não houver refresh_token (ou ele também tiver expirado), refaz o login completo.
+// Deve ser chamado com authMu travado.
+func (c *Client) refreshLocked(ctx context.Context) error {
+	if c.RefreshToken != "" {
+		err := c.refreshGrantLocked(ctx)
+		if err == nil || IsTemporary(err) {
+			return err // Sem rede o re-login também falharia
+		}
+		// Refresh token recusado: descarta e cai para o re-login transparente abaixo
+		c.RefreshToken = ""
+	}
+	return c.loginLocked(ctx)
+}
+
+// refreshGrantLocked executa o grant "refresh_token". Deve ser chamado com authMu travado.
+func (c *Client) refreshGrantLocked(ctx context.Context) error {
+	return c.requestTokenLocked(ctx, c.comSecret(map[string]string{
+		"grant_type":    "refresh_token",
+		"client_id":     c.cfg.ClientID,
+		"refresh_token": c.RefreshToken,
+	}))
+}
+
+// Interactive diz se o login pode precisar do usuário no navegador ou em outro aparelho
+func (c *Client) Interactive() bool {
+	return c.cfg.Interactive()
+}
+
+// SetInteract troca o Interact (nil = login interativo indisponível)
+func (c *Client) SetInteract(f func(instrucoes string)) {
+	c.Interact = f
+}
+
+// MyUserID devolve o ID do usuário autenticado (preenchido por GetMyID)
+func (c *Client) MyUserID() int { return c.UserID }
+
+// MyGroupIDs devolve os grupos do usuário autenticado (preenchidos por GetMyGroups)
+func (c *Client) MyGroupIDs() []int { return c.GroupIDs }
+
+// requestTokenLocked faz o POST em /token e grava o resultado no client.
+// Deve ser chamado com authMu travado.
+func (c *Client) requestTokenLocked(ctx context.Context, payload map[string]string) error {
+	// Conforme doc.json: tokenUrl: "/api.php/token"
+	url := c.cfg.BaseURL + "/token"
+
 	jsonPayload, err := json.Marshal(payload)
 	if err != nil {
-		return fmt.Errorf("erro ao criar payload de login: %w", err)
+		return fmt.Errorf("erro ao criar payload de token: %w", err)
 	}
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return fmt.Errorf("erro ao criar requisição: %w", err)
 	}
@@ -88,7 +203,7 @@ func (c *Client) Login() error {
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != 201 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("login falhou status: %d - resp: %s", resp.StatusCode, string(body))
+		return &StatusError{What: "token", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var t TokenResponse
@@ -97,82 +212,256 @@ func (c *Client) Login() error {
 	}
 
 	c.Token = t.AccessToken
+	// Alguns servidores não devolvem um novo refresh_token na renovação: mantemos o anterior
+	if t.RefreshToken != "" && t.RefreshToken != c.RefreshToken {
+		c.RefreshToken = t.RefreshToken
+		// Guarda já a sessão nova: servidores que rotacionam o refresh_token invalidam o anterior.
+		// Falhar aqui só custa pedir a senha (ou um novo auth login) no próximo início.
+		_ = c.cfg.SaveRefreshToken(t.RefreshToken)
+	}
+	c.TokenExpiry = time.Time{}
+	if t.ExpiresIn > 0 {
+		c.TokenExpiry = time.Now().Add(time.Duration(t.ExpiresIn) * time.Second)
+	}
 	return nil
 }
 
-func (c *Client) GetTickets() ([]domain.Chamado, error) {
+// validToken devolve o token atual, renovando-o antes se estiver perto de expirar
+func (c *Client) validToken(ctx context.Context) (string, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
 	if c.Token == "" {
-		return nil, fmt.Errorf("client não autenticado: token vazio")
+		return "", fmt.Errorf("client não autenticado")
+	}
+	if !c.tokenValidoLocked() {
+		if err := c.refreshLocked(ctx); err != nil {
+			return "", fmt.Errorf("erro ao renovar token: %w", err)
+		}
+	}
+	return c.Token, nil
+}
+
+// renewAfterUnauthorized renova o token após um 401. Se outra goroutine já trocou
+// o token enquanto esta requisição estava em voo, apenas reaproveita o novo.
+func (c *Client) renewAfterUnauthorized(ctx context.Context, staleToken string) (string, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.Token != staleToken {
+		return c.Token, nil
+	}
+	if err := c.refreshLocked(ctx); err != nil {
+		return "", err
+	}
+	return c.Token, nil
+}
+
+// do executa uma requisição autenticada. Renova o token proativamente antes do
+// vencimento e, se mesmo assim o servidor responder 401, renova e repete a
+// requisição original uma única vez.
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	return c.doWith(c.HTTPClient, req)
+}
+
+// doWith é o do() usando um http.Client específico (ex: transferências sem timeout global)
+func (c *Client) doWith(hc *http.Client, req *http.Request) (*http.Response, error) {
+	token, err := c.validToken(req.Context())
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+token)
+	resp, err := hc.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return nil, fmt.Errorf("sessão expirada durante o envio e o corpo não pode ser repetido; tente novamente")
+	}
+
+	newToken, err := c.renewAfterUnauthorized(req.Context(), token)
+	if err != nil {
+		return nil, fmt.Errorf("sessão expirada e não foi possível renovar: %w", err)
+	}
+
+	// Recria a requisição: o corpo original já foi consumido na primeira tentativa
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao repetir requisição: %w", err)
+		}
+		retry.Body = body
+	}
+	retry.Header.Set("Authorization", "Bearer "+newToken)
+	return hc.Do(retry)
+}
+
+// GetTickets busca a primeira página de chamados (mais recentes primeiro)
+func (c *Client) GetTickets() ([]domain.Chamado, error) {
+	return c.GetTicketsContext(context.Background())
+}
+
+// GetTicketsContext é a variante de GetTickets que respeita cancelamento e prazo do ctx
+func (c *Client) GetTicketsContext(ctx context.Context) ([]domain.Chamado, error) {
+	page, err := c.GetTicketsPageContext(ctx, TicketQuery{})
+	if err != nil {
+		return nil, err
+	}
+	return page.Tickets, nil
+}
+
+// GetTicketsPage busca uma página de chamados conforme start/limit/filter de q
+func (c *Client) GetTicketsPage(q TicketQuery) (TicketPage, error) {
+	return c.GetTicketsPageContext(context.Background(), q)
+}
+
+// GetTicketsPageContext é a variante de GetTicketsPage que respeita cancelamento e prazo do ctx
+func (c *Client) GetTicketsPageContext(ctx context.Context, tq TicketQuery) (TicketPage, error) {
+	tq = tq.withDefaults()
+	if tq.unassigned() {
+		return c.unassignedPage(ctx, tq)
+	}
+	chamados, faixa, err := c.buscarChamados(ctx, tq)
+	if err != nil {
+		return TicketPage{}, err
+	}
+	return newTicketPage(semEquipe(chamados), tq.Start, faixa), nil
+}
+
+// chamadoComEquipe é o item da lista com os atores que o schema Ticket traz em "team"
+type chamadoComEquipe struct {
+	domain.Chamado
+	Team []domain.TicketActor `json:"team"`
+}
+
+// atribuido indica se algum técnico ou grupo está atribuído ao chamado
+func (t chamadoComEquipe) atribuido() bool {
+	for _, a := range t.Team {
+		if a.Role == "assigned" && (a.Type == "User" || a.Type == "Group") {
+			return true
+		}
+	}
+	return false
+}
+
+func semEquipe(ts []chamadoComEquipe) []domain.Chamado {
+	chamados := make([]domain.Chamado, len(ts))
+	for i, t := range ts {
+		chamados[i] = t.Chamado
+	}
+	return chamados
+}
+
+// unassignedLote é o tamanho das páginas lidas do servidor para montar a fila sem atribuição
+const unassignedLote = 100
+
+// unassignedPage atende TicketFilter.Unassigned: o RSQL não expressa "nenhum ator atribuído",
+// então a consulta percorre todas as páginas do resto do filtro (em geral só os status em
+// aberto), fica com os chamados sem técnico nem grupo na equipe e pagina o resultado aqui.
+func (c *Client) unassignedPage(ctx context.Context, tq TicketQuery) (TicketPage, error) {
+	lote := tq
+	lote.Start, lote.Limit = 0, unassignedLote
+	var livres []domain.Chamado
+	for {
+		chamados, faixa, err := c.buscarChamados(ctx, lote)
+		if err != nil {
+			return TicketPage{}, err
+		}
+		for _, t := range chamados {
+			if !t.atribuido() {
+				livres = append(livres, t.Chamado)
+			}
+		}
+		page := newTicketPage(semEquipe(chamados), lote.Start, faixa)
+		if len(chamados) == 0 || !page.HasMore() {
+			break
+		}
+		lote.Start = page.Next()
 	}
 
+	pagina := []domain.Chamado{}
+	if tq.Start < len(livres) {
+		pagina = livres[tq.Start:min(tq.Start+tq.Limit, len(livres))]
+	}
+	return TicketPage{Tickets: pagina, Start: tq.Start, Total: len(livres)}, nil
+}
+
+// buscarChamados faz um GET /Assistance/Ticket e devolve os itens junto com o Content-Range
+func (c *Client) buscarChamados(ctx context.Context, tq TicketQuery) ([]chamadoComEquipe, string, error) {
 	// CORREÇÃO 1: A rota correta no doc.json é /Assistance/Ticket
 	endpoint := c.cfg.BaseURL + "/Assistance/Ticket"
 
 	u, err := url.Parse(endpoint)
 	if err != nil {
-		return nil, fmt.Errorf("erro na URL: %w", err)
+		return nil, "", fmt.Errorf("erro na URL: %w", err)
 	}
 
 	q := u.Query()
 
 	// CORREÇÃO 2: Parâmetros suportados no doc para GET /Assistance/Ticket:
 	// filter, start, limit, sort
-	q.Set("limit", "20")           // Default é 100, ajustando para teste
-	q.Set("sort", "date_mod:desc") // Formato correto segundo doc: property:direction
+	q.Set("start", strconv.Itoa(tq.Start))
+	q.Set("limit", strconv.Itoa(tq.Limit))
+	q.Set("sort", tq.Sort) // Formato correto segundo doc: property:direction
+	if rsql := tq.rsql(); rsql != "" {
+		q.Set("filter", rsql)
+	}
 
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
-		return nil, fmt.Errorf("erro ao criar req: %w", err)
+		return nil, "", fmt.Errorf("erro ao criar req: %w", err)
 	}
 
 	// Headers obrigatórios
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.Token)
 
 	// Headers opcionais listados no doc que podem ser úteis no futuro:
 	// req.Header.Set("GLPI-Entity", "0")
 	// req.Header.Set("GLPI-Entity-Recursive", "true")
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
-		return nil, fmt.Errorf("erro de conexão: %w", err)
+		return nil, "", fmt.Errorf("erro de conexão: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != 206 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("erro na API (HTTP %d): %s", resp.StatusCode, string(body))
+		return nil, "", &StatusError{What: "chamados", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
-	var chamados []domain.Chamado
+	var chamados []chamadoComEquipe
 	// O doc diz que a resposta é um array direto de schemas.Ticket
 	if err := json.NewDecoder(resp.Body).Decode(&chamados); err != nil {
-		return nil, fmt.Errorf("erro de decode do JSON: %w", err)
+		return nil, "", fmt.Errorf("erro de decode do JSON: %w", err)
 	}
-
-	return chamados, nil
+	return chamados, resp.Header.Get("Content-Range"), nil
 }
 
 // GetTicketActors busca os atores (Team Members) de um chamado específico.
 // Endpoint: GET /Assistance/Ticket/{id}/TeamMember
 func (c *Client) GetTicketActors(ticketID int) ([]domain.TicketActor, error) {
-	if c.Token == "" {
-		return nil, fmt.Errorf("client não autenticado")
-	}
+	return c.GetTicketActorsContext(context.Background(), ticketID)
+}
 
+// GetTicketActorsContext é a variante de GetTicketActors que respeita cancelamento e prazo do ctx
+func (c *Client) GetTicketActorsContext(ctx context.Context, ticketID int) ([]domain.TicketActor, error) {
 	endpoint := fmt.Sprintf("%s/Assistance/Ticket/%d/TeamMember", c.cfg.BaseURL, ticketID)
 
-	req, err := http.NewRequest("GET", endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar req de atores: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.Token)
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("erro de conexão ao buscar atores: %w", err)
 	}
@@ -180,7 +469,7 @@ func (c *Client) GetTicketActors(ticketID int) ([]domain.TicketActor, error) {
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != 206 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("erro API atores (HTTP %d): %s", resp.StatusCode, string(body))
+		return nil, &StatusError{What: "atores", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var actors []domain.TicketActor
@@ -194,10 +483,11 @@ func (c *Client) GetTicketActors(ticketID int) ([]domain.TicketActor, error) {
 // GetTicketFollowups busca os acompanhamentos de um chamado na timeline.
 // Endpoint: GET /Assistance/Ticket/{id}/Timeline/Followup
 func (c *Client) GetTicketFollowups(ticketID int) ([]domain.TicketFollowup, error) {
-	if c.Token == "" {
-		return nil, fmt.Errorf("client não autenticado")
-	}
+	return c.GetTicketFollowupsContext(context.Background(), ticketID)
+}
 
+// GetTicketFollowupsContext é a variante de GetTicketFollowups que respeita cancelamento e prazo do ctx
+func (c *Client) GetTicketFollowupsContext(ctx context.Context, ticketID int) ([]domain.TicketFollowup, error) {
 	endpoint := fmt.Sprintf("%s/Assistance/Ticket/%d/Timeline/Followup", c.cfg.BaseURL, ticketID)
 
 	u, err := url.Parse(endpoint)
@@ -210,15 +500,14 @@ func (c *Client) GetTicketFollowups(ticketID int) ([]domain.TicketFollowup, erro
 	q.Set("expand_dropdowns", "true")
 	u.RawQuery = q.Encode()
 
-	req, err := http.NewRequest("GET", u.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("erro ao criar req de followups: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.Token)
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return nil, fmt.Errorf("erro de conexão ao buscar followups: %w", err)
 	}
@@ -226,7 +515,7 @@ func (c *Client) GetTicketFollowups(ticketID int) ([]domain.TicketFollowup, erro
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != 206 {
 		body, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("erro API followups (HTTP %d): %s", resp.StatusCode, string(body))
+		return nil, &StatusError{What: "followups", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	// --- CORREÇÃO DE ESTRUTURA ---
@@ -252,19 +541,20 @@ func (c *Client) GetTicketFollowups(ticketID int) ([]domain.TicketFollowup, erro
 	return followups, nil
 }
 
-// CreateTicketFollowup envia um novo acompanhamento.
+// CreateTicketFollowup envia um novo acompanhamento. O content é Markdown (texto simples também serve).
 // Endpoint: POST /Assistance/Ticket/{id}/Timeline/Followup
 func (c *Client) CreateTicketFollowup(ticketID int, content string) error {
-	if c.Token == "" {
-		return fmt.Errorf("client não autenticado")
-	}
+	return c.CreateTicketFollowupContext(context.Background(), ticketID, content)
+}
 
+// CreateTicketFollowupContext é a variante de CreateTicketFollowup que respeita cancelamento e prazo do ctx
+func (c *Client) CreateTicketFollowupContext(ctx context.Context, ticketID int, content string) error {
 	endpoint := fmt.Sprintf("%s/Assistance/Ticket/%d/Timeline/Followup", c.cfg.BaseURL, ticketID)
 
-	// TENTATIVA 3: Enviar o JSON "plano", sem o wrapper input, e com HTML simples
-	// Às vezes o GLPI ignora texto plano se a validação de RichText estiver estrita
+	// JSON "plano", sem o wrapper input. O texto vem em Markdown e vai convertido em HTML:
+	// o GLPI ignora texto plano se a validação de RichText estiver estrita
 	payload := FollowupPayload{
-		Content:       fmt.Sprintf("<p>%s</p>", content), // Envelopa em HTML
+		Content:       markdown.ToHTML(content),
 		RequestTypeID: 1,
 		ItemsID:       ticketID,
 		ItemType:      "Ticket",
@@ -275,21 +565,17 @@ func (c *Client) CreateTicketFollowup(ticketID int, content string) error {
 		return fmt.Errorf("erro ao criar payload: %w", err)
 	}
 
-	// DEBUG: Vamos imprimir no terminal o que está sendo enviado para ter certeza
-	fmt.Printf("\n--- DEBUG PAYLOAD ---\n%s\n---------------------\n", string(jsonPayload))
-
-	req, err := http.NewRequest("POST", endpoint, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return fmt.Errorf("erro ao criar requisição: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.Token)
 	// Se você usa App-Token no header globalmente, verifique se ele está aqui
 	// req.Header.Set("App-Token", "SEU_APP_TOKEN")
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("erro de conexão ao criar followup: %w", err)
 	}
@@ -297,7 +583,7 @@ func (c *Client) CreateTicketFollowup(ticketID int, content string) error {
 
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != 201 {
 		body, _ := io.ReadAll(resp.Body)
-		return fmt.Errorf("erro API criar followup (HTTP %d): %s", resp.StatusCode, string(body))
+		return &StatusError{What: "criar followup", StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	return nil
@@ -305,23 +591,23 @@ func (c *Client) CreateTicketFollowup(ticketID int, content string) error {
 
 // GetMyID consulta o endpoint fornecido pela documentação
 func (c *Client) GetMyID() error {
-	if c.Token == "" {
-		return fmt.Errorf("client não autenticado")
-	}
+	return c.GetMyIDContext(context.Background())
+}
 
+// GetMyIDContext é a variante de GetMyID que respeita cancelamento e prazo do ctx
+func (c *Client) GetMyIDContext(ctx context.Context) error {
 	// CORREÇÃO: Usando estritamente o endpoint fornecido pelo NotebookLM
 	// Antes eu presumi incorretamente que seria apenas /User/Me
 	endpoint := c.cfg.BaseURL + "/Administration/User/Me"
 
-	req, err := http.NewRequest("GET", endpoint, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
 	if err != nil {
 		return fmt.Errorf("erro ao criar req UserMe: %w", err)
 	}
 
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.Token)
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("erro de conexão UserMe: %w", err)
 	}
@@ -331,7 +617,7 @@ func (c *Client) GetMyID() error {
 		body, _ := io.ReadAll(resp.Body)
 		// Se der 404 de novo aqui, significa que a documentação pode ter nos dado o caminho da interface web e não da API
 		// Mas primeiro temos que testar o que ela mandou.
-		return fmt.Errorf("erro API UserMe (HTTP %d) no endpoint %s: %s", resp.StatusCode, endpoint, string(body))
+		return &StatusError{What: "UserMe no endpoint " + endpoint, StatusCode: resp.StatusCode, Body: string(body)}
 	}
 
 	var u UserMeResponse
@@ -343,24 +629,112 @@ func (c *Client) GetMyID() error {
 	return nil
 }
 
+// GetMyGroups busca os grupos do usuário autenticado e guarda os IDs em GroupIDs
+func (c *Client) GetMyGroups() error {
+	return c.GetMyGroupsContext(context.Background())
+}
+
+// GetMyGroupsContext é a variante de GetMyGroups que respeita cancelamento e prazo do ctx
+func (c *Client) GetMyGroupsContext(ctx context.Context) error {
+	endpoint := c.cfg.BaseURL + "/Administration/User/Me/Group"
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao criar req de grupos: %w", err)
+	}
+
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("erro de conexão ao buscar grupos: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != 206 {
+		body, _ := io.ReadAll(resp.Body)
+		return &StatusError{What: "grupos", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var groups []GroupResponse
+	if err := json.NewDecoder(resp.Body).Decode(&groups); err != nil {
+		return fmt.Errorf("erro decode grupos: %w", err)
+	}
+
+	ids := make([]int, len(groups))
+	for i, g := range groups {
+		ids[i] = g.ID
+	}
+	c.GroupIDs = ids
+	return nil
+}
+
 // AssignTicketViaUpdate atribui o ticket usando a rota principal (PATCH)
 // Documentação: PATCH /Assistance/Ticket/{id} exige envelope "input"
+// O status segue domain.AssignStatus, conferido com o status atual lido do servidor.
 func (c *Client) AssignTicketViaUpdate(ticketID int, entityID int) error {
+	return c.AssignTicketViaUpdateContext(context.Background(), ticketID, entityID)
+}
+
+// AssignTicketViaUpdateContext é a variante de AssignTicketViaUpdate que respeita cancelamento e prazo do ctx
+func (c *Client) AssignTicketViaUpdateContext(ctx context.Context, ticketID int, entityID int) error {
 	if c.UserID == 0 {
 		return fmt.Errorf("ID do usuário desconhecido. GetMyID foi chamado?")
 	}
 
-	endpoint := fmt.Sprintf("%s/Assistance/Ticket/%d", c.cfg.BaseURL, ticketID)
+	atual, err := c.GetTicketContext(ctx, ticketID)
+	if err != nil {
+		return err
+	}
+	to, err := domain.AssignStatus(atual.Status.ID)
+	if err != nil {
+		return err
+	}
 
 	// ESTRUTURA DO PAYLOAD (Escrita):
 	// Usamos "users_id_assign" para definir o técnico.
-	// Usamos "status": 2 para mover para "Processing/Assigned".
+	// Usamos "status" só quando ele muda (ex: Novo -> Atribuído).
 	// Isso evita mexer no array complexo "team" e sobrescrever dados.
+	campos := map[string]interface{}{
+		"users_id_assign": c.UserID, // O ID do técnico (você)
+	}
+	if to != atual.Status.ID {
+		campos["status"] = to
+	}
+	return c.UpdateTicketContext(ctx, ticketID, entityID, campos)
+}
+
+// ChangeTicketStatus muda o status do chamado, recusando movimentos fora da máquina de estados do domain
+func (c *Client) ChangeTicketStatus(ticketID, entityID, from, to int) error {
+	return c.ChangeTicketStatusContext(context.Background(), ticketID, entityID, from, to)
+}
+
+// ChangeTicketStatusContext é a variante de ChangeTicketStatus que respeita cancelamento e prazo do ctx
+func (c *Client) ChangeTicketStatusContext(ctx context.Context, ticketID, entityID, from, to int) error {
+	if err := domain.ValidateTransition(from, to); err != nil {
+		return err
+	}
+	return c.UpdateTicketContext(ctx, ticketID, entityID, map[string]interface{}{
+		"status": to,
+	})
+}
+
+// UpdateTicket atualiza parcialmente o chamado com os campos informados
+// Documentação: PATCH /Assistance/Ticket/{id} exige envelope "input"
+func (c *Client) UpdateTicket(ticketID int, entityID int, fields map[string]interface{}) error {
+	return c.UpdateTicketContext(context.Background(), ticketID, entityID, fields)
+}
+
+// UpdateTicketContext é a variante de UpdateTicket que respeita cancelamento e prazo do ctx
+func (c *Client) UpdateTicketContext(ctx context.Context, ticketID int, entityID int, fields map[string]interface{}) error {
+	if len(fields) == 0 {
+		return fmt.Errorf("nenhum campo para atualizar")
+	}
+
+	endpoint := fmt.Sprintf("%s/Assistance/Ticket/%d", c.cfg.BaseURL, ticketID)
+
 	payload := map[string]interface{}{
-		"input": map[string]interface{}{
-			"status":          2,        // 2 = Em atendimento
-			"users_id_assign": c.UserID, // O ID do técnico (você)
-		},
+		"input": fields,
 	}
 
 	jsonPayload, err := json.Marshal(payload)
@@ -369,19 +743,18 @@ func (c *Client) AssignTicketViaUpdate(ticketID int, entityID int) error {
 	}
 
 	// IMPORTANTE: Método PATCH (Atualização Parcial)
-	req, err := http.NewRequest("PATCH", endpoint, bytes.NewBuffer(jsonPayload))
+	req, err := http.NewRequestWithContext(ctx, "PATCH", endpoint, bytes.NewBuffer(jsonPayload))
 	if err != nil {
 		return fmt.Errorf("erro req patch: %w", err)
 	}
 
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Accept", "application/json")
-	req.Header.Set("Authorization", "Bearer "+c.Token)
 
 	// Contexto da Entidade (Obrigatório segundo doc.txt)
 	req.Header.Set("GLPI-Entity", fmt.Sprintf("%d", entityID))
 
-	resp, err := c.HTTPClient.Do(req)
+	resp, err := c.do(req)
 	if err != nil {
 		return fmt.Errorf("erro conexão patch: %w", err)
 	}
@@ -401,7 +774,7 @@ func (c *Client) AssignTicketViaUpdate(ticketID int, entityID int) error {
 				debugBuilder.WriteString(fmt.Sprintf("%s: %s\n", k, v))
 			}
 		}
-		return fmt.Errorf("FALHA PATCH:\n%s\nBODY: %s", debugBuilder.String(), bodyString)
+		return &StatusError{What: "patch chamado", StatusCode: resp.StatusCode, Body: debugBuilder.String() + "BODY: " + bodyString}
 	}
 
 	return nil
@@ -0,0 +1,340 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"glpi-tui/internal/config"
+	"glpi-tui/internal/domain"
+	"glpi-tui/internal/glpimock"
+)
+
+// registro guarda método, caminho e status de cada requisição que chegou ao servidor falso
+type registro struct {
+	mu        sync.Mutex
+	respostas []string
+}
+
+func (r *registro) anotar(linha string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.respostas = append(r.respostas, linha)
+}
+
+func (r *registro) linhas() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return slices.Clone(r.respostas)
+}
+
+// anotador registra a resposta ao escrever o cabeçalho, antes de o client recebê-la
+type anotador struct {
+	http.ResponseWriter
+	reg     *registro
+	pedido  string
+	anotado bool
+}
+
+func (w *anotador) WriteHeader(status int) {
+	if w.anotado {
+		return
+	}
+	w.anotado = true
+	w.reg.anotar(w.pedido + " " + http.StatusText(status))
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *anotador) Write(b []byte) (int, error) {
+	w.WriteHeader(http.StatusOK) // Sem efeito depois do primeiro
+	return w.ResponseWriter.Write(b)
+}
+
+// novoServidor sobe o glpimock com a fixture padrão e devolve um Client já logado como ana
+func novoServidor(t *testing.T, ajustar func(*glpimock.Server)) (*glpimock.Server, *Client, *registro) {
+	t.Helper()
+	srv := glpimock.New(glpimock.DefaultFixture())
+	if ajustar != nil {
+		ajustar(srv)
+	}
+	reg := &registro{}
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		srv.ServeHTTP(&anotador{ResponseWriter: w, reg: reg, pedido: r.Method + " " + r.URL.Path}, r)
+	}))
+	t.Cleanup(hs.Close)
+
+	c := NewClient(&config.Config{BaseURL: hs.URL, ClientID: "glpi-tui", ClientSecret: "segredo", Username: "ana", Password: "ana123"})
+	if err := c.Login(); err != nil {
+		t.Fatalf("login: %v", err)
+	}
+	return srv, c, reg
+}
+
+func TestPaginacaoPeloContentRange(t *testing.T) {
+	_, c, _ := novoServidor(t, nil)
+
+	var ids []int
+	q := TicketQuery{Limit: 3}
+	for paginas := 1; ; paginas++ {
+		page, err := c.GetTicketsPage(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Start != q.Start || page.Total != 8 {
+			t.Fatalf("página %d: Start=%d Total=%d, esperado Start=%d Total=8", paginas, page.Start, page.Total, q.Start)
+		}
+		for _, ch := range page.Tickets {
+			ids = append(ids, ch.ID)
+		}
+		if !page.HasMore() {
+			if paginas != 3 {
+				t.Errorf("%d páginas, esperado 3 (3+3+2)", paginas)
+			}
+			break
+		}
+		if paginas > 3 {
+			t.Fatalf("HasMore não parou: %+v", page)
+		}
+		q.Start = page.Next()
+	}
+
+	slices.Sort(ids)
+	if want := []int{101, 102, 103, 104, 105, 106, 107, 108}; !slices.Equal(ids, want) {
+		t.Errorf("chamados = %v, esperado %v", ids, want)
+	}
+}
+
+func TestTokenExpiradoRepeteUmaVez(t *testing.T) {
+	srv, c, reg := novoServidor(t, nil)
+	antes := c.Token
+
+	srv.ExpireTokens()
+	page, err := c.GetTicketsPage(TicketQuery{Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Tickets) != 1 {
+		t.Errorf("%d chamados, esperado 1", len(page.Tickets))
+	}
+	if c.Token == antes {
+		t.Errorf("o token não foi renovado depois do 401")
+	}
+
+	linhas := reg.linhas()
+	want := []string{
+		"POST /token OK",                         // Login
+		"GET /Assistance/Ticket Unauthorized",    // Token expirado no servidor
+		"POST /token OK",                         // Renovação pelo refresh_token
+		"GET /Assistance/Ticket Partial Content", // Repetição com o token novo
+	}
+	if !slices.Equal(linhas, want) {
+		t.Errorf("requisições:\n%q\nesperado:\n%q", linhas, want)
+	}
+}
+
+func TestFalhasTemporarias(t *testing.T) {
+	casos := []struct {
+		status     int
+		temporario bool
+	}{
+		{http.StatusServiceUnavailable, true},
+		{http.StatusInternalServerError, true},
+		{http.StatusTooManyRequests, true},
+		{http.StatusForbidden, false},
+		{http.StatusNotFound, false},
+	}
+	for _, tc := range casos {
+		t.Run(http.StatusText(tc.status), func(t *testing.T) {
+			srv, c, _ := novoServidor(t, nil)
+			srv.Inject(glpimock.Fault{Method: "GET", Path: "/Assistance/Ticket", Status: tc.status, Times: 1})
+
+			_, err := c.GetTicketsPage(TicketQuery{})
+			var se *StatusError
+			if !errors.As(err, &se) || se.StatusCode != tc.status {
+				t.Fatalf("erro = %v, esperado StatusError com HTTP %d", err, tc.status)
+			}
+			if IsTemporary(err) != tc.temporario {
+				t.Errorf("IsTemporary = %v, esperado %v", !tc.temporario, tc.temporario)
+			}
+
+			// Times: 1 — a segunda tentativa passa
+			if _, err := c.GetTicketsPage(TicketQuery{}); err != nil {
+				t.Errorf("depois da falha única: %v", err)
+			}
+		})
+	}
+}
+
+func TestLatenciaEstouraPrazo(t *testing.T) {
+	_, c, _ := novoServidor(t, func(s *glpimock.Server) { s.Latency = 200 * time.Millisecond })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := c.GetTicketsPageContext(ctx, TicketQuery{})
+	if err == nil {
+		t.Fatal("esperado erro de prazo")
+	}
+	if !IsTemporary(err) {
+		t.Errorf("IsTemporary(%v) = false, esperado true", err)
+	}
+
+	c.HTTPClient.Timeout = 50 * time.Millisecond
+	if _, err = c.GetTicketsPage(TicketQuery{}); err == nil || !IsTemporary(err) {
+		t.Errorf("timeout do http.Client: erro = %v, esperado temporário", err)
+	}
+}
+
+func TestUpdateTicketPatch(t *testing.T) {
+	srv, c, reg := novoServidor(t, nil)
+
+	if err := c.UpdateTicket(101, 2, map[string]interface{}{"priority": 5, "name": "Impressora HP do 2º andar"}); err != nil {
+		t.Fatal(err)
+	}
+	ch, ok := srv.Ticket(101)
+	if !ok {
+		t.Fatal("chamado 101 sumiu")
+	}
+	if ch.Priority != 5 || ch.Name != "Impressora HP do 2º andar" {
+		t.Errorf("chamado depois do PATCH: prioridade %d, título %q", ch.Priority, ch.Name)
+	}
+	linhas := reg.linhas()
+	if ultima := linhas[len(linhas)-1]; ultima != "PATCH /Assistance/Ticket/101 OK" {
+		t.Errorf("última requisição = %q", ultima)
+	}
+
+	err := c.UpdateTicket(999, 0, map[string]interface{}{"priority": 5})
+	var se *StatusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusNotFound {
+		t.Errorf("chamado inexistente: erro = %v, esperado HTTP 404", err)
+	}
+}
+
+func TestLoginComTokenValidoNaoRepete(t *testing.T) {
+	_, c, reg := novoServidor(t, nil)
+	antes := c.Token
+
+	if err := c.Login(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Token != antes {
+		t.Errorf("o segundo Login trocou o token")
+	}
+	if linhas := reg.linhas(); len(linhas) != 1 {
+		t.Errorf("requisições = %q, esperado só o login inicial", linhas)
+	}
+}
+
+func TestDeviceAuthorizationEmFormulario(t *testing.T) {
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ct := r.Header.Get("Content-Type"); ct != "application/x-www-form-urlencoded" {
+			t.Errorf("Content-Type = %q, esperado formulário (RFC 8628 §3.1)", ct)
+		}
+		if err := r.ParseForm(); err != nil || r.PostForm.Get("client_id") != "glpi-tui" || r.PostForm.Get("scope") != escopoOAuth {
+			t.Errorf("formulário = %v (%v)", r.PostForm, err)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"device_code":"d1","user_code":"ABCD-EFGH","verification_uri":"https://glpi/device"}`)
+	}))
+	defer hs.Close()
+
+	c := NewClient(&config.Config{BaseURL: hs.URL, ClientID: "glpi-tui"})
+	var d deviceAuthResponse
+	payload := c.comSecret(map[string]string{"client_id": c.cfg.ClientID, "scope": escopoOAuth})
+	if err := c.postSemToken(context.Background(), hs.URL+"/device", payload, &d, "device authorization"); err != nil {
+		t.Fatal(err)
+	}
+	if d.UserCode != "ABCD-EFGH" {
+		t.Errorf("user_code = %q", d.UserCode)
+	}
+}
+
+func TestFilaSemAtribuicao(t *testing.T) {
+	_, c, _ := novoServidor(t, nil)
+
+	// Os chamados da fixture têm todos técnico ou grupo; os criados aqui só têm requerente
+	var novos []int
+	for _, nome := range []string{"Mouse sem fio", "Troca de toner", "Acesso ao SEI"} {
+		id, err := c.CreateTicket(TicketInput{Name: nome, Content: "Detalhes", Type: domain.TypeRequest, Urgency: 3})
+		if err != nil {
+			t.Fatal(err)
+		}
+		novos = append(novos, id)
+	}
+	if err := c.GetMyID(); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.AssignTicketViaUpdate(novos[1], 0); err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []int
+	// Os criados aqui têm o mesmo date_mod: a ordem pelo ID mantém as páginas estáveis
+	q := TicketQuery{Limit: 1, Sort: "id:asc", Scope: TicketFilter{Unassigned: true, Statuses: []int{domain.StatusNew, domain.StatusAssign, domain.StatusPlanned, domain.StatusPending}}}
+	for {
+		page, err := c.GetTicketsPage(q)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if page.Total != 2 {
+			t.Fatalf("Total = %d, esperado 2", page.Total)
+		}
+		for _, ch := range page.Tickets {
+			ids = append(ids, ch.ID)
+		}
+		if !page.HasMore() {
+			break
+		}
+		q.Start = page.Next()
+	}
+	slices.Sort(ids)
+	if want := []int{novos[0], novos[2]}; !slices.Equal(ids, want) {
+		t.Errorf("sem atribuição = %v, esperado %v", ids, want)
+	}
+}
+
+func TestTimelinePulaItemInvalido(t *testing.T) {
+	hs := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `[
+			{"type": "Followup", "item": {"id": 1, "content": "<p>primeiro</p>", "date": "2026-01-02 10:00:00"}},
+			{"type": "Followup", "item": {"id": "dois", "content": ["não é texto"]}},
+			{"type": "ITILReminder", "item": {"id": 9}},
+			{"type": "Followup", "item": {"id": 3, "content": "<p>terceiro</p>", "date": "2026-01-03 10:00:00"}}
+		]`)
+	}))
+	defer hs.Close()
+
+	c := NewClient(&config.Config{BaseURL: hs.URL})
+	c.Token = "token"
+	items, err := c.GetTicketTimeline(101)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []int
+	for _, it := range items {
+		ids = append(ids, it.Followup.ID)
+	}
+	if want := []int{1, 3}; !slices.Equal(ids, want) {
+		t.Errorf("acompanhamentos = %v, esperado %v", ids, want)
+	}
+}
+
+func TestTituloContaCaracteres(t *testing.T) {
+	in := TicketInput{Content: "Detalhes", Type: domain.TypeRequest, Urgency: 3}
+
+	in.Name = strings.Repeat("ção", 85) // 255 caracteres, 425 bytes
+	if err := in.Validate(); err != nil {
+		t.Errorf("título com 255 caracteres acentuados: %v", err)
+	}
+	in.Name += "é"
+	if err := in.Validate(); err == nil {
+		t.Errorf("título com 256 caracteres foi aceito")
+	}
+}
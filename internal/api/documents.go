@@ -0,0 +1,280 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"glpi-tui/internal/domain"
+)
+
+// ProgressFunc recebe o andamento de uma transferência (total = -1 quando desconhecido)
+type ProgressFunc func(done, total int64)
+
+// UploadInput descreve o arquivo local a anexar
+type UploadInput struct {
+	TicketID   int
+	EntityID   int
+	Path       string // Caminho do arquivo local
+	FollowupID int    // 0 = anexa ao chamado; senão anexa ao acompanhamento informado
+}
+
+// progressReader conta os bytes lidos e avisa o ProgressFunc
+type progressReader struct {
+	r        io.Reader
+	done     int64
+	total    int64
+	progress ProgressFunc
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.done += int64(n)
+	if p.progress != nil && n > 0 {
+		p.progress(p.done, p.total)
+	}
+	return n, err
+}
+
+// transferHTTPClient usa o mesmo transporte do client, mas sem o timeout global:
+// arquivos grandes levam mais que 10s e o prazo fica por conta do ctx.
+func (c *Client) transferHTTPClient() *http.Client {
+	return &http.Client{Transport: c.HTTPClient.Transport}
+}
+
+// GetTicketDocuments lista os documentos anexados ao chamado.
+// Endpoint: GET /Assistance/Ticket/{id}/Timeline/Document
+func (c *Client) GetTicketDocuments(ticketID int) ([]domain.TicketDocument, error) {
+	return c.GetTicketDocumentsContext(context.Background(), ticketID)
+}
+
+// GetTicketDocumentsContext é a variante de GetTicketDocuments que respeita cancelamento e prazo do ctx
+func (c *Client) GetTicketDocumentsContext(ctx context.Context, ticketID int) ([]domain.TicketDocument, error) {
+	endpoint := fmt.Sprintf("%s/Assistance/Ticket/%d/Timeline/Document", c.cfg.BaseURL, ticketID)
+
+	q := url.Values{}
+	q.Set("expand_dropdowns", "true")
+
+	// Mesmo envelope {type, item} da timeline de acompanhamentos
+	var raw []struct {
+		Type string                `json:"type"`
+		Item domain.TicketDocument `json:"item"`
+	}
+	if err := c.getJSON(ctx, endpoint, q, &raw, "documentos"); err != nil {
+		return nil, err
+	}
+
+	docs := make([]domain.TicketDocument, 0, len(raw))
+	for _, w := range raw {
+		docs = append(docs, w.Item)
+	}
+	return docs, nil
+}
+
+// DownloadDocument grava o conteúdo do documento em dst.
+// Endpoint: GET /Management/Document/{id}/Download
+func (c *Client) DownloadDocument(documentID int, dst io.Writer, progress ProgressFunc) error {
+	return c.DownloadDocumentContext(context.Background(), documentID, dst, progress)
+}
+
+// DownloadDocumentContext é a variante de DownloadDocument que respeita cancelamento e prazo do ctx
+func (c *Client) DownloadDocumentContext(ctx context.Context, documentID int, dst io.Writer, progress ProgressFunc) error {
+	endpoint := fmt.Sprintf("%s/Management/Document/%d/Download", c.cfg.BaseURL, documentID)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("erro ao criar req de download: %w", err)
+	}
+	req.Header.Set("Accept", "application/octet-stream")
+
+	resp, err := c.doWith(c.transferHTTPClient(), req)
+	if err != nil {
+		return fmt.Errorf("erro de conexão ao baixar documento: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return &StatusError{What: "download", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	src := &progressReader{r: resp.Body, total: resp.ContentLength, progress: progress}
+	if _, err := io.Copy(dst, src); err != nil {
+		return fmt.Errorf("erro ao gravar documento: %w", err)
+	}
+	return nil
+}
+
+// DownloadDocumentToDir baixa o documento para dir e devolve o caminho final.
+// Se já existir um arquivo com o mesmo nome, acrescenta um sufixo numérico em vez de sobrescrever.
+func (c *Client) DownloadDocumentToDir(ctx context.Context, doc domain.TicketDocument, dir string, progress ProgressFunc) (string, error) {
+	return downloadToDir(doc, dir, func(dst io.Writer) error {
+		return c.DownloadDocumentContext(ctx, doc.ID, dst, progress)
+	})
+}
+
+// downloadToDir cria o arquivo local de doc em dir e grava nele com download (comum às duas APIs)
+func downloadToDir(doc domain.TicketDocument, dir string, download func(dst io.Writer) error) (string, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("erro ao criar pasta de download: %w", err)
+	}
+
+	name := filepath.Base(doc.Filename)
+	if name == "." || name == string(filepath.Separator) || name == "" {
+		name = fmt.Sprintf("documento-%d", doc.ID)
+	}
+	path, f, err := createUnique(dir, name)
+	if err != nil {
+		return "", fmt.Errorf("erro ao criar arquivo local: %w", err)
+	}
+
+	err = download(f)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path) // Não deixa arquivo pela metade
+		return "", err
+	}
+	return path, nil
+}
+
+// createUnique cria dir/name, ou dir/name (1).ext, dir/name (2).ext... se já existir
+func createUnique(dir, name string) (string, *os.File, error) {
+	ext := filepath.Ext(name)
+	base := strings.TrimSuffix(name, ext)
+	for i := 0; ; i++ {
+		candidate := name
+		if i > 0 {
+			candidate = fmt.Sprintf("%s (%d)%s", base, i, ext)
+		}
+		path := filepath.Join(dir, candidate)
+		f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o644)
+		if os.IsExist(err) {
+			continue
+		}
+		return path, f, err
+	}
+}
+
+// UploadDocument envia um arquivo local e o anexa ao chamado ou a um acompanhamento.
+// Endpoint: POST /Management/Document (multipart: "uploadManifest" com o JSON {"input": ...} + "filename[0]" com o arquivo)
+func (c *Client) UploadDocument(in UploadInput, progress ProgressFunc) error {
+	return c.UploadDocumentContext(context.Background(), in, progress)
+}
+
+// UploadDocumentContext é a variante de UploadDocument que respeita cancelamento e prazo do ctx
+func (c *Client) UploadDocumentContext(ctx context.Context, in UploadInput, progress ProgressFunc) error {
+	info, err := os.Stat(in.Path)
+	if err != nil {
+		return fmt.Errorf("arquivo inválido: %w", err)
+	}
+	if info.IsDir() {
+		return fmt.Errorf("%s é uma pasta, escolha um arquivo", in.Path)
+	}
+
+	itemType, itemID := "Ticket", in.TicketID
+	if in.FollowupID != 0 {
+		itemType, itemID = "ITILFollowup", in.FollowupID
+	}
+	manifest, err := json.Marshal(map[string]interface{}{
+		"input": map[string]interface{}{
+			"name":        filepath.Base(in.Path),
+			"entities_id": in.EntityID,
+			"itemtype":    itemType,
+			"items_id":    itemID,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("erro ao criar manifesto do upload: %w", err)
+	}
+
+	req, err := newUploadRequest(ctx, c.cfg.BaseURL+"/Management/Document", in.Path, info.Size(), manifest, progress)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("GLPI-Entity", fmt.Sprintf("%d", in.EntityID))
+
+	resp, err := c.doWith(c.transferHTTPClient(), req)
+	if err != nil {
+		return fmt.Errorf("erro de conexão ao enviar documento: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != 201 {
+		b, _ := io.ReadAll(resp.Body)
+		return &StatusError{What: "upload", StatusCode: resp.StatusCode, Body: string(b)}
+	}
+	return nil
+}
+
+// newUploadRequest monta o POST multipart do upload (manifesto + arquivo).
+// O corpo é gerado em streaming (sem carregar o arquivo na memória); GetBody
+// permite recriá-lo se o token precisar ser renovado no meio do caminho.
+func newUploadRequest(ctx context.Context, endpoint, path string, size int64, manifest []byte, progress ProgressFunc) (*http.Request, error) {
+	boundary := multipart.NewWriter(io.Discard).Boundary()
+	newBody := func() (io.ReadCloser, error) {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		pr, pw := io.Pipe()
+		go func() {
+			defer f.Close()
+			pw.CloseWithError(writeUploadMultipart(pw, boundary, manifest, filepath.Base(path),
+				&progressReader{r: f, total: size, progress: progress}))
+		}()
+		return pr, nil
+	}
+
+	body, err := newBody()
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir arquivo: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, body)
+	if err != nil {
+		body.Close()
+		return nil, fmt.Errorf("erro ao criar requisição de upload: %w", err)
+	}
+	req.GetBody = newBody
+	req.Header.Set("Content-Type", "multipart/form-data; boundary="+boundary)
+	req.Header.Set("Accept", "application/json")
+	return req, nil
+}
+
+func writeUploadMultipart(w io.Writer, boundary string, manifest []byte, filename string, file io.Reader) error {
+	mw := multipart.NewWriter(w)
+	if err := mw.SetBoundary(boundary); err != nil {
+		return err
+	}
+	if err := mw.WriteField("uploadManifest", string(manifest)); err != nil {
+		return err
+	}
+
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="filename[0]"; filename=%q`, filename))
+	ctype := mime.TypeByExtension(filepath.Ext(filename))
+	if ctype == "" {
+		ctype = "application/octet-stream"
+	}
+	h.Set("Content-Type", ctype)
+
+	part, err := mw.CreatePart(h)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(part, file); err != nil {
+		return err
+	}
+	return mw.Close()
+}
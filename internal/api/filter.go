@@ -0,0 +1,114 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// filterDateLayout é o formato de data aceito pelo filtro RSQL do GLPI
+const filterDateLayout = "2006-01-02 15:04:05"
+
+// TicketFilter descreve um filtro server-side para GET /Assistance/Ticket.
+// Campos com valor zero são ignorados; os critérios preenchidos são combinados com AND.
+type TicketFilter struct {
+	Statuses    []int // status.id em (...)
+	MinPriority int   // priority >= MinPriority (0 = ignora)
+	EntityID    *int  // entity.id == EntityID (nil = qualquer entidade)
+	AssignedTo  int   // Técnico atribuído (users_id); 0 = ignora
+	RequesterID int   // Requerente (users_id); 0 = ignora
+
+	AssignedGroups []int // Atribuído a algum destes grupos (groups_id)
+	Unassigned     bool  // Sem técnico nem grupo atribuído (fora do RSQL; veja unassignedPage)
+
+	OpenedAfter    time.Time // date >= OpenedAfter
+	OpenedBefore   time.Time // date <= OpenedBefore
+	ModifiedAfter  time.Time // date_mod >= ModifiedAfter
+	ModifiedBefore time.Time // date_mod <= ModifiedBefore
+
+	Text string // Contido no título ou na descrição (case-insensitive)
+}
+
+// IsZero indica se o filtro não restringe nada
+func (f TicketFilter) IsZero() bool {
+	return f.RSQL() == "" && !f.Unassigned
+}
+
+// RSQL serializa o filtro no formato aceito pelo parâmetro "filter" da API v2.
+// Ex: status.id=in=(1,2);priority=ge=4;name=ilike="*impressora*"
+// Unassigned não entra: o RSQL não expressa "nenhum ator atribuído" e o client filtra do seu lado.
+func (f TicketFilter) RSQL() string {
+	var parts []string
+
+	if len(f.Statuses) > 0 {
+		ids := make([]string, len(f.Statuses))
+		for i, s := range f.Statuses {
+			ids[i] = strconv.Itoa(s)
+		}
+		parts = append(parts, fmt.Sprintf("status.id=in=(%s)", strings.Join(ids, ",")))
+	}
+	if f.MinPriority > 0 {
+		parts = append(parts, fmt.Sprintf("priority=ge=%d", f.MinPriority))
+	}
+	if f.EntityID != nil {
+		parts = append(parts, fmt.Sprintf("entity.id==%d", *f.EntityID))
+	}
+	if f.AssignedTo > 0 {
+		parts = append(parts, teamMemberRSQL(f.AssignedTo, "assigned"))
+	}
+	if f.RequesterID > 0 {
+		parts = append(parts, teamMemberRSQL(f.RequesterID, "requester"))
+	}
+	if len(f.AssignedGroups) > 0 {
+		ids := make([]string, len(f.AssignedGroups))
+		for i, g := range f.AssignedGroups {
+			ids[i] = strconv.Itoa(g)
+		}
+		parts = append(parts, fmt.Sprintf("(team.type==Group;team.id=in=(%s);team.role==assigned)", strings.Join(ids, ",")))
+	}
+
+	parts = appendDateRSQL(parts, "date", f.OpenedAfter, f.OpenedBefore)
+	parts = appendDateRSQL(parts, "date_mod", f.ModifiedAfter, f.ModifiedBefore)
+
+	if text := strings.TrimSpace(f.Text); text != "" {
+		v := quoteRSQL("*" + text + "*")
+		parts = append(parts, fmt.Sprintf("(name=ilike=%s,content=ilike=%s)", v, v))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// teamMemberRSQL filtra pelos atores (TeamMember) do chamado. Supõe que as comparações entre
+// parênteses valem para um mesmo ator e que grupos diferentes podem casar com atores diferentes;
+// o glpimock segue a mesma suposição (veja rsqlE.casa).
+func teamMemberRSQL(userID int, role string) string {
+	return fmt.Sprintf("(team.type==User;team.id==%d;team.role==%s)", userID, role)
+}
+
+func appendDateRSQL(parts []string, field string, after, before time.Time) []string {
+	if !after.IsZero() {
+		parts = append(parts, fmt.Sprintf("%s=ge=%s", field, quoteRSQL(after.Format(filterDateLayout))))
+	}
+	if !before.IsZero() {
+		parts = append(parts, fmt.Sprintf("%s=le=%s", field, quoteRSQL(before.Format(filterDateLayout))))
+	}
+	return parts
+}
+
+// quoteRSQL envolve o valor em aspas, escapando aspas e barras invertidas
+func quoteRSQL(v string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+	return `"` + r.Replace(v) + `"`
+}
+
+// joinRSQL combina expressões RSQL com AND, ignorando as vazias
+func joinRSQL(exprs ...string) string {
+	var parts []string
+	for _, e := range exprs {
+		if e != "" {
+			parts = append(parts, e)
+		}
+	}
+	return strings.Join(parts, ";")
+}
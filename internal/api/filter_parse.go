@@ -0,0 +1,91 @@
+package api
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"glpi-tui/internal/domain"
+)
+
+// FilterHelp resume a sintaxe aceita por ParseFilter (barra de filtro da TUI e "list --filter")
+const FilterHelp = "texto livre • status:novo,pendente • prio:4 • ent:0 • eu • req:12 • desde:2024-01-31 • ate:… • alterado-desde:… • alterado-ate:…"
+
+// ParseFilter converte o texto de filtro digitado pelo usuário em um TicketFilter.
+// Tokens no formato chave:valor viram critérios tipados; o resto vira busca textual.
+func ParseFilter(texto string, myID int) (TicketFilter, error) {
+	var f TicketFilter
+	var livre []string
+
+	for _, tok := range strings.Fields(texto) {
+		key, val, isKV := strings.Cut(tok, ":")
+		if !isKV {
+			if strings.EqualFold(tok, "eu") {
+				if myID == 0 {
+					return f, fmt.Errorf("perfil do usuário ainda não carregado para usar \"eu\"")
+				}
+				f.AssignedTo = myID
+				continue
+			}
+			livre = append(livre, tok)
+			continue
+		}
+
+		var err error
+		switch strings.ToLower(key) {
+		case "status":
+			for _, s := range strings.Split(val, ",") {
+				id, err := domain.ParseStatus(s)
+				if err != nil {
+					return f, err
+				}
+				f.Statuses = append(f.Statuses, id)
+			}
+		case "prio":
+			if f.MinPriority, err = strconv.Atoi(strings.TrimPrefix(val, ">=")); err != nil {
+				return f, fmt.Errorf("prioridade inválida: %q", val)
+			}
+		case "ent":
+			id, err := strconv.Atoi(val)
+			if err != nil {
+				return f, fmt.Errorf("entidade inválida: %q", val)
+			}
+			f.EntityID = &id
+		case "req":
+			if f.RequesterID, err = strconv.Atoi(val); err != nil {
+				return f, fmt.Errorf("requerente inválido: %q", val)
+			}
+		case "desde":
+			f.OpenedAfter, err = parseFilterDate(val, false)
+		case "ate":
+			f.OpenedBefore, err = parseFilterDate(val, true)
+		case "alterado-desde":
+			f.ModifiedAfter, err = parseFilterDate(val, false)
+		case "alterado-ate":
+			f.ModifiedBefore, err = parseFilterDate(val, true)
+		default:
+			// Chave desconhecida: trata como texto (ex: "erro:404")
+			livre = append(livre, tok)
+		}
+		if err != nil {
+			return f, err
+		}
+	}
+
+	f.Text = strings.Join(livre, " ")
+	return f, nil
+}
+
+// parseFilterDate aceita AAAA-MM-DD ou DD/MM/AAAA. Com fimDoDia, a data cobre o dia inteiro.
+func parseFilterDate(v string, fimDoDia bool) (time.Time, error) {
+	for _, layout := range []string{"2006-01-02", "02/01/2006"} {
+		if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+			if fimDoDia {
+				t = t.Add(24*time.Hour - time.Second)
+			}
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("data inválida: %q (use AAAA-MM-DD)", v)
+}
@@ -0,0 +1,274 @@
+package api
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"strings"
+	"time"
+
+	"glpi-tui/internal/config"
+)
+
+// Fluxos OAuth2 interativos para GLPI atrás de SSO: authorization code com PKCE (o navegador
+// volta para um listener em 127.0.0.1) e device code (o usuário digita um código em outro
+// aparelho). Nos dois a senha nunca passa pelo programa; depois do primeiro login a sessão
+// segue com o refresh_token, guardado pelo config no backend de segredos.
+
+// prazoLoginInterativo limita a espera pelo usuário no navegador
+const prazoLoginInterativo = 5 * time.Minute
+
+// escopoOAuth são os escopos pedidos em todos os grants (mesmos do grant "password")
+const escopoOAuth = "api user"
+
+// loginInterativoLocked renova a sessão guardada ou, sem ela, chama o usuário.
+// Deve ser chamado com authMu travado.
+func (c *Client) loginInterativoLocked(ctx context.Context) error {
+	if c.RefreshToken != "" {
+		err := c.refreshGrantLocked(ctx)
+		if err == nil || IsTemporary(err) {
+			return err
+		}
+		c.RefreshToken = "" // Sessão recusada: só um login novo resolve
+	}
+	if c.Interact == nil {
+		return fmt.Errorf("é preciso entrar pelo navegador: rode glpi-tui auth login")
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, prazoLoginInterativo)
+	defer cancel()
+	if c.cfg.Auth == config.AuthDevice {
+		return c.deviceCodeLocked(ctx)
+	}
+	return c.authCodeLocked(ctx)
+}
+
+// authCodeLocked executa o authorization code com PKCE (RFC 7636) e redirect de loopback (RFC 8252)
+func (c *Client) authCodeLocked(ctx context.Context) error {
+	verifier := aleatorio(32)
+	desafio := sha256.Sum256([]byte(verifier))
+	state := aleatorio(16)
+
+	ln, err := net.Listen("tcp", fmt.Sprintf("127.0.0.1:%d", c.cfg.RedirectPort))
+	if err != nil {
+		return fmt.Errorf("erro ao abrir a porta local do retorno do navegador: %w", err)
+	}
+	redirect := fmt.Sprintf("http://127.0.0.1:%d/callback", ln.Addr().(*net.TCPAddr).Port)
+
+	type retorno struct {
+		code string
+		err  error
+	}
+	ch := make(chan retorno, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc("/callback", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("state") != state {
+			// Não é o retorno desta autorização (aba antiga, outra página batendo na porta):
+			// recusa e continua esperando o verdadeiro
+			http.Error(w, "state inválido", http.StatusBadRequest)
+			return
+		}
+		var ret retorno
+		switch {
+		case q.Get("error") != "":
+			ret.err = fmt.Errorf("autorização negada: %s %s", q.Get("error"), q.Get("error_description"))
+		case q.Get("code") == "":
+			ret.err = fmt.Errorf("retorno do navegador sem code")
+		default:
+			ret.code = q.Get("code")
+		}
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if ret.err != nil {
+			// error e error_description vêm da query string: qualquer página pode montá-los
+			fmt.Fprintf(w, "<p>Falha no login do glpi-tui: %s</p>", html.EscapeString(ret.err.Error()))
+		} else {
+			fmt.Fprint(w, "<p>Login concluído. Pode fechar esta aba e voltar ao terminal.</p>")
+		}
+		select {
+		case ch <- ret:
+		default: // Só o primeiro retorno conta
+		}
+	})
+	srv := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go srv.Serve(ln)
+	defer srv.Close()
+
+	autorizacao := c.cfg.AuthorizeURL + "?" + url.Values{
+		"response_type":         {"code"},
+		"client_id":             {c.cfg.ClientID},
+		"redirect_uri":          {redirect},
+		"scope":                 {escopoOAuth},
+		"state":                 {state},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(desafio[:])},
+		"code_challenge_method": {"S256"},
+	}.Encode()
+	c.Interact("Abra no navegador para entrar no GLPI (aguardando o retorno):\n" + autorizacao)
+	abrirNavegador(autorizacao)
+
+	var ret retorno
+	select {
+	case ret = <-ch:
+	case <-ctx.Done():
+		return fmt.Errorf("login pelo navegador não concluído: %w", ctx.Err())
+	}
+	if ret.err != nil {
+		return ret.err
+	}
+
+	return c.requestTokenLocked(ctx, c.comSecret(map[string]string{
+		"grant_type":    "authorization_code",
+		"client_id":     c.cfg.ClientID,
+		"code":          ret.code,
+		"redirect_uri":  redirect,
+		"code_verifier": verifier,
+	}))
+}
+
+// deviceAuthResponse mapeia a resposta do endpoint de device authorization (RFC 8628)
+type deviceAuthResponse struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// deviceCodeLocked executa o device authorization grant: mostra o código e consulta o /token
+// no intervalo pedido pelo servidor até o usuário aprovar
+func (c *Client) deviceCodeLocked(ctx context.Context) error {
+	var d deviceAuthResponse
+	payload := c.comSecret(map[string]string{"client_id": c.cfg.ClientID, "scope": escopoOAuth})
+	if err := c.postSemToken(ctx, c.cfg.DeviceURL, payload, &d, "device authorization"); err != nil {
+		return err
+	}
+	if d.DeviceCode == "" || d.UserCode == "" {
+		return fmt.Errorf("resposta de device authorization sem device_code/user_code")
+	}
+
+	instrucao := fmt.Sprintf("Para entrar no GLPI, acesse %s e digite o código %s", d.VerificationURI, d.UserCode)
+	if d.VerificationURIComplete != "" {
+		instrucao += "\n(ou abra direto: " + d.VerificationURIComplete + ")"
+	}
+	c.Interact(instrucao)
+
+	if d.ExpiresIn > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, time.Duration(d.ExpiresIn)*time.Second)
+		defer cancel()
+	}
+	intervalo := time.Duration(max(d.Interval, 5)) * time.Second
+	for {
+		select {
+		case <-time.After(intervalo):
+		case <-ctx.Done():
+			return fmt.Errorf("código não confirmado a tempo: %w", ctx.Err())
+		}
+
+		err := c.requestTokenLocked(ctx, c.comSecret(map[string]string{
+			"grant_type":  "urn:ietf:params:oauth:grant-type:device_code",
+			"client_id":   c.cfg.ClientID,
+			"device_code": d.DeviceCode,
+		}))
+		switch erroOAuth(err) {
+		case "":
+			return err
+		case "authorization_pending":
+		case "slow_down":
+			intervalo += 5 * time.Second
+		case "access_denied":
+			return fmt.Errorf("login recusado no outro aparelho")
+		case "expired_token":
+			return fmt.Errorf("o código expirou antes da confirmação; tente de novo")
+		default:
+			return err
+		}
+	}
+}
+
+// postSemToken faz um POST fora da sessão (antes do login existir). O corpo vai como formulário
+// (application/x-www-form-urlencoded), como a RFC 8628 §3.1 exige no device authorization.
+func (c *Client) postSemToken(ctx context.Context, endpoint string, payload map[string]string, out any, what string) error {
+	form := url.Values{}
+	for k, v := range payload {
+		form.Set(k, v)
+	}
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição de %s: %w", what, err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("erro de conexão com GLPI: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return &StatusError{What: what, StatusCode: resp.StatusCode, Body: string(body)}
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("erro ao decodificar %s: %w", what, err)
+	}
+	return nil
+}
+
+// comSecret acrescenta o client_secret quando o cliente OAuth tem um (clientes públicos não têm)
+func (c *Client) comSecret(payload map[string]string) map[string]string {
+	if c.cfg.ClientSecret != "" {
+		payload["client_secret"] = c.cfg.ClientSecret
+	}
+	return payload
+}
+
+// erroOAuth extrai o campo "error" de uma recusa do /token ("" se err não for uma recusa)
+func erroOAuth(err error) string {
+	var se *StatusError
+	if !errors.As(err, &se) || se.StatusCode != http.StatusBadRequest {
+		return ""
+	}
+	var corpo struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal([]byte(se.Body), &corpo) != nil {
+		return ""
+	}
+	return corpo.Error
+}
+
+// aleatorio gera n bytes aleatórios em base64url (verifier do PKCE e state)
+func aleatorio(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// abrirNavegador tenta abrir a URL no navegador padrão; se não der, o usuário copia do terminal
+func abrirNavegador(u string) {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", u)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", u)
+	default:
+		cmd = exec.Command("xdg-open", u)
+	}
+	if cmd.Start() == nil {
+		go cmd.Wait()
+	}
+}
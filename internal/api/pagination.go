@@ -0,0 +1,113 @@
+package api
+
+import (
+	"strconv"
+	"strings"
+
+	"glpi-tui/internal/domain"
+)
+
+// DefaultPageSize é o tamanho de página usado quando TicketQuery.Limit não é informado
+const DefaultPageSize = 20
+
+// TicketQuery descreve qual fatia da lista de chamados buscar
+type TicketQuery struct {
+	Start int    // Offset do primeiro item (0 = início)
+	Limit int    // Quantidade de itens (0 = DefaultPageSize)
+	Sort  string // Formato property:direction (vazio = date_mod:desc)
+
+	Scope  TicketFilter // Recorte fixo da consulta (ex: fila "Meus chamados")
+	Filter TicketFilter // Filtro digitado pelo usuário; combinado com Scope via AND
+}
+
+// rsql devolve o parâmetro "filter" da consulta (vazio = sem filtro)
+func (q TicketQuery) rsql() string {
+	return joinRSQL(q.Scope.RSQL(), q.Filter.RSQL())
+}
+
+// unassigned indica se a consulta só quer chamados sem ninguém atribuído
+func (q TicketQuery) unassigned() bool {
+	return q.Scope.Unassigned || q.Filter.Unassigned
+}
+
+func (q TicketQuery) withDefaults() TicketQuery {
+	if q.Start < 0 {
+		q.Start = 0
+	}
+	if q.Limit <= 0 {
+		q.Limit = DefaultPageSize
+	}
+	if q.Sort == "" {
+		q.Sort = "date_mod:desc"
+	}
+	return q
+}
+
+// TicketPage é uma página de chamados junto com a posição dela no total do servidor
+type TicketPage struct {
+	Tickets []domain.Chamado
+	Start   int // Offset do primeiro item da página
+	Total   int // Total de itens no servidor (-1 quando o servidor não informa)
+}
+
+// Next devolve o offset da próxima página
+func (p TicketPage) Next() int { return p.Start + len(p.Tickets) }
+
+// HasMore indica se ainda existem itens depois desta página
+func (p TicketPage) HasMore() bool {
+	if p.Total < 0 {
+		// Sem Content-Range: só sabemos que acabou quando vier uma página vazia
+		return len(p.Tickets) > 0
+	}
+	return p.Next() < p.Total
+}
+
+func newTicketPage(tickets []domain.Chamado, start int, contentRange string) TicketPage {
+	page := TicketPage{Tickets: tickets, Start: start, Total: -1}
+	if first, last, total, ok := parseContentRange(contentRange); ok {
+		page.Total = total
+		if last >= first {
+			page.Start = first
+		}
+	}
+	return page
+}
+
+// parseContentRange interpreta o header Content-Range devolvido nas respostas 206.
+// Aceita tanto "0-19/1432" quanto "items 0-19/1432"; total "*" vira -1.
+func parseContentRange(h string) (first, last, total int, ok bool) {
+	h = strings.TrimSpace(h)
+	if i := strings.LastIndex(h, " "); i >= 0 {
+		h = h[i+1:]
+	}
+
+	rangePart, totalPart, found := strings.Cut(h, "/")
+	if !found {
+		return 0, 0, 0, false
+	}
+
+	total = -1
+	if totalPart != "*" {
+		t, err := strconv.Atoi(totalPart)
+		if err != nil {
+			return 0, 0, 0, false
+		}
+		total = t
+	}
+
+	// Página vazia: alguns servidores mandam "*/1432"
+	if rangePart == "*" {
+		return 0, -1, total, true
+	}
+
+	a, b, found := strings.Cut(rangePart, "-")
+	if !found {
+		return 0, 0, 0, false
+	}
+	first, err1 := strconv.Atoi(a)
+	last, err2 := strconv.Atoi(b)
+	if err1 != nil || err2 != nil {
+		return 0, 0, 0, false
+	}
+	return first, last, total, true
+}
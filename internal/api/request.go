@@ -0,0 +1,141 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+)
+
+// StatusError é uma resposta de erro (fora de 2xx) da API. Permite ao chamador separar
+// falhas definitivas (dados recusados) das temporárias que valem uma nova tentativa.
+type StatusError struct {
+	What       string // Operação, no mesmo formato das demais mensagens ("criar followup")
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("erro API %s (HTTP %d): %s", e.What, e.StatusCode, e.Body)
+}
+
+// Temporary diz se o servidor recusou por um problema passageiro (sobrecarga, manutenção, limite de taxa)
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode >= 500 || e.StatusCode == http.StatusRequestTimeout || e.StatusCode == http.StatusTooManyRequests
+}
+
+// IsTemporary diz se vale repetir a operação mais tarde: falhas de rede, prazos estourados e
+// respostas temporárias do servidor. Erros de validação e HTTP 4xx são definitivos.
+func IsTemporary(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return se.Temporary()
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var ne net.Error
+	if errors.As(err, &ne) {
+		return true
+	}
+	var ue *url.Error
+	return errors.As(err, &ue)
+}
+
+// IsAmbiguous diz se a requisição pode ter chegado ao servidor apesar do erro: prazo estourado
+// ou conexão caída depois do envio. Respostas HTTP e falhas ao conectar não são ambíguas.
+func IsAmbiguous(err error) bool {
+	var se *StatusError
+	if errors.As(err, &se) {
+		return false
+	}
+	var oe *net.OpError
+	if errors.As(err, &oe) && oe.Op == "dial" {
+		return false
+	}
+	var de *net.DNSError
+	if errors.As(err, &de) {
+		return false
+	}
+	return IsTemporary(err)
+}
+
+// getJSON faz um GET autenticado e decodifica a resposta em out.
+// "what" entra nas mensagens de erro (ex: "soluções") para manter o padrão dos demais métodos.
+func (c *Client) getJSON(ctx context.Context, endpoint string, query url.Values, out interface{}, what string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("erro parsing url de %s: %w", what, err)
+	}
+	if len(query) > 0 {
+		u.RawQuery = query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("erro ao criar req de %s: %w", what, err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("erro de conexão ao buscar %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != 206 {
+		body, _ := io.ReadAll(resp.Body)
+		return &StatusError{What: what, StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("erro de decode de %s: %w", what, err)
+	}
+	return nil
+}
+
+// sendJSON envia payload como JSON (POST/PATCH/DELETE autenticado) e, se out não for nil,
+// decodifica a resposta nele. entityID >= 0 define o header GLPI-Entity.
+func (c *Client) sendJSON(ctx context.Context, method, endpoint string, entityID int, payload, out interface{}, what string) error {
+	var body io.Reader
+	if payload != nil {
+		jsonPayload, err := json.Marshal(payload)
+		if err != nil {
+			return fmt.Errorf("erro ao criar payload de %s: %w", what, err)
+		}
+		body = bytes.NewBuffer(jsonPayload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição de %s: %w", what, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	if entityID >= 0 {
+		req.Header.Set("GLPI-Entity", fmt.Sprintf("%d", entityID))
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("erro de conexão ao %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		b, _ := io.ReadAll(resp.Body)
+		return &StatusError{What: what, StatusCode: resp.StatusCode, Body: string(b)}
+	}
+
+	if out != nil && resp.StatusCode != http.StatusNoContent {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("erro de decode de %s: %w", what, err)
+		}
+	}
+	return nil
+}
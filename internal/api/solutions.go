@@ -0,0 +1,144 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+
+	"glpi-tui/internal/domain"
+)
+
+// SolutionInput reúne os campos de uma nova solução
+type SolutionInput struct {
+	Content        string // Texto puro; convertido para HTML no envio
+	SolutionTypeID int    // 0 = sem tipo
+}
+
+type solutionPayload struct {
+	Content        string `json:"content"`
+	SolutionTypeID int    `json:"solutiontypes_id,omitempty"`
+	ItemsID        int    `json:"items_id"`
+	ItemType       string `json:"itemtype"`
+}
+
+// GetTicketSolutions busca as soluções registradas no chamado.
+// Endpoint: GET /Assistance/Ticket/{id}/Timeline/Solution
+func (c *Client) GetTicketSolutions(ticketID int) ([]domain.TicketSolution, error) {
+	return c.GetTicketSolutionsContext(context.Background(), ticketID)
+}
+
+// GetTicketSolutionsContext é a variante de GetTicketSolutions que respeita cancelamento e prazo do ctx
+func (c *Client) GetTicketSolutionsContext(ctx context.Context, ticketID int) ([]domain.TicketSolution, error) {
+	endpoint := fmt.Sprintf("%s/Assistance/Ticket/%d/Timeline/Solution", c.cfg.BaseURL, ticketID)
+
+	q := url.Values{}
+	q.Set("expand_dropdowns", "true")
+
+	// Mesmo envelope {type, item} da timeline de acompanhamentos
+	var raw []struct {
+		Type string                `json:"type"`
+		Item domain.TicketSolution `json:"item"`
+	}
+	if err := c.getJSON(ctx, endpoint, q, &raw, "soluções"); err != nil {
+		return nil, err
+	}
+
+	solutions := make([]domain.TicketSolution, 0, len(raw))
+	for _, w := range raw {
+		solutions = append(solutions, w.Item)
+	}
+	return solutions, nil
+}
+
+// CreateTicketSolution registra uma solução no chamado (o GLPI muda o status para Solucionado).
+// Endpoint: POST /Assistance/Ticket/{id}/Timeline/Solution
+func (c *Client) CreateTicketSolution(ticketID, entityID int, in SolutionInput) error {
+	return c.CreateTicketSolutionContext(context.Background(), ticketID, entityID, in)
+}
+
+// CreateTicketSolutionContext é a variante de CreateTicketSolution que respeita cancelamento e prazo do ctx
+func (c *Client) CreateTicketSolutionContext(ctx context.Context, ticketID, entityID int, in SolutionInput) error {
+	if in.Content == "" {
+		return fmt.Errorf("a solução precisa de uma descrição")
+	}
+
+	endpoint := fmt.Sprintf("%s/Assistance/Ticket/%d/Timeline/Solution", c.cfg.BaseURL, ticketID)
+	payload := solutionPayload{
+		Content:        plainTextToHTML(in.Content),
+		SolutionTypeID: in.SolutionTypeID,
+		ItemsID:        ticketID,
+		ItemType:       "Ticket",
+	}
+	return c.sendJSON(ctx, "POST", endpoint, entityID, payload, nil, "criar solução")
+}
+
+// ApproveTicketSolution aceita a solução pendente, o que fecha o chamado.
+// Endpoint: PATCH /Assistance/Ticket/{id}/Timeline/Solution/{solution_id}
+func (c *Client) ApproveTicketSolution(ticketID, entityID, solutionID int) error {
+	return c.ApproveTicketSolutionContext(context.Background(), ticketID, entityID, solutionID)
+}
+
+// ApproveTicketSolutionContext é a variante de ApproveTicketSolution que respeita cancelamento e prazo do ctx
+func (c *Client) ApproveTicketSolutionContext(ctx context.Context, ticketID, entityID, solutionID int) error {
+	return c.setSolutionStatus(ctx, ticketID, entityID, solutionID, domain.SolutionStatusAccepted)
+}
+
+// RefuseTicketSolution recusa a solução pendente, reabrindo o chamado.
+// Se reason for informado, ele é registrado como acompanhamento explicando a recusa.
+func (c *Client) RefuseTicketSolution(ticketID, entityID, solutionID int, reason string) error {
+	return c.RefuseTicketSolutionContext(context.Background(), ticketID, entityID, solutionID, reason)
+}
+
+// RefuseTicketSolutionContext é a variante de RefuseTicketSolution que respeita cancelamento e prazo do ctx
+func (c *Client) RefuseTicketSolutionContext(ctx context.Context, ticketID, entityID, solutionID int, reason string) error {
+	if err := c.setSolutionStatus(ctx, ticketID, entityID, solutionID, domain.SolutionStatusRefused); err != nil {
+		return err
+	}
+	if reason == "" {
+		return nil
+	}
+	return c.CreateTicketFollowupContext(ctx, ticketID, reason)
+}
+
+func (c *Client) setSolutionStatus(ctx context.Context, ticketID, entityID, solutionID, status int) error {
+	endpoint := fmt.Sprintf("%s/Assistance/Ticket/%d/Timeline/Solution/%d", c.cfg.BaseURL, ticketID, solutionID)
+	payload := map[string]interface{}{
+		"input": map[string]interface{}{
+			"status": status,
+		},
+	}
+	return c.sendJSON(ctx, "PATCH", endpoint, entityID, payload, nil, "atualizar solução")
+}
+
+// GetSolutionTypes lista os tipos de solução disponíveis.
+// Endpoint: GET /Dropdowns/SolutionType
+func (c *Client) GetSolutionTypes() ([]domain.SolutionType, error) {
+	return c.GetSolutionTypesContext(context.Background())
+}
+
+// GetSolutionTypesContext é a variante de GetSolutionTypes que respeita cancelamento e prazo do ctx
+func (c *Client) GetSolutionTypesContext(ctx context.Context) ([]domain.SolutionType, error) {
+	var types []domain.SolutionType
+	if err := c.getJSON(ctx, c.cfg.BaseURL+"/Dropdowns/SolutionType", nil, &types, "tipos de solução"); err != nil {
+		return nil, err
+	}
+	return types, nil
+}
+
+// GetSolutionTemplates lista os modelos de solução disponíveis.
+// Endpoint: GET /Dropdowns/SolutionTemplate
+func (c *Client) GetSolutionTemplates() ([]domain.SolutionTemplate, error) {
+	return c.GetSolutionTemplatesContext(context.Background())
+}
+
+// GetSolutionTemplatesContext é a variante de GetSolutionTemplates que respeita cancelamento e prazo do ctx
+func (c *Client) GetSolutionTemplatesContext(ctx context.Context) ([]domain.SolutionTemplate, error) {
+	q := url.Values{}
+	q.Set("expand_dropdowns", "true")
+
+	var templates []domain.SolutionTemplate
+	if err := c.getJSON(ctx, c.cfg.BaseURL+"/Dropdowns/SolutionTemplate", q, &templates, "modelos de solução"); err != nil {
+		return nil, err
+	}
+	return templates, nil
+}
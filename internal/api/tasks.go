@@ -0,0 +1,114 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"time"
+
+	"glpi-tui/internal/domain"
+)
+
+// taskDateLayout é o formato de data usado nos campos de planejamento da tarefa
+const taskDateLayout = "2006-01-02 15:04:05"
+
+// TaskInput reúne os campos de uma nova tarefa
+type TaskInput struct {
+	Content      string        // Texto puro; convertido para HTML no envio
+	ActionTime   time.Duration // Tempo gasto
+	State        int           // domain.TaskStateTodo / domain.TaskStateDone / domain.TaskStateInformation
+	TechnicianID int           // 0 = usuário autenticado
+	BeginPlan    time.Time     // Zero = sem planejamento
+	EndPlan      time.Time
+}
+
+// Validate confere os campos antes de enviar
+func (in TaskInput) Validate() error {
+	if in.Content == "" {
+		return fmt.Errorf("a descrição da tarefa é obrigatória")
+	}
+	if in.ActionTime < 0 {
+		return fmt.Errorf("o tempo gasto não pode ser negativo")
+	}
+	if in.State != domain.TaskStateInformation && in.State != domain.TaskStateTodo && in.State != domain.TaskStateDone {
+		return fmt.Errorf("estado inválido: %d", in.State)
+	}
+	if in.BeginPlan.IsZero() != in.EndPlan.IsZero() {
+		return fmt.Errorf("informe início e fim do planejamento, ou nenhum dos dois")
+	}
+	if !in.BeginPlan.IsZero() && !in.EndPlan.After(in.BeginPlan) {
+		return fmt.Errorf("o fim planejado deve ser depois do início")
+	}
+	return nil
+}
+
+type taskPayload struct {
+	Content      string `json:"content"`
+	ActionTime   int    `json:"actiontime"`
+	State        int    `json:"state"`
+	TechnicianID int    `json:"users_id_tech,omitempty"`
+	Begin        string `json:"begin,omitempty"`
+	End          string `json:"end,omitempty"`
+	ItemsID      int    `json:"tickets_id"`
+}
+
+// GetTicketTasks busca as tarefas do chamado.
+// Endpoint: GET /Assistance/Ticket/{id}/Timeline/Task
+func (c *Client) GetTicketTasks(ticketID int) ([]domain.TicketTask, error) {
+	return c.GetTicketTasksContext(context.Background(), ticketID)
+}
+
+// GetTicketTasksContext é a variante de GetTicketTasks que respeita cancelamento e prazo do ctx
+func (c *Client) GetTicketTasksContext(ctx context.Context, ticketID int) ([]domain.TicketTask, error) {
+	endpoint := fmt.Sprintf("%s/Assistance/Ticket/%d/Timeline/Task", c.cfg.BaseURL, ticketID)
+
+	q := url.Values{}
+	q.Set("expand_dropdowns", "true")
+
+	// Mesmo envelope {type, item} da timeline de acompanhamentos
+	var raw []struct {
+		Type string            `json:"type"`
+		Item domain.TicketTask `json:"item"`
+	}
+	if err := c.getJSON(ctx, endpoint, q, &raw, "tarefas"); err != nil {
+		return nil, err
+	}
+
+	tasks := make([]domain.TicketTask, 0, len(raw))
+	for _, w := range raw {
+		tasks = append(tasks, w.Item)
+	}
+	return tasks, nil
+}
+
+// CreateTicketTask registra uma tarefa (com tempo gasto) no chamado.
+// Endpoint: POST /Assistance/Ticket/{id}/Timeline/Task
+func (c *Client) CreateTicketTask(ticketID, entityID int, in TaskInput) error {
+	return c.CreateTicketTaskContext(context.Background(), ticketID, entityID, in)
+}
+
+// CreateTicketTaskContext é a variante de CreateTicketTask que respeita cancelamento e prazo do ctx
+func (c *Client) CreateTicketTaskContext(ctx context.Context, ticketID, entityID int, in TaskInput) error {
+	if err := in.Validate(); err != nil {
+		return fmt.Errorf("tarefa inválida: %w", err)
+	}
+
+	endpoint := fmt.Sprintf("%s/Assistance/Ticket/%d/Timeline/Task", c.cfg.BaseURL, ticketID)
+
+	tech := in.TechnicianID
+	if tech == 0 {
+		tech = c.UserID
+	}
+	payload := taskPayload{
+		Content:      plainTextToHTML(in.Content),
+		ActionTime:   int(in.ActionTime.Seconds()),
+		State:        in.State,
+		TechnicianID: tech,
+		ItemsID:      ticketID,
+	}
+	if !in.BeginPlan.IsZero() {
+		payload.Begin = in.BeginPlan.Format(taskDateLayout)
+		payload.End = in.EndPlan.Format(taskDateLayout)
+	}
+	return c.sendJSON(ctx, "POST", endpoint, entityID, payload, nil, "criar tarefa")
+}
@@ -0,0 +1,162 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"strings"
+	"unicode/utf8"
+
+	"glpi-tui/internal/domain"
+)
+
+// TicketInput reúne os campos de abertura de um chamado
+type TicketInput struct {
+	Name         string // Título (obrigatório)
+	Content      string // Descrição em texto puro (obrigatória); convertida para HTML no envio
+	Type         int    // domain.TypeIncident ou domain.TypeRequest
+	Urgency      int    // 1 (muito baixa) a 5 (muito alta)
+	CategoryID   int    // itilcategories_id; 0 = sem categoria
+	EntityID     int    // entities_id; 0 = entidade raiz
+	RequesterIDs []int  // users_id dos requerentes; vazio = o próprio usuário autenticado
+	ObserverIDs  []int  // users_id dos observadores
+}
+
+// Validate confere os campos antes de enviar, para o erro aparecer no formulário e não na API
+func (in TicketInput) Validate() error {
+	if strings.TrimSpace(in.Name) == "" {
+		return fmt.Errorf("o título é obrigatório")
+	}
+	if utf8.RuneCountInString(in.Name) > 255 { // Caracteres, como o CharLimit do formulário
+		return fmt.Errorf("o título deve ter no máximo 255 caracteres")
+	}
+	if strings.TrimSpace(in.Content) == "" {
+		return fmt.Errorf("a descrição é obrigatória")
+	}
+	if in.Type != domain.TypeIncident && in.Type != domain.TypeRequest {
+		return fmt.Errorf("tipo inválido: %d", in.Type)
+	}
+	if in.Urgency < 1 || in.Urgency > 5 {
+		return fmt.Errorf("urgência deve estar entre 1 e 5")
+	}
+	if in.CategoryID < 0 || in.EntityID < 0 {
+		return fmt.Errorf("categoria e entidade não podem ser negativas")
+	}
+	for _, id := range append(append([]int{}, in.RequesterIDs...), in.ObserverIDs...) {
+		if id <= 0 {
+			return fmt.Errorf("ID de usuário inválido: %d", id)
+		}
+	}
+	return nil
+}
+
+// ticketCreatePayload é o corpo do POST /Assistance/Ticket.
+// Os campos "_users_id_*" são os mesmos aceitos pelo formulário do GLPI para criar os atores junto com o chamado.
+type ticketCreatePayload struct {
+	Name         string `json:"name"`
+	Content      string `json:"content"`
+	Type         int    `json:"type"`
+	Urgency      int    `json:"urgency"`
+	CategoryID   int    `json:"itilcategories_id,omitempty"`
+	EntityID     int    `json:"entities_id"`
+	RequesterIDs []int  `json:"_users_id_requester,omitempty"`
+	ObserverIDs  []int  `json:"_users_id_observer,omitempty"`
+}
+
+// createdResponse é o corpo devolvido pelo GLPI ao criar um item
+type createdResponse struct {
+	ID   int    `json:"id"`
+	Href string `json:"href"`
+}
+
+// GetTicket busca um único chamado pelo ID.
+// Endpoint: GET /Assistance/Ticket/{id}
+func (c *Client) GetTicket(ticketID int) (domain.Chamado, error) {
+	return c.GetTicketContext(context.Background(), ticketID)
+}
+
+// GetTicketContext é a variante de GetTicket que respeita cancelamento e prazo do ctx
+func (c *Client) GetTicketContext(ctx context.Context, ticketID int) (domain.Chamado, error) {
+	var t domain.Chamado
+	endpoint := fmt.Sprintf("%s/Assistance/Ticket/%d", c.cfg.BaseURL, ticketID)
+	if err := c.getJSON(ctx, endpoint, nil, &t, "chamado"); err != nil {
+		return domain.Chamado{}, err
+	}
+	return t, nil
+}
+
+// CreateTicket abre um novo chamado e devolve o ID gerado.
+// Endpoint: POST /Assistance/Ticket
+func (c *Client) CreateTicket(in TicketInput) (int, error) {
+	return c.CreateTicketContext(context.Background(), in)
+}
+
+// CreateTicketContext é a variante de CreateTicket que respeita cancelamento e prazo do ctx
+func (c *Client) CreateTicketContext(ctx context.Context, in TicketInput) (int, error) {
+	if err := in.Validate(); err != nil {
+		return 0, fmt.Errorf("chamado inválido: %w", err)
+	}
+
+	endpoint := c.cfg.BaseURL + "/Assistance/Ticket"
+
+	payload := ticketCreatePayload{
+		Name:         strings.TrimSpace(in.Name),
+		Content:      plainTextToHTML(in.Content),
+		Type:         in.Type,
+		Urgency:      in.Urgency,
+		CategoryID:   in.CategoryID,
+		EntityID:     in.EntityID,
+		RequesterIDs: in.RequesterIDs,
+		ObserverIDs:  in.ObserverIDs,
+	}
+
+	jsonPayload, err := json.Marshal(payload)
+	if err != nil {
+		return 0, fmt.Errorf("erro ao criar payload do chamado: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewBuffer(jsonPayload))
+	if err != nil {
+		return 0, fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	// Cria o chamado no contexto da entidade escolhida
+	req.Header.Set("GLPI-Entity", fmt.Sprintf("%d", in.EntityID))
+
+	resp, err := c.do(req)
+	if err != nil {
+		return 0, fmt.Errorf("erro de conexão ao criar chamado: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != 201 {
+		body, _ := io.ReadAll(resp.Body)
+		return 0, &StatusError{What: "criar chamado", StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	var created createdResponse
+	if err := json.NewDecoder(resp.Body).Decode(&created); err != nil {
+		return 0, fmt.Errorf("erro decode do chamado criado: %w", err)
+	}
+
+	return created.ID, nil
+}
+
+// plainTextToHTML escapa o texto e preserva as quebras de linha como parágrafos/<br>
+func plainTextToHTML(text string) string {
+	var sb strings.Builder
+	for _, para := range strings.Split(strings.TrimSpace(text), "\n\n") {
+		lines := strings.Split(strings.TrimSpace(para), "\n")
+		for i, l := range lines {
+			lines[i] = html.EscapeString(l)
+		}
+		sb.WriteString("<p>" + strings.Join(lines, "<br>") + "</p>")
+	}
+	return sb.String()
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+
+	"glpi-tui/internal/domain"
+)
+
+// GetTicketTimeline busca a timeline completa do chamado (acompanhamentos, tarefas,
+// soluções, documentos e validações) e decodifica cada item no tipo correspondente.
+// Endpoint: GET /Assistance/Ticket/{id}/Timeline
+func (c *Client) GetTicketTimeline(ticketID int) ([]domain.TimelineItem, error) {
+	return c.GetTicketTimelineContext(context.Background(), ticketID)
+}
+
+// GetTicketTimelineContext é a variante de GetTicketTimeline que respeita cancelamento e prazo do ctx
+func (c *Client) GetTicketTimelineContext(ctx context.Context, ticketID int) ([]domain.TimelineItem, error) {
+	endpoint := fmt.Sprintf("%s/Assistance/Ticket/%d/Timeline", c.cfg.BaseURL, ticketID)
+
+	q := url.Values{}
+	q.Set("expand_dropdowns", "true")
+
+	// O item só pode ser decodificado depois de saber o "type" do envelope
+	var raw []struct {
+		Type string          `json:"type"`
+		Item json.RawMessage `json:"item"`
+	}
+	if err := c.getJSON(ctx, endpoint, q, &raw, "timeline"); err != nil {
+		return nil, err
+	}
+
+	// Um item que não decodifica (ex: campo com outro tipo nesta versão do GLPI) é pulado como
+	// os tipos desconhecidos, para não perder o resto do histórico
+	items := make([]domain.TimelineItem, 0, len(raw))
+	for _, w := range raw {
+		if item, ok, err := decodeTimelineItem(w.Type, w.Item); err == nil && ok {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+// decodeTimelineItem monta a união tipada; tipos desconhecidos são ignorados (ok = false)
+func decodeTimelineItem(rawType string, data json.RawMessage) (domain.TimelineItem, bool, error) {
+	t, ok := domain.ParseTimelineType(rawType)
+	if !ok {
+		return domain.TimelineItem{}, false, nil
+	}
+
+	item := domain.TimelineItem{Type: t}
+	var target interface{}
+	switch t {
+	case domain.TimelineFollowup:
+		item.Followup = &domain.TicketFollowup{}
+		target = item.Followup
+	case domain.TimelineTask:
+		item.Task = &domain.TicketTask{}
+		target = item.Task
+	case domain.TimelineSolution:
+		item.Solution = &domain.TicketSolution{}
+		target = item.Solution
+	case domain.TimelineDocument:
+		item.Document = &domain.TicketDocument{}
+		target = item.Document
+	case domain.TimelineValidation:
+		item.Validation = &domain.TicketValidation{}
+		target = item.Validation
+	}
+
+	if err := json.Unmarshal(data, target); err != nil {
+		return domain.TimelineItem{}, false, err
+	}
+	return item, true, nil
+}
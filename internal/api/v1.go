@@ -0,0 +1,396 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"glpi-tui/internal/config"
+)
+
+// v1Range é o intervalo pedido nas listas de sub-itens (sem ele a v1 devolve só os 50 primeiros)
+const v1Range = "0-999"
+
+// V1Client fala com a API REST legada do GLPI (apirest.php), a única disponível nos servidores
+// 9.x e ainda a padrão no 10.x. A sessão é aberta no initSession com usuário e senha (Basic auth)
+// e o App-Token do perfil, e segue no header Session-Token. A v1 devolve só IDs nos itens
+// relacionados: os nomes de usuários, grupos e entidades são buscados à parte e guardados.
+type V1Client struct {
+	cfg          *config.Config
+	HTTPClient   *http.Client
+	SessionToken string
+	UserID       int   // Preenchido por GetMyID
+	GroupIDs     []int // Preenchido por GetMyGroups
+
+	// authMu protege SessionToken, já que os comandos da TUI rodam em goroutines
+	authMu sync.Mutex
+
+	nomesMu   sync.Mutex
+	nomes     map[string]string // "User/12" -> "Maria Souza"
+	entidades map[string]int    // Nome completo -> ID; nil até a primeira consulta
+}
+
+// v1Int aceita número, texto numérico ou vazio: conforme a versão do GLPI e do driver do banco,
+// a v1 devolve os IDs como número ou como string
+type v1Int int
+
+func (n *v1Int) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	switch s {
+	case "", "null", "false":
+		*n = 0
+		return nil
+	case "true":
+		*n = 1
+		return nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("número inválido: %s", b)
+	}
+	*n = v1Int(v)
+	return nil
+}
+
+func NewV1Client(cfg *config.Config) *V1Client {
+	return &V1Client{
+		cfg: cfg,
+		HTTPClient: &http.Client{
+			Timeout: 10 * time.Second,
+		},
+		nomes: map[string]string{},
+	}
+}
+
+// Login abre a sessão na API v1.
+// Endpoint: GET /initSession (Authorization: Basic usuário:senha)
+func (c *V1Client) Login() error {
+	return c.LoginContext(context.Background())
+}
+
+// LoginContext é a variante de Login que respeita cancelamento e prazo do ctx
+func (c *V1Client) LoginContext(ctx context.Context) error {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+	return c.loginLocked(ctx)
+}
+
+// loginLocked executa o initSession. Deve ser chamado com authMu travado.
+func (c *V1Client) loginLocked(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.cfg.BaseURL+"/initSession", nil)
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição: %w", err)
+	}
+	req.SetBasicAuth(c.cfg.Username, c.cfg.Password)
+	req.Header.Set("Accept", "application/json")
+	if c.cfg.AppToken != "" {
+		req.Header.Set("App-Token", c.cfg.AppToken)
+	}
+
+	resp, err := c.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("login falhou: erro de conexão com GLPI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return fmt.Errorf("login falhou: %w", &StatusError{What: "initSession", StatusCode: resp.StatusCode, Body: string(body)})
+	}
+
+	var s struct {
+		SessionToken string `json:"session_token"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&s); err != nil {
+		return fmt.Errorf("erro ao decodificar resposta do initSession: %w", err)
+	}
+	if s.SessionToken == "" {
+		return fmt.Errorf("login falhou: initSession sem session_token")
+	}
+	c.SessionToken = s.SessionToken
+	return nil
+}
+
+// Interactive é sempre false: a v1 não tem os logins pelo navegador ou por código
+func (c *V1Client) Interactive() bool { return false }
+
+// SetInteract não faz nada: a v1 nunca precisa do usuário durante o login
+func (c *V1Client) SetInteract(func(instrucoes string)) {}
+
+// MyUserID devolve o ID do usuário autenticado (preenchido por GetMyID)
+func (c *V1Client) MyUserID() int { return c.UserID }
+
+// MyGroupIDs devolve os grupos do usuário autenticado (preenchidos por GetMyGroups)
+func (c *V1Client) MyGroupIDs() []int { return c.GroupIDs }
+
+// v1FullSession mapeia o que interessa do getFullSession
+type v1FullSession struct {
+	Session struct {
+		ID     v1Int   `json:"glpiID"`
+		Groups []v1Int `json:"glpigroups"`
+	} `json:"session"`
+}
+
+// GetMyID busca o ID do usuário da sessão.
+// Endpoint: GET /getFullSession
+func (c *V1Client) GetMyID() error {
+	return c.GetMyIDContext(context.Background())
+}
+
+// GetMyIDContext é a variante de GetMyID que respeita cancelamento e prazo do ctx
+func (c *V1Client) GetMyIDContext(ctx context.Context) error {
+	var s v1FullSession
+	if err := c.getJSON(ctx, c.cfg.BaseURL+"/getFullSession", nil, &s, "sessão"); err != nil {
+		return err
+	}
+	c.UserID = int(s.Session.ID)
+	return nil
+}
+
+// GetMyGroups busca os grupos do usuário da sessão e guarda os IDs em GroupIDs.
+// Endpoint: GET /getFullSession
+func (c *V1Client) GetMyGroups() error {
+	return c.GetMyGroupsContext(context.Background())
+}
+
+// GetMyGroupsContext é a variante de GetMyGroups que respeita cancelamento e prazo do ctx
+func (c *V1Client) GetMyGroupsContext(ctx context.Context) error {
+	var s v1FullSession
+	if err := c.getJSON(ctx, c.cfg.BaseURL+"/getFullSession", nil, &s, "grupos"); err != nil {
+		return err
+	}
+	ids := make([]int, len(s.Session.Groups))
+	for i, g := range s.Session.Groups {
+		ids[i] = int(g)
+	}
+	c.GroupIDs = ids
+	return nil
+}
+
+// validSession devolve o Session-Token atual
+func (c *V1Client) validSession() (string, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.SessionToken == "" {
+		return "", fmt.Errorf("client não autenticado")
+	}
+	return c.SessionToken, nil
+}
+
+// renewAfterUnauthorized abre uma sessão nova após um 401 (sessão expirada no servidor).
+// Se outra goroutine já trocou a sessão enquanto esta requisição estava em voo, apenas reaproveita a nova.
+func (c *V1Client) renewAfterUnauthorized(ctx context.Context, staleToken string) (string, error) {
+	c.authMu.Lock()
+	defer c.authMu.Unlock()
+
+	if c.SessionToken != staleToken {
+		return c.SessionToken, nil
+	}
+	if err := c.loginLocked(ctx); err != nil {
+		return "", err
+	}
+	return c.SessionToken, nil
+}
+
+// do executa uma requisição com a sessão. Se o servidor responder 401 (a sessão da v1 expira
+// por inatividade), abre outra sessão e repete a requisição original uma única vez.
+func (c *V1Client) do(req *http.Request) (*http.Response, error) {
+	return c.doWith(c.HTTPClient, req)
+}
+
+// doWith é o do() usando um http.Client específico (ex: transferências sem timeout global)
+func (c *V1Client) doWith(hc *http.Client, req *http.Request) (*http.Response, error) {
+	token, err := c.validSession()
+	if err != nil {
+		return nil, err
+	}
+
+	c.setHeaders(req, token)
+	resp, err := hc.Do(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	resp.Body.Close()
+
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return nil, fmt.Errorf("sessão expirada durante o envio e o corpo não pode ser repetido; tente novamente")
+	}
+
+	newToken, err := c.renewAfterUnauthorized(req.Context(), token)
+	if err != nil {
+		return nil, fmt.Errorf("sessão expirada e não foi possível renovar: %w", err)
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, fmt.Errorf("erro ao repetir requisição: %w", err)
+		}
+		retry.Body = body
+	}
+	c.setHeaders(retry, newToken)
+	return hc.Do(retry)
+}
+
+func (c *V1Client) setHeaders(req *http.Request, token string) {
+	req.Header.Set("Session-Token", token)
+	if c.cfg.AppToken != "" {
+		req.Header.Set("App-Token", c.cfg.AppToken)
+	}
+}
+
+// transferHTTPClient usa o mesmo transporte do client, mas sem o timeout global
+func (c *V1Client) transferHTTPClient() *http.Client {
+	return &http.Client{Transport: c.HTTPClient.Transport}
+}
+
+// getJSON faz um GET com a sessão e decodifica a resposta em out
+func (c *V1Client) getJSON(ctx context.Context, endpoint string, query url.Values, out interface{}, what string) error {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("erro parsing url de %s: %w", what, err)
+	}
+	if len(query) > 0 {
+		u.RawQuery = query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", u.String(), nil)
+	if err != nil {
+		return fmt.Errorf("erro ao criar req de %s: %w", what, err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("erro de conexão ao buscar %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != 206 {
+		body, _ := io.ReadAll(resp.Body)
+		return &StatusError{What: what, StatusCode: resp.StatusCode, Body: string(body)}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("erro de decode de %s: %w", what, err)
+	}
+	return nil
+}
+
+// sendJSON envia {"input": input} (POST/PUT com a sessão) e, se out não for nil, decodifica a resposta nele
+func (c *V1Client) sendJSON(ctx context.Context, method, endpoint string, input, out interface{}, what string) error {
+	jsonPayload, err := json.Marshal(map[string]interface{}{"input": input})
+	if err != nil {
+		return fmt.Errorf("erro ao criar payload de %s: %w", what, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(jsonPayload))
+	if err != nil {
+		return fmt.Errorf("erro ao criar requisição de %s: %w", what, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.do(req)
+	if err != nil {
+		return fmt.Errorf("erro de conexão ao %s: %w", what, err)
+	}
+	defer resp.Body.Close()
+
+	// A v1 responde 207 quando parte de uma operação em lote falhou: para um item só, é falha
+	if resp.StatusCode < 200 || resp.StatusCode > 299 || resp.StatusCode == http.StatusMultiStatus {
+		b, _ := io.ReadAll(resp.Body)
+		return &StatusError{What: what, StatusCode: resp.StatusCode, Body: string(b)}
+	}
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			return fmt.Errorf("erro de decode de %s: %w", what, err)
+		}
+	}
+	return nil
+}
+
+// idEntidade devolve o ID da entidade pelo nome completo, que é o que a busca mostra no campo 80.
+// A lista de entidades é lida do servidor uma vez por sessão; se a leitura falhar (ex: sem direito
+// de ver entidades) ou o nome não estiver nela, o ID fica 0.
+func (c *V1Client) idEntidade(ctx context.Context, nome string) int {
+	c.nomesMu.Lock()
+	entidades := c.entidades
+	c.nomesMu.Unlock()
+	if entidades == nil {
+		var lista []struct {
+			ID           v1Int  `json:"id"`
+			CompleteName string `json:"completename"`
+		}
+		q := url.Values{"range": {"0-9999"}, "expand_dropdowns": {"false"}}
+		if err := c.getJSON(ctx, c.cfg.BaseURL+"/Entity", q, &lista, "entidades"); ctx.Err() != nil {
+			return 0 // Busca abandonada: tenta de novo na próxima
+		} else if err != nil {
+			lista = nil
+		}
+		entidades = make(map[string]int, len(lista))
+		for _, e := range lista {
+			entidades[e.CompleteName] = int(e.ID)
+		}
+		c.nomesMu.Lock()
+		c.entidades = entidades
+		c.nomesMu.Unlock()
+	}
+	return entidades[nome]
+}
+
+// nome devolve o nome de um item (User, Group, Entity, SolutionType...) consultando o servidor
+// só na primeira vez. Sem permissão para ler o item, mostra "User #12" em vez de falhar a tela.
+func (c *V1Client) nome(ctx context.Context, itemtype string, id int) string {
+	if id <= 0 {
+		return ""
+	}
+	chave := fmt.Sprintf("%s/%d", itemtype, id)
+	c.nomesMu.Lock()
+	n, ok := c.nomes[chave]
+	c.nomesMu.Unlock()
+	if ok {
+		return n
+	}
+
+	var item struct {
+		Name         string `json:"name"`
+		CompleteName string `json:"completename"`
+		FirstName    string `json:"firstname"`
+		RealName     string `json:"realname"`
+	}
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/%s/%d", c.cfg.BaseURL, itemtype, id), nil, &item, "nome"); err != nil {
+		return fmt.Sprintf("%s #%d", itemtype, id)
+	}
+	switch {
+	case item.FirstName != "" || item.RealName != "":
+		n = strings.TrimSpace(item.FirstName + " " + item.RealName)
+	case item.CompleteName != "":
+		n = item.CompleteName
+	default:
+		n = item.Name
+	}
+
+	c.nomesMu.Lock()
+	c.nomes[chave] = n
+	c.nomesMu.Unlock()
+	return n
+}
+
+// v1HTML desfaz o escape de entidades que a v1 aplica nos campos de texto rico
+// ("&lt;p&gt;" vira "<p>"), deixando o HTML no mesmo formato da v2
+func v1HTML(s string) string {
+	return html.UnescapeString(s)
+}
@@ -0,0 +1,345 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"glpi-tui/internal/domain"
+	"glpi-tui/internal/markdown"
+)
+
+// Opções de busca (search options) do Ticket usadas na v1: a lista vem do /search/Ticket,
+// que filtra e ordena pelo número do campo em vez do nome
+const (
+	v1CampoNome       = "1"
+	v1CampoID         = "2"
+	v1CampoPrioridade = "3"
+	v1CampoRequerente = "4"
+	v1CampoTecnico    = "5"
+	v1CampoGrupo      = "8"
+	v1CampoStatus     = "12"
+	v1CampoAbertura   = "15"
+	v1CampoAlteracao  = "19"
+	v1CampoDescricao  = "21"
+	v1CampoEntidade   = "80"
+)
+
+// v1CamposOrdem traduz as propriedades de TicketQuery.Sort para os campos da busca
+var v1CamposOrdem = map[string]string{
+	"id":        v1CampoID,
+	"name":      v1CampoNome,
+	"priority":  v1CampoPrioridade,
+	"status":    v1CampoStatus,
+	"status.id": v1CampoStatus,
+	"date":      v1CampoAbertura,
+	"date_mod":  v1CampoAlteracao,
+}
+
+// v1Criterio é um critério do /search/Ticket; com grupo preenchido vira um sub-critério entre parênteses
+type v1Criterio struct {
+	link  string // AND ou OR (ignorado no primeiro)
+	campo string
+	tipo  string // equals, contains, morethan, lessthan
+	valor string
+	grupo []v1Criterio
+}
+
+// v1Qualquer casa o campo com qualquer um dos valores (OR entre parênteses)
+func v1Qualquer(campo string, valores []int) v1Criterio {
+	c := v1Criterio{link: "AND"}
+	for _, v := range valores {
+		c.grupo = append(c.grupo, v1Criterio{link: "OR", campo: campo, tipo: "equals", valor: strconv.Itoa(v)})
+	}
+	return c
+}
+
+// v1Criterios traduz o filtro para critérios da busca, combinados com AND como no RSQL da v2
+func (f TicketFilter) v1Criterios() []v1Criterio {
+	var cs []v1Criterio
+	igual := func(campo string, v int) {
+		cs = append(cs, v1Criterio{link: "AND", campo: campo, tipo: "equals", valor: strconv.Itoa(v)})
+	}
+	data := func(campo, tipo string, v time.Time) {
+		cs = append(cs, v1Criterio{link: "AND", campo: campo, tipo: tipo, valor: v.Format(filterDateLayout)})
+	}
+
+	if len(f.Statuses) > 0 {
+		cs = append(cs, v1Qualquer(v1CampoStatus, f.Statuses))
+	}
+	if f.MinPriority > 0 {
+		// Prioridade é um campo de lista: "maior ou igual" vira a lista das prioridades aceitas
+		var prios []int
+		for p := f.MinPriority; p <= 6; p++ {
+			prios = append(prios, p)
+		}
+		cs = append(cs, v1Qualquer(v1CampoPrioridade, prios))
+	}
+	if f.EntityID != nil {
+		igual(v1CampoEntidade, *f.EntityID)
+	}
+	if f.AssignedTo > 0 {
+		igual(v1CampoTecnico, f.AssignedTo)
+	}
+	if f.RequesterID > 0 {
+		igual(v1CampoRequerente, f.RequesterID)
+	}
+	if len(f.AssignedGroups) > 0 {
+		cs = append(cs, v1Qualquer(v1CampoGrupo, f.AssignedGroups))
+	}
+	if f.Unassigned {
+		// Na v1 a busca expressa direto: nenhum técnico e nenhum grupo atribuído
+		igual(v1CampoTecnico, 0)
+		igual(v1CampoGrupo, 0)
+	}
+	if !f.OpenedAfter.IsZero() {
+		data(v1CampoAbertura, "morethan", f.OpenedAfter)
+	}
+	if !f.OpenedBefore.IsZero() {
+		data(v1CampoAbertura, "lessthan", f.OpenedBefore)
+	}
+	if !f.ModifiedAfter.IsZero() {
+		data(v1CampoAlteracao, "morethan", f.ModifiedAfter)
+	}
+	if !f.ModifiedBefore.IsZero() {
+		data(v1CampoAlteracao, "lessthan", f.ModifiedBefore)
+	}
+	if text := strings.TrimSpace(f.Text); text != "" {
+		cs = append(cs, v1Criterio{link: "AND", grupo: []v1Criterio{
+			{link: "OR", campo: v1CampoNome, tipo: "contains", valor: text},
+			{link: "OR", campo: v1CampoDescricao, tipo: "contains", valor: text},
+		}})
+	}
+	return cs
+}
+
+// encodeV1Criterios serializa os critérios no formato criteria[0][field]=12&criteria[0][criteria][0]...
+func encodeV1Criterios(q url.Values, prefixo string, cs []v1Criterio) {
+	for i, c := range cs {
+		p := fmt.Sprintf("%s[%d]", prefixo, i)
+		q.Set(p+"[link]", c.link)
+		if len(c.grupo) > 0 {
+			encodeV1Criterios(q, p+"[criteria]", c.grupo)
+			continue
+		}
+		q.Set(p+"[field]", c.campo)
+		q.Set(p+"[searchtype]", c.tipo)
+		q.Set(p+"[value]", c.valor)
+	}
+}
+
+// v1Linha é uma linha do resultado do /search, indexada pelo número do campo
+type v1Linha map[string]json.RawMessage
+
+func (l v1Linha) texto(campo string) string {
+	var s string
+	_ = json.Unmarshal(l[campo], &s)
+	return s
+}
+
+func (l v1Linha) numero(campo string) int {
+	var n v1Int
+	_ = json.Unmarshal(l[campo], &n)
+	return int(n)
+}
+
+// GetTicketsPage busca uma página de chamados conforme start/limit/filter de q.
+// Endpoint: GET /search/Ticket (critérios, forcedisplay, sort/order e range)
+func (c *V1Client) GetTicketsPage(q TicketQuery) (TicketPage, error) {
+	return c.GetTicketsPageContext(context.Background(), q)
+}
+
+// GetTicketsPageContext é a variante de GetTicketsPage que respeita cancelamento e prazo do ctx
+func (c *V1Client) GetTicketsPageContext(ctx context.Context, tq TicketQuery) (TicketPage, error) {
+	tq = tq.withDefaults()
+
+	prop, dir, _ := strings.Cut(tq.Sort, ":")
+	campoOrdem, ok := v1CamposOrdem[prop]
+	if !ok {
+		return TicketPage{}, fmt.Errorf("ordenação por %q não suportada na API v1", prop)
+	}
+	ordem := "DESC"
+	if strings.EqualFold(dir, "asc") {
+		ordem = "ASC"
+	}
+
+	q := url.Values{}
+	encodeV1Criterios(q, "criteria", append(tq.Scope.v1Criterios(), tq.Filter.v1Criterios()...))
+	for i, campo := range []string{v1CampoID, v1CampoNome, v1CampoStatus, v1CampoPrioridade, v1CampoAbertura, v1CampoAlteracao, v1CampoEntidade} {
+		q.Set(fmt.Sprintf("forcedisplay[%d]", i), campo)
+	}
+	q.Set("sort", campoOrdem)
+	q.Set("order", ordem)
+	q.Set("range", fmt.Sprintf("%d-%d", tq.Start, tq.Start+tq.Limit-1))
+
+	var resp struct {
+		TotalCount int       `json:"totalcount"`
+		Data       []v1Linha `json:"data"`
+	}
+	err := c.getJSON(ctx, c.cfg.BaseURL+"/search/Ticket", q, &resp, "chamados")
+	var se *StatusError
+	if errors.As(err, &se) && strings.Contains(se.Body, "ERROR_RANGE_EXCEED_TOTAL") {
+		// Pediu além do fim (a lista encolheu desde a última página): página vazia
+		return TicketPage{Start: tq.Start, Total: tq.Start}, nil
+	}
+	if err != nil {
+		return TicketPage{}, err
+	}
+
+	chamados := make([]domain.Chamado, 0, len(resp.Data))
+	for _, l := range resp.Data {
+		entidade := l.texto(v1CampoEntidade)
+		chamados = append(chamados, domain.Chamado{
+			ID:       l.numero(v1CampoID),
+			Name:     l.texto(v1CampoNome),
+			Date:     l.texto(v1CampoAbertura),
+			DateMod:  l.texto(v1CampoAlteracao),
+			Status:   domain.TicketStatus{ID: l.numero(v1CampoStatus)},
+			Priority: l.numero(v1CampoPrioridade),
+			Entity:   domain.TicketEntity{ID: c.idEntidade(ctx, entidade), Name: entidade},
+		})
+	}
+	return TicketPage{Tickets: chamados, Start: tq.Start, Total: resp.TotalCount}, nil
+}
+
+// GetTicket busca um único chamado pelo ID.
+// Endpoint: GET /Ticket/{id}
+func (c *V1Client) GetTicket(ticketID int) (domain.Chamado, error) {
+	return c.GetTicketContext(context.Background(), ticketID)
+}
+
+// GetTicketContext é a variante de GetTicket que respeita cancelamento e prazo do ctx
+func (c *V1Client) GetTicketContext(ctx context.Context, ticketID int) (domain.Chamado, error) {
+	var t struct {
+		ID         v1Int  `json:"id"`
+		Name       string `json:"name"`
+		Content    string `json:"content"`
+		Date       string `json:"date"`
+		DateMod    string `json:"date_mod"`
+		Status     v1Int  `json:"status"`
+		Priority   v1Int  `json:"priority"`
+		EntitiesID v1Int  `json:"entities_id"`
+	}
+	if err := c.getJSON(ctx, fmt.Sprintf("%s/Ticket/%d", c.cfg.BaseURL, ticketID), nil, &t, "chamado"); err != nil {
+		return domain.Chamado{}, err
+	}
+
+	entidade := c.nome(ctx, "Entity", int(t.EntitiesID))
+	if t.EntitiesID == 0 {
+		entidade = "Entidade raiz" // A raiz tem ID 0, que nome() trata como vazio
+	}
+	return domain.Chamado{
+		ID:       int(t.ID),
+		Name:     t.Name,
+		Content:  v1HTML(t.Content),
+		Date:     t.Date,
+		DateMod:  t.DateMod,
+		Status:   domain.TicketStatus{ID: int(t.Status)},
+		Priority: int(t.Priority),
+		Entity:   domain.TicketEntity{ID: int(t.EntitiesID), Name: entidade},
+	}, nil
+}
+
+// CreateTicket abre um novo chamado e devolve o ID gerado.
+// Endpoint: POST /Ticket
+func (c *V1Client) CreateTicket(in TicketInput) (int, error) {
+	return c.CreateTicketContext(context.Background(), in)
+}
+
+// CreateTicketContext é a variante de CreateTicket que respeita cancelamento e prazo do ctx
+func (c *V1Client) CreateTicketContext(ctx context.Context, in TicketInput) (int, error) {
+	if err := in.Validate(); err != nil {
+		return 0, fmt.Errorf("chamado inválido: %w", err)
+	}
+
+	payload := ticketCreatePayload{
+		Name:         strings.TrimSpace(in.Name),
+		Content:      plainTextToHTML(in.Content),
+		Type:         in.Type,
+		Urgency:      in.Urgency,
+		CategoryID:   in.CategoryID,
+		EntityID:     in.EntityID,
+		RequesterIDs: in.RequesterIDs,
+		ObserverIDs:  in.ObserverIDs,
+	}
+	var created createdResponse
+	if err := c.sendJSON(ctx, "POST", c.cfg.BaseURL+"/Ticket", payload, &created, "criar chamado"); err != nil {
+		return 0, err
+	}
+	return created.ID, nil
+}
+
+// CreateTicketFollowup envia um novo acompanhamento. O content é Markdown (texto simples também serve).
+// Endpoint: POST /Ticket/{id}/ITILFollowup
+func (c *V1Client) CreateTicketFollowup(ticketID int, content string) error {
+	return c.CreateTicketFollowupContext(context.Background(), ticketID, content)
+}
+
+// CreateTicketFollowupContext é a variante de CreateTicketFollowup que respeita cancelamento e prazo do ctx
+func (c *V1Client) CreateTicketFollowupContext(ctx context.Context, ticketID int, content string) error {
+	payload := FollowupPayload{
+		Content:       markdown.ToHTML(content),
+		RequestTypeID: 1,
+		ItemsID:       ticketID,
+		ItemType:      "Ticket",
+	}
+	endpoint := fmt.Sprintf("%s/Ticket/%d/ITILFollowup", c.cfg.BaseURL, ticketID)
+	return c.sendJSON(ctx, "POST", endpoint, payload, nil, "criar followup")
+}
+
+// AssignTicketViaUpdate atribui o chamado ao usuário autenticado, com o status de domain.AssignStatus.
+// Endpoints: POST /Ticket/{id}/Ticket_User (type 2 = técnico) e PUT /Ticket/{id}.
+// entityID é ignorado: na v1 a entidade vem da sessão.
+func (c *V1Client) AssignTicketViaUpdate(ticketID int, entityID int) error {
+	return c.AssignTicketViaUpdateContext(context.Background(), ticketID, entityID)
+}
+
+// AssignTicketViaUpdateContext é a variante de AssignTicketViaUpdate que respeita cancelamento e prazo do ctx
+func (c *V1Client) AssignTicketViaUpdateContext(ctx context.Context, ticketID int, entityID int) error {
+	if c.UserID == 0 {
+		return fmt.Errorf("ID do usuário desconhecido. GetMyID foi chamado?")
+	}
+
+	atual, err := c.GetTicketContext(ctx, ticketID)
+	if err != nil {
+		return err
+	}
+	to, err := domain.AssignStatus(atual.Status.ID)
+	if err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/Ticket/%d/Ticket_User", c.cfg.BaseURL, ticketID)
+	ator := map[string]interface{}{"tickets_id": ticketID, "users_id": c.UserID, "type": 2}
+	if err := c.sendJSON(ctx, "POST", endpoint, ator, nil, "atribuir chamado"); err != nil {
+		return err
+	}
+	if to == atual.Status.ID {
+		return nil
+	}
+	return c.updateTicket(ctx, ticketID, map[string]interface{}{"status": to})
+}
+
+// ChangeTicketStatus muda o status do chamado, recusando movimentos fora da máquina de estados do domain
+func (c *V1Client) ChangeTicketStatus(ticketID, entityID, from, to int) error {
+	return c.ChangeTicketStatusContext(context.Background(), ticketID, entityID, from, to)
+}
+
+// ChangeTicketStatusContext é a variante de ChangeTicketStatus que respeita cancelamento e prazo do ctx
+func (c *V1Client) ChangeTicketStatusContext(ctx context.Context, ticketID, entityID, from, to int) error {
+	if err := domain.ValidateTransition(from, to); err != nil {
+		return err
+	}
+	return c.updateTicket(ctx, ticketID, map[string]interface{}{"status": to})
+}
+
+// updateTicket atualiza parcialmente o chamado. Endpoint: PUT /Ticket/{id}
+func (c *V1Client) updateTicket(ctx context.Context, ticketID int, fields map[string]interface{}) error {
+	endpoint := fmt.Sprintf("%s/Ticket/%d", c.cfg.BaseURL, ticketID)
+	return c.sendJSON(ctx, "PUT", endpoint, fields, nil, "atualizar chamado")
+}
@@ -0,0 +1,224 @@
+// Package cache guarda localmente (bbolt) os chamados e as timelines já baixados,
+// para a lista aparecer na hora ao abrir o programa e os chamados poderem ser lidos sem rede.
+//
+// Cada chamado é salvo pelo ID junto com o date_mod da versão baixada; a TUI mostra o
+// que estiver no cache e reconcilia com o servidor em background. O mesmo arquivo guarda a
+// fila de envio (escritas ainda não confirmadas) e o que o usuário já leu de cada chamado.
+package cache
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+
+	"glpi-tui/internal/domain"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	bucketTickets  = []byte("tickets")
+	bucketDetalhes = []byte("detalhes")
+	bucketFilas    = []byte("filas")
+)
+
+// Store é o cache local de uma instância GLPI/usuário. Um *Store nil é válido e
+// simplesmente não guarda nada (cache desligado ou indisponível).
+type Store struct {
+	db *bolt.DB
+}
+
+// Details é o que se sabe de um chamado aberto: atores e timeline, com o date_mod
+// do chamado no momento em que foram baixados
+type Details struct {
+	DateMod  string                `json:"date_mod"`
+	Actors   []domain.TicketActor  `json:"actors,omitempty"`
+	Timeline []domain.TimelineItem `json:"timeline,omitempty"`
+	Saved    time.Time             `json:"saved"`
+}
+
+// filaSalva é a ordem dos chamados da primeira página de uma fila
+type filaSalva struct {
+	IDs   []int `json:"ids"`
+	Total int   `json:"total"`
+}
+
+var nomeInseguro = regexp.MustCompile(`[^a-zA-Z0-9._-]+`)
+
+// Path devolve o arquivo de cache da instância (host da URL) e do usuário dentro de dir
+func Path(dir, baseURL, username string) string {
+	host := baseURL
+	if u, err := url.Parse(baseURL); err == nil && u.Host != "" {
+		host = u.Host + u.Path
+	}
+	nome := nomeInseguro.ReplaceAllString(host+"_"+username, "_")
+	return filepath.Join(dir, nome+".db")
+}
+
+// Open abre (ou cria) o cache. Falha rápido se outra instância do programa estiver com o arquivo aberto.
+func Open(path string) (*Store, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("erro ao criar pasta do cache: %w", err)
+	}
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("erro ao abrir cache %s: %w", path, err)
+	}
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{bucketTickets, bucketDetalhes, bucketFilas, bucketOutbox, bucketVistos} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("erro ao preparar cache: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close fecha o arquivo do cache
+func (s *Store) Close() error {
+	if s == nil {
+		return nil
+	}
+	return s.db.Close()
+}
+
+// SaveTickets grava (ou atualiza) os chamados recebidos do servidor
+func (s *Store) SaveTickets(tickets []domain.Chamado) error {
+	if s == nil || len(tickets) == 0 {
+		return nil
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketTickets)
+		for _, t := range tickets {
+			// Atores e timeline não vão no JSON do chamado; ficam no bucket de detalhes
+			data, err := json.Marshal(t)
+			if err != nil {
+				return fmt.Errorf("erro ao serializar chamado %d: %w", t.ID, err)
+			}
+			if err := b.Put(itob(t.ID), data); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// Ticket devolve o chamado salvo
+func (s *Store) Ticket(id int) (domain.Chamado, bool) {
+	var t domain.Chamado
+	if s == nil {
+		return t, false
+	}
+	ok := false
+	s.db.View(func(tx *bolt.Tx) error {
+		if data := tx.Bucket(bucketTickets).Get(itob(id)); data != nil {
+			ok = json.Unmarshal(data, &t) == nil
+		}
+		return nil
+	})
+	return t, ok
+}
+
+// SaveQueue grava a ordem da primeira página de uma fila (aba) da lista
+func (s *Store) SaveQueue(nome string, ids []int, total int) error {
+	if s == nil {
+		return nil
+	}
+	data, err := json.Marshal(filaSalva{IDs: ids, Total: total})
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketFilas).Put([]byte(nome), data)
+	})
+}
+
+// Queue devolve os chamados salvos de uma fila, na ordem em que foram vistos, e o total do servidor
+func (s *Store) Queue(nome string) ([]domain.Chamado, int) {
+	if s == nil {
+		return nil, -1
+	}
+	var tickets []domain.Chamado
+	total := -1
+	s.db.View(func(tx *bolt.Tx) error {
+		var f filaSalva
+		data := tx.Bucket(bucketFilas).Get([]byte(nome))
+		if data == nil || json.Unmarshal(data, &f) != nil {
+			return nil
+		}
+		total = f.Total
+		b := tx.Bucket(bucketTickets)
+		for _, id := range f.IDs {
+			var t domain.Chamado
+			if raw := b.Get(itob(id)); raw != nil && json.Unmarshal(raw, &t) == nil {
+				tickets = append(tickets, t)
+			}
+		}
+		return nil
+	})
+	return tickets, total
+}
+
+// Details devolve atores e timeline salvos do chamado
+func (s *Store) Details(ticketID int) (Details, bool) {
+	var d Details
+	if s == nil {
+		return d, false
+	}
+	ok := false
+	s.db.View(func(tx *bolt.Tx) error {
+		if data := tx.Bucket(bucketDetalhes).Get(itob(ticketID)); data != nil {
+			ok = json.Unmarshal(data, &d) == nil
+		}
+		return nil
+	})
+	return d, ok
+}
+
+// SaveActors atualiza os atores salvos do chamado
+func (s *Store) SaveActors(ticketID int, dateMod string, actors []domain.TicketActor) error {
+	return s.updateDetails(ticketID, dateMod, func(d *Details) { d.Actors = actors })
+}
+
+// SaveTimeline atualiza a timeline salva do chamado
+func (s *Store) SaveTimeline(ticketID int, dateMod string, items []domain.TimelineItem) error {
+	return s.updateDetails(ticketID, dateMod, func(d *Details) { d.Timeline = items })
+}
+
+func (s *Store) updateDetails(ticketID int, dateMod string, fn func(*Details)) error {
+	if s == nil {
+		return nil
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketDetalhes)
+		var d Details
+		if data := b.Get(itob(ticketID)); data != nil {
+			_ = json.Unmarshal(data, &d) // Registro corrompido é simplesmente sobrescrito
+		}
+		fn(&d)
+		d.DateMod = dateMod
+		d.Saved = time.Now()
+		data, err := json.Marshal(d)
+		if err != nil {
+			return fmt.Errorf("erro ao serializar detalhes do chamado %d: %w", ticketID, err)
+		}
+		return b.Put(itob(ticketID), data)
+	})
+}
+
+// itob codifica o ID em big-endian para as chaves ficarem ordenadas numericamente
+func itob(id int) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(id))
+	return b
+}
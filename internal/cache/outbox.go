@@ -0,0 +1,112 @@
+package cache
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var bucketOutbox = []byte("outbox")
+
+// ErrDesligado indica que não há cache aberto para persistir a operação
+var ErrDesligado = errors.New("cache local desligado")
+
+// Tipos de escrita que passam pela fila de envio
+const (
+	OutboxFollowup = "followup"
+	OutboxAssign   = "assign"
+	OutboxStatus   = "status"
+)
+
+// OutboxEntry é uma escrita feita na TUI que ainda não foi confirmada pelo servidor
+type OutboxEntry struct {
+	ID       uint64 `json:"id"`
+	Kind     string `json:"kind"`
+	TicketID int    `json:"ticket_id"`
+	EntityID int    `json:"entity_id"`
+
+	Content  string `json:"content,omitempty"`   // Texto (Markdown) do acompanhamento
+	ToStatus int    `json:"to_status,omitempty"` // O status de origem é lido do servidor na hora do envio
+
+	Created   time.Time `json:"created"`
+	Attempts  int       `json:"attempts"`
+	NextTry   time.Time `json:"next_try"`
+	LastError string    `json:"last_error,omitempty"`
+	Failed    bool      `json:"failed"`              // Recusada pelo servidor: não é mais reenviada sozinha
+	Uncertain bool      `json:"uncertain,omitempty"` // Pode ter chegado ao servidor: conferir antes de reenviar
+}
+
+// Enqueue grava uma nova escrita pendente e devolve a entrada com o ID atribuído
+func (s *Store) Enqueue(e OutboxEntry) (OutboxEntry, error) {
+	if s == nil {
+		return e, ErrDesligado
+	}
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		b := tx.Bucket(bucketOutbox)
+		var err error
+		if e.ID, err = b.NextSequence(); err != nil {
+			return err
+		}
+		return putOutbox(b, e)
+	})
+	if err != nil {
+		return e, fmt.Errorf("erro ao gravar envio pendente: %w", err)
+	}
+	return e, nil
+}
+
+// UpdateOutbox regrava a entrada (tentativas, próximo horário, erro)
+func (s *Store) UpdateOutbox(e OutboxEntry) error {
+	if s == nil {
+		return ErrDesligado
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return putOutbox(tx.Bucket(bucketOutbox), e)
+	})
+}
+
+// RemoveOutbox apaga a entrada (enviada com sucesso ou descartada pelo usuário)
+func (s *Store) RemoveOutbox(id uint64) error {
+	if s == nil {
+		return ErrDesligado
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketOutbox).Delete(outboxKey(id))
+	})
+}
+
+// Outbox devolve as escritas pendentes na ordem em que foram feitas
+func (s *Store) Outbox() []OutboxEntry {
+	if s == nil {
+		return nil
+	}
+	var out []OutboxEntry
+	s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketOutbox).ForEach(func(_, v []byte) error {
+			var e OutboxEntry
+			if json.Unmarshal(v, &e) == nil {
+				out = append(out, e)
+			}
+			return nil
+		})
+	})
+	sort.Slice(out, func(i, j int) bool { return out[i].ID < out[j].ID })
+	return out
+}
+
+func putOutbox(b *bolt.Bucket, e OutboxEntry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	return b.Put(outboxKey(e.ID), data)
+}
+
+func outboxKey(id uint64) []byte {
+	return binary.BigEndian.AppendUint64(nil, id)
+}
@@ -0,0 +1,50 @@
+package cache
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var bucketVistos = []byte("vistos")
+
+// Seen é o que o usuário já viu de um chamado na última vez que abriu a timeline
+type Seen struct {
+	DateMod      string    `json:"date_mod"`
+	LastFollowup int       `json:"last_followup"`
+	At           time.Time `json:"at"`
+}
+
+// MarkSeen grava o estado visto do chamado
+func (s *Store) MarkSeen(ticketID int, seen Seen) error {
+	if s == nil {
+		return nil
+	}
+	data, err := json.Marshal(seen)
+	if err != nil {
+		return err
+	}
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketVistos).Put(itob(ticketID), data)
+	})
+}
+
+// SeenAll devolve o estado visto de todos os chamados já abertos (mapa vazio sem cache)
+func (s *Store) SeenAll() map[int]Seen {
+	out := map[int]Seen{}
+	if s == nil {
+		return out
+	}
+	s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(bucketVistos).ForEach(func(k, v []byte) error {
+			var seen Seen
+			if len(k) == 8 && json.Unmarshal(v, &seen) == nil {
+				out[int(binary.BigEndian.Uint64(k))] = seen
+			}
+			return nil
+		})
+	})
+	return out
+}
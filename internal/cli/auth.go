@@ -0,0 +1,193 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"glpi-tui/internal/api"
+	"glpi-tui/internal/config"
+	"glpi-tui/internal/secrets"
+)
+
+// authOut é a resposta do auth login/logout
+type authOut struct {
+	Perfil   string   `json:"profile,omitempty" yaml:"profile,omitempty"`
+	Usuario  string   `json:"user" yaml:"user"`
+	URL      string   `json:"url" yaml:"url"`
+	Backend  string   `json:"backend" yaml:"backend"`
+	Segredos []string `json:"secrets" yaml:"secrets"`
+}
+
+func runAuth(e *ambiente, args []string) error {
+	if len(args) == 0 {
+		return usof("falta a ação: login ou logout")
+	}
+	switch args[0] {
+	case "login":
+		return runAuthLogin(e, args[1:])
+	case "logout":
+		return runAuthLogout(e, args[1:])
+	}
+	return usof("ação desconhecida %q: use login ou logout", args[0])
+}
+
+// runAuthLogin pede a senha uma vez, confere com o servidor e guarda os segredos no backend
+func runAuthLogin(e *ambiente, args []string) error {
+	fs := e.flags("auth login")
+	backend := fs.String("backend", "", "onde guardar: auto, keyring ou file (padrão: secrets do perfil ou GLPI_SECRETS)")
+	soSessao := fs.Bool("token-only", false, "guarda só a sessão (refresh token), nunca a senha")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usof("argumento inesperado: %s", pos[0])
+	}
+
+	cfg, err := config.LoadProfileForLogin(e.perfil)
+	if err != nil {
+		return erroConfig{err}
+	}
+	if *backend != "" {
+		cfg.Secrets = *backend
+	}
+	store, err := cfg.SecretStore()
+	if err != nil {
+		return erroConfig{err}
+	}
+
+	if *soSessao && cfg.API == config.APIV1 {
+		return usof("--token-only não se aplica à API v1: a sessão dela expira e só a senha abre outra")
+	}
+
+	// client_secret que já está em texto puro na configuração fica onde está.
+	// No login pelo navegador ou por código ele é opcional (clientes OAuth públicos não têm);
+	// na API v1 não existe (o App-Token fica no perfil).
+	guardarSecret := cfg.ClientSecret == "" && cfg.API == config.APIV2
+	if guardarSecret && cfg.Interactive() {
+		cfg.ClientSecret, err = store.Get(cfg.SecretKey(secrets.ClientSecret))
+		if errors.Is(err, secrets.ErrNotFound) {
+			err = nil
+		}
+		if err != nil {
+			return err
+		}
+		guardarSecret = false
+	} else if guardarSecret {
+		cfg.ClientSecret, err = store.Get(cfg.SecretKey(secrets.ClientSecret))
+		if errors.Is(err, secrets.ErrNotFound) {
+			cfg.ClientSecret, err = secrets.PromptPassword("Client secret (" + cfg.ClientID + "): ")
+		}
+		if err != nil {
+			return err
+		}
+	}
+	senhaEmTexto := cfg.Password != "" && !cfg.Interactive()
+	if !cfg.Interactive() {
+		if !senhaEmTexto {
+			if cfg.Password, err = secrets.PromptPassword(fmt.Sprintf("Senha de %s em %s: ", cfg.Username, cfg.BaseURL)); err != nil {
+				return err
+			}
+		}
+		if cfg.Password == "" || (cfg.ClientSecret == "" && cfg.API == config.APIV2) {
+			return usof("senha e client_secret não podem ser vazios")
+		}
+	}
+
+	client, err := api.New(cfg)
+	if err != nil {
+		return erroConfig{err}
+	}
+	client.SetInteract(func(instrucoes string) { fmt.Fprintln(e.stderr, instrucoes) })
+	if err := client.LoginContext(e.ctx); err != nil {
+		return err
+	}
+	// Só a v2 (OAuth2) tem sessão que sobrevive entre execuções
+	var refreshToken string
+	if v2, ok := client.(*api.Client); ok {
+		refreshToken = v2.RefreshToken
+	}
+	if (*soSessao || cfg.Interactive()) && refreshToken == "" {
+		if cfg.Interactive() {
+			return fmt.Errorf("o servidor não devolveu refresh_token: o login pelo navegador/código teria de ser repetido a cada início")
+		}
+		return fmt.Errorf("o servidor não devolveu refresh_token: sem --token-only a senha é guardada")
+	}
+
+	out := authOut{Perfil: cfg.Profile, Usuario: cfg.Username, URL: cfg.BaseURL, Backend: store.Name(), Segredos: []string{}}
+	guardar := func(tipo, valor string) error {
+		if err := store.Set(cfg.SecretKey(tipo), valor); err != nil {
+			return fmt.Errorf("erro ao gravar no %s: %w", store.Name(), err)
+		}
+		out.Segredos = append(out.Segredos, tipo)
+		return nil
+	}
+	if guardarSecret {
+		if err := guardar(secrets.ClientSecret, cfg.ClientSecret); err != nil {
+			return err
+		}
+	}
+	if *soSessao || cfg.Interactive() {
+		if err := store.Delete(cfg.SecretKey(secrets.Password)); err != nil {
+			return fmt.Errorf("erro ao apagar a senha antiga do %s: %w", store.Name(), err)
+		}
+	} else if err := guardar(secrets.Password, cfg.Password); err != nil {
+		return err
+	}
+	if refreshToken != "" {
+		if err := guardar(secrets.RefreshToken, refreshToken); err != nil {
+			return err
+		}
+	}
+
+	return e.imprimir(out, func(w io.Writer) error {
+		fmt.Fprintf(w, "Login confirmado: %s guardado(s) no %s\n", strings.Join(out.Segredos, ", "), out.Backend)
+		if senhaEmTexto {
+			origem := "GLPI_PASS"
+			if cfg.Profile != "" {
+				origem = "password do perfil em " + config.FilePath()
+			}
+			fmt.Fprintf(w, "A senha ainda está em texto puro (%s): pode removê-la de lá\n", origem)
+		}
+		return nil
+	})
+}
+
+// runAuthLogout apaga do backend os segredos da conta do perfil
+func runAuthLogout(e *ambiente, args []string) error {
+	fs := e.flags("auth logout")
+	backend := fs.String("backend", "", "de onde apagar: auto, keyring ou file (padrão: secrets do perfil ou GLPI_SECRETS)")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usof("argumento inesperado: %s", pos[0])
+	}
+
+	cfg, err := config.LoadProfileForLogin(e.perfil)
+	if err != nil {
+		return erroConfig{err}
+	}
+	if *backend != "" {
+		cfg.Secrets = *backend
+	}
+	store, err := cfg.SecretStore()
+	if err != nil {
+		return erroConfig{err}
+	}
+
+	out := authOut{Perfil: cfg.Profile, Usuario: cfg.Username, URL: cfg.BaseURL, Backend: store.Name(), Segredos: []string{}}
+	for _, tipo := range []string{secrets.Password, secrets.ClientSecret, secrets.RefreshToken} {
+		if err := store.Delete(cfg.SecretKey(tipo)); err != nil {
+			return fmt.Errorf("erro ao apagar do %s: %w", store.Name(), err)
+		}
+		out.Segredos = append(out.Segredos, tipo)
+	}
+	return e.imprimir(out, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Segredos de %s em %s apagados do %s\n", cfg.Username, cfg.BaseURL, out.Backend)
+		return err
+	})
+}
@@ -0,0 +1,227 @@
+// Package cli implementa os subcomandos não interativos (glpi-tui list, show, reply...),
+// para o mesmo binário da TUI ser usado em scripts, cron e pipelines.
+//
+// Toda saída de dados vai para o stdout no formato pedido em --output; mensagens de erro
+// vão para o stderr e o código de saída diz o tipo de falha (ver Exit*).
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+
+	"glpi-tui/internal/api"
+	"glpi-tui/internal/config"
+)
+
+// Códigos de saída
+const (
+	ExitOK            = 0
+	ExitErro          = 1  // Falha da API sem categoria específica
+	ExitUso           = 2  // Subcomando, flag ou argumento inválido
+	ExitConfig        = 3  // Configuração ausente ou inválida
+	ExitAuth          = 4  // Login recusado ou sem permissão (HTTP 401/403)
+	ExitNaoEncontrado = 5  // Chamado inexistente (HTTP 404)
+	ExitTemporario    = 75 // Rede fora ou servidor indisponível: vale tentar de novo (EX_TEMPFAIL)
+)
+
+// erroUso indica argumentos inválidos: a mensagem vem acompanhada do uso do subcomando
+type erroUso struct{ msg string }
+
+func (e erroUso) Error() string { return e.msg }
+
+func usof(format string, a ...any) error { return erroUso{fmt.Sprintf(format, a...)} }
+
+// erroConfig indica falha ao carregar a configuração
+type erroConfig struct{ err error }
+
+func (e erroConfig) Error() string { return e.err.Error() }
+func (e erroConfig) Unwrap() error { return e.err }
+
+// comando é um subcomando da CLI
+type comando struct {
+	uso    string // Argumentos, para a ajuda ("<id> <status>")
+	resumo string
+	run    func(e *ambiente, args []string) error
+}
+
+var comandos = map[string]comando{
+	"list":   {"[--queue meus|nao-atribuidos|grupos|todos] [--filter texto] [--limit n]", "lista chamados", runList},
+	"show":   {"<id>", "mostra o chamado com atores e timeline", runShow},
+	"reply":  {"<id> [--message texto | --file arquivo]", "responde o chamado (Markdown; sem --message lê do stdin)", runReply},
+	"assign": {"<id>", "atribui o chamado ao usuário autenticado", runAssign},
+	"auth":   {"login|logout [--backend auto|keyring|file] [--token-only]", "guarda (ou apaga) senha e sessão no chaveiro do sistema ou em arquivo cifrado", runAuth},
+	"status": {"<id> <status>", "muda o status (novo, atribuido, planejado, pendente, solucionado, fechado ou o ID)", runStatus},
+	"create": {"--title texto [--content texto] [--type incidente|requisicao] [--urgency 1-5] [--category id] [--entity id]", "abre um chamado (sem --content lê a descrição do stdin)", runCreate},
+}
+
+// ambiente é o que os subcomandos compartilham: saídas, formato e o client já autenticado
+type ambiente struct {
+	ctx     context.Context
+	stdin   io.Reader
+	stdout  io.Writer
+	stderr  io.Writer
+	formato formato
+	perfil  string
+	cfg     *config.Config
+	client  api.Backend
+}
+
+// Run executa o subcomando em args[0] e devolve o código de saída do processo.
+// perfil é o --profile global ("" = GLPI_PROFILE ou default_profile); cada subcomando também aceita --profile.
+func Run(args []string, perfil string, stdin io.Reader, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" {
+		Usage(stdout)
+		return ExitOK
+	}
+	cmd, ok := comandos[args[0]]
+	if !ok {
+		fmt.Fprintf(stderr, "glpi-tui: subcomando desconhecido %q\n\n", args[0])
+		Usage(stderr)
+		return ExitUso
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	e := &ambiente{ctx: ctx, stdin: stdin, stdout: stdout, stderr: stderr, perfil: perfil}
+	err := cmd.run(e, args[1:])
+	if err == nil {
+		return ExitOK
+	}
+	if errors.Is(err, flag.ErrHelp) {
+		return ExitOK
+	}
+
+	fmt.Fprintf(stderr, "glpi-tui %s: %v\n", args[0], err)
+	var eu erroUso
+	if errors.As(err, &eu) {
+		fmt.Fprintf(stderr, "uso: glpi-tui %s %s\n", args[0], cmd.uso)
+	}
+	return codigoSaida(err)
+}
+
+// codigoSaida classifica o erro para scripts decidirem se tentam de novo
+func codigoSaida(err error) int {
+	var eu erroUso
+	var ec erroConfig
+	var se *api.StatusError
+	switch {
+	case errors.As(err, &eu):
+		return ExitUso
+	case errors.As(err, &ec):
+		return ExitConfig
+	case errors.As(err, &se) && (se.StatusCode == http.StatusUnauthorized || se.StatusCode == http.StatusForbidden):
+		return ExitAuth
+	case errors.As(err, &se) && se.What == "token" && se.StatusCode == http.StatusBadRequest:
+		// OAuth2 responde 400 (invalid_grant) para senha ou refresh token recusados
+		return ExitAuth
+	case errors.As(err, &se) && se.StatusCode == http.StatusNotFound:
+		return ExitNaoEncontrado
+	case api.IsTemporary(err):
+		return ExitTemporario
+	}
+	return ExitErro
+}
+
+// Usage escreve a ajuda geral do programa e dos subcomandos
+func Usage(w io.Writer) {
+	nomes := make([]string, 0, len(comandos))
+	for nome := range comandos {
+		nomes = append(nomes, nome)
+	}
+	sort.Strings(nomes)
+
+	fmt.Fprintln(w, "uso: glpi-tui [--profile nome]                      abre a interface interativa")
+	fmt.Fprintln(w, "     glpi-tui --demo                                   abre a interface com dados de demonstração, sem servidor")
+	fmt.Fprintln(w, "     glpi-tui [--profile nome] <subcomando> [flags]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "subcomandos:")
+	for _, nome := range nomes {
+		fmt.Fprintf(w, "  %-7s %s\n", nome, comandos[nome].resumo)
+		fmt.Fprintf(w, "          glpi-tui %s %s\n", nome, comandos[nome].uso)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "flags comuns: --output table|json|yaml (-o) • --profile nome")
+	fmt.Fprintf(w, "perfis: %s\n", config.FilePath())
+	fmt.Fprintf(w, "códigos de saída: %d ok • %d erro • %d uso • %d configuração • %d autenticação • %d não encontrado • %d temporário\n",
+		ExitOK, ExitErro, ExitUso, ExitConfig, ExitAuth, ExitNaoEncontrado, ExitTemporario)
+}
+
+// flags cria o FlagSet do subcomando já com --output/-o e --profile
+func (e *ambiente) flags(nome string) *flag.FlagSet {
+	fs := flag.NewFlagSet(nome, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Var(&e.formato, "output", "formato da saída: table, json ou yaml")
+	fs.Var(&e.formato, "o", "atalho para --output")
+	fs.StringVar(&e.perfil, "profile", e.perfil, "perfil do arquivo de configuração")
+	return fs
+}
+
+// parse interpreta flags e argumentos em qualquer ordem ("show 12 -o json" e "show -o json 12")
+func parse(fs *flag.FlagSet, args []string) ([]string, error) {
+	var pos []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			if errors.Is(err, flag.ErrHelp) {
+				return nil, err
+			}
+			return nil, erroUso{err.Error()}
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return pos, nil
+		}
+		if args[0] == "--" {
+			return append(pos, args[1:]...), nil
+		}
+		pos = append(pos, args[0])
+		args = args[1:]
+	}
+}
+
+// conectar carrega a configuração do perfil e faz o login
+func (e *ambiente) conectar() error {
+	cfg, err := config.LoadProfile(e.perfil)
+	if err != nil {
+		return erroConfig{err}
+	}
+	e.cfg = cfg
+	if e.client, err = api.New(cfg); err != nil {
+		return erroConfig{err}
+	}
+	e.client.SetInteract(func(instrucoes string) { fmt.Fprintln(e.stderr, instrucoes) })
+	return e.client.LoginContext(e.ctx)
+}
+
+// lerTexto devolve o valor da flag ou, se vazio, o conteúdo do arquivo ("-" = stdin) ou do stdin
+func (e *ambiente) lerTexto(valor, arquivo, oque string) (string, error) {
+	if valor != "" {
+		return valor, nil
+	}
+	var r io.Reader = e.stdin
+	if arquivo != "" && arquivo != "-" {
+		f, err := os.Open(arquivo)
+		if err != nil {
+			return "", usof("erro ao abrir %s: %v", arquivo, err)
+		}
+		defer f.Close()
+		r = f
+	}
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler %s: %w", oque, err)
+	}
+	texto := strings.TrimSpace(string(b))
+	if texto == "" {
+		return "", usof("%s vazia", oque)
+	}
+	return texto, nil
+}
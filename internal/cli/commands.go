@@ -0,0 +1,390 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"glpi-tui/internal/api"
+	"glpi-tui/internal/domain"
+)
+
+// statusAbertos são os status das filas de trabalho (mesmo recorte das abas da TUI)
+var statusAbertos = []int{domain.StatusNew, domain.StatusAssign, domain.StatusPlanned, domain.StatusPending}
+
+// chamadoOut é o chamado como aparece em JSON/YAML
+type chamadoOut struct {
+	ID         int    `json:"id" yaml:"id"`
+	Titulo     string `json:"name" yaml:"name"`
+	StatusID   int    `json:"status_id" yaml:"status_id"`
+	Status     string `json:"status" yaml:"status"`
+	Prioridade int    `json:"priority" yaml:"priority"`
+	EntidadeID int    `json:"entity_id" yaml:"entity_id"`
+	Entidade   string `json:"entity" yaml:"entity"`
+	Abertura   string `json:"date" yaml:"date"`
+	Alteracao  string `json:"date_mod" yaml:"date_mod"`
+}
+
+// detalhesOut é o chamado completo do "show"
+type detalhesOut struct {
+	chamadoOut `yaml:",inline"`
+	Descricao  string    `json:"content" yaml:"content"`
+	Atores     []atorOut `json:"actors" yaml:"actors"`
+	Timeline   []itemOut `json:"timeline" yaml:"timeline"`
+}
+
+type atorOut struct {
+	ID    int    `json:"id" yaml:"id"`
+	Nome  string `json:"name" yaml:"name"`
+	Tipo  string `json:"type" yaml:"type"`
+	Papel string `json:"role" yaml:"role"`
+}
+
+type itemOut struct {
+	Tipo     string `json:"type" yaml:"type"`
+	ID       int    `json:"id" yaml:"id"`
+	Data     string `json:"date" yaml:"date"`
+	Autor    string `json:"author" yaml:"author"`
+	Conteudo string `json:"content" yaml:"content"`
+}
+
+// resultadoOut é a resposta das ações (reply, assign, status, create)
+type resultadoOut struct {
+	TicketID int    `json:"ticket_id" yaml:"ticket_id"`
+	Acao     string `json:"action" yaml:"action"`
+	Status   string `json:"status,omitempty" yaml:"status,omitempty"`
+}
+
+func novoChamadoOut(t domain.Chamado) chamadoOut {
+	return chamadoOut{
+		ID:         t.ID,
+		Titulo:     t.Name,
+		StatusID:   t.Status.ID,
+		Status:     domain.StatusLabel(t.Status.ID),
+		Prioridade: t.Priority,
+		EntidadeID: t.Entity.ID,
+		Entidade:   t.Entity.Name,
+		Abertura:   t.Date,
+		Alteracao:  t.DateMod,
+	}
+}
+
+func runList(e *ambiente, args []string) error {
+	fs := e.flags("list")
+	fila := fs.String("queue", "todos", "fila: meus, nao-atribuidos, grupos ou todos")
+	filtro := fs.String("filter", "", "filtro no formato da barra da TUI: "+api.FilterHelp)
+	limite := fs.Int("limit", 50, "máximo de chamados (0 = todos)")
+	ordem := fs.String("sort", "", "ordenação property:direction (padrão date_mod:desc)")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usof("argumento inesperado: %s", pos[0])
+	}
+	if *limite < 0 {
+		return usof("--limit não pode ser negativo")
+	}
+	if err := e.conectar(); err != nil {
+		return err
+	}
+
+	escopo, err := e.escopoFila(*fila)
+	if err != nil {
+		return err
+	}
+	f, err := api.ParseFilter(*filtro, e.client.MyUserID())
+	if err != nil {
+		return usof("%v", err)
+	}
+
+	// Busca página a página até atingir o limite ou o fim da lista
+	var tickets []domain.Chamado
+	q := api.TicketQuery{Scope: escopo, Filter: f, Sort: *ordem, Limit: 100}
+	for {
+		if *limite > 0 {
+			q.Limit = min(100, *limite-len(tickets))
+		}
+		page, err := e.client.GetTicketsPageContext(e.ctx, q)
+		if err != nil {
+			return err
+		}
+		tickets = append(tickets, page.Tickets...)
+		if !page.HasMore() || len(page.Tickets) == 0 || (*limite > 0 && len(tickets) >= *limite) {
+			break
+		}
+		q.Start = page.Next()
+	}
+
+	out := make([]chamadoOut, len(tickets))
+	for i, t := range tickets {
+		out[i] = novoChamadoOut(t)
+	}
+	return e.imprimir(out, func(w io.Writer) error {
+		t := tabela{cabecalho: []string{"ID", "STATUS", "PRIO", "ALTERADO", "ENTIDADE", "TÍTULO"}}
+		for _, c := range tickets {
+			t.linhas = append(t.linhas, []string{
+				strconv.Itoa(c.ID), domain.StatusLabel(c.Status.ID), c.GetPriorityLabel(),
+				domain.FormatDate(c.DateMod), c.Entity.Name, c.Name,
+			})
+		}
+		return t.escrever(w)
+	})
+}
+
+// escopoFila traduz --queue no mesmo recorte das abas da TUI
+func (e *ambiente) escopoFila(fila string) (api.TicketFilter, error) {
+	switch strings.ToLower(fila) {
+	case "todos", "all":
+		return api.TicketFilter{}, nil
+	case "nao-atribuidos", "unassigned":
+		return api.TicketFilter{Unassigned: true, Statuses: statusAbertos}, nil
+	case "meus", "mine":
+		if err := e.client.GetMyIDContext(e.ctx); err != nil {
+			return api.TicketFilter{}, err
+		}
+		return api.TicketFilter{AssignedTo: e.client.MyUserID(), Statuses: statusAbertos}, nil
+	case "grupos", "groups":
+		if err := e.client.GetMyGroupsContext(e.ctx); err != nil {
+			return api.TicketFilter{}, err
+		}
+		if len(e.client.MyGroupIDs()) == 0 {
+			return api.TicketFilter{}, fmt.Errorf("você não pertence a nenhum grupo")
+		}
+		return api.TicketFilter{AssignedGroups: e.client.MyGroupIDs(), Statuses: statusAbertos}, nil
+	}
+	return api.TicketFilter{}, usof("fila desconhecida %q", fila)
+}
+
+func runShow(e *ambiente, args []string) error {
+	fs := e.flags("show")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := argID(pos, 1)
+	if err != nil {
+		return err
+	}
+	if err := e.conectar(); err != nil {
+		return err
+	}
+
+	t, err := e.client.GetTicketContext(e.ctx, id)
+	if err != nil {
+		return err
+	}
+	if t.Actors, err = e.client.GetTicketActorsContext(e.ctx, id); err != nil {
+		return err
+	}
+	items, err := e.client.GetTicketTimelineContext(e.ctx, id)
+	if err != nil {
+		return err
+	}
+	t.SetTimeline(items)
+
+	out := detalhesOut{chamadoOut: novoChamadoOut(t), Descricao: t.GetCleanContent(), Atores: []atorOut{}, Timeline: []itemOut{}}
+	for _, a := range t.Actors {
+		out.Atores = append(out.Atores, atorOut{ID: a.ID, Nome: a.Name, Tipo: a.Type, Papel: a.Role})
+	}
+	for _, it := range t.Timeline {
+		out.Timeline = append(out.Timeline, itemOut{
+			Tipo: string(it.Type), ID: it.ID(), Data: it.RawDate(), Autor: it.Author(), Conteudo: conteudoItem(it),
+		})
+	}
+
+	return e.imprimir(out, func(w io.Writer) error {
+		fmt.Fprintf(w, "#%d %s\n", t.ID, t.Name)
+		fmt.Fprintf(w, "Status: %s • Prioridade: %s • Entidade: %s\n", domain.StatusLabel(t.Status.ID), t.GetPriorityLabel(), t.Entity.Name)
+		fmt.Fprintf(w, "Aberto em: %s • Alterado em: %s\n", t.GetFormattedDate(), domain.FormatDate(t.DateMod))
+		fmt.Fprintf(w, "Requerente: %s\nTécnico: %s\n\n", t.GetRequesters(), t.GetTechnicians())
+		fmt.Fprintln(w, out.Descricao)
+		for _, it := range out.Timeline {
+			fmt.Fprintf(w, "\n--- %s #%d • %s • %s\n%s\n", it.Tipo, it.ID, domain.FormatDate(it.Data), it.Autor, it.Conteudo)
+		}
+		return nil
+	})
+}
+
+// conteudoItem devolve o texto puro do item da timeline
+func conteudoItem(it domain.TimelineItem) string {
+	switch it.Type {
+	case domain.TimelineFollowup:
+		return it.Followup.GetCleanContent()
+	case domain.TimelineTask:
+		return it.Task.GetCleanContent()
+	case domain.TimelineSolution:
+		return it.Solution.GetCleanContent()
+	case domain.TimelineDocument:
+		return it.Document.Filename
+	case domain.TimelineValidation:
+		return strings.TrimSpace(it.Validation.GetCleanSubmission() + "\n" + it.Validation.GetCleanValidation())
+	}
+	return ""
+}
+
+func runReply(e *ambiente, args []string) error {
+	fs := e.flags("reply")
+	msg := fs.String("message", "", "texto da resposta (Markdown)")
+	fs.StringVar(msg, "m", "", "atalho para --message")
+	arquivo := fs.String("file", "", "lê a resposta do arquivo (- = stdin)")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := argID(pos, 1)
+	if err != nil {
+		return err
+	}
+	texto, err := e.lerTexto(*msg, *arquivo, "resposta")
+	if err != nil {
+		return err
+	}
+	if err := e.conectar(); err != nil {
+		return err
+	}
+
+	if err := e.client.CreateTicketFollowupContext(e.ctx, id, texto); err != nil {
+		return err
+	}
+	return e.imprimir(resultadoOut{TicketID: id, Acao: "reply"}, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Resposta enviada no chamado #%d\n", id)
+		return err
+	})
+}
+
+func runAssign(e *ambiente, args []string) error {
+	fs := e.flags("assign")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := argID(pos, 1)
+	if err != nil {
+		return err
+	}
+	if err := e.conectar(); err != nil {
+		return err
+	}
+
+	if err := e.client.GetMyIDContext(e.ctx); err != nil {
+		return err
+	}
+	t, err := e.client.GetTicketContext(e.ctx, id)
+	if err != nil {
+		return err
+	}
+	to, err := domain.AssignStatus(t.Status.ID)
+	if err != nil {
+		return fmt.Errorf("chamado #%d: %w", id, err)
+	}
+	if err := e.client.AssignTicketViaUpdateContext(e.ctx, id, t.Entity.ID); err != nil {
+		return err
+	}
+	status := domain.StatusLabel(to)
+	return e.imprimir(resultadoOut{TicketID: id, Acao: "assign", Status: status}, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Chamado #%d atribuído a você (%s)\n", id, status)
+		return err
+	})
+}
+
+func runStatus(e *ambiente, args []string) error {
+	fs := e.flags("status")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	id, err := argID(pos, 2)
+	if err != nil {
+		return err
+	}
+	to, err := domain.ParseStatus(pos[1])
+	if err != nil {
+		return usof("%v", err)
+	}
+	if err := e.conectar(); err != nil {
+		return err
+	}
+
+	t, err := e.client.GetTicketContext(e.ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := domain.ValidateTransition(t.Status.ID, to); err != nil {
+		// Os argumentos estão certos; é o estado atual do chamado que não permite a troca
+		return fmt.Errorf("chamado #%d: %w", id, err)
+	}
+	if err := e.client.ChangeTicketStatusContext(e.ctx, id, t.Entity.ID, t.Status.ID, to); err != nil {
+		return err
+	}
+	status := domain.StatusLabel(to)
+	return e.imprimir(resultadoOut{TicketID: id, Acao: "status", Status: status}, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Chamado #%d: %s → %s\n", id, domain.StatusLabel(t.Status.ID), status)
+		return err
+	})
+}
+
+func runCreate(e *ambiente, args []string) error {
+	fs := e.flags("create")
+	titulo := fs.String("title", "", "título do chamado (obrigatório)")
+	descricao := fs.String("content", "", "descrição (sem ela, lida do stdin)")
+	arquivo := fs.String("file", "", "lê a descrição do arquivo (- = stdin)")
+	tipo := fs.String("type", "incidente", "incidente ou requisicao")
+	urgencia := fs.Int("urgency", 3, "urgência de 1 (muito baixa) a 5 (muito alta)")
+	categoria := fs.Int("category", 0, "ID da categoria (0 = sem categoria)")
+	entidade := fs.Int("entity", -1, "ID da entidade (0 = raiz; padrão: entity do perfil)")
+	pos, err := parse(fs, args)
+	if err != nil {
+		return err
+	}
+	if len(pos) > 0 {
+		return usof("argumento inesperado: %s", pos[0])
+	}
+
+	in := api.TicketInput{Name: *titulo, Urgency: *urgencia, CategoryID: *categoria, EntityID: max(*entidade, 0)}
+	switch strings.ToLower(*tipo) {
+	case "incidente", "incident":
+		in.Type = domain.TypeIncident
+	case "requisicao", "requisição", "request":
+		in.Type = domain.TypeRequest
+	default:
+		return usof("tipo desconhecido %q", *tipo)
+	}
+	if strings.TrimSpace(in.Name) == "" {
+		return usof("--title é obrigatório")
+	}
+	if in.Content, err = e.lerTexto(*descricao, *arquivo, "descrição"); err != nil {
+		return err
+	}
+	if err := in.Validate(); err != nil {
+		return usof("%v", err)
+	}
+	if err := e.conectar(); err != nil {
+		return err
+	}
+	if *entidade < 0 {
+		in.EntityID = e.cfg.DefaultEntity
+	}
+
+	id, err := e.client.CreateTicketContext(e.ctx, in)
+	if err != nil {
+		return err
+	}
+	return e.imprimir(resultadoOut{TicketID: id, Acao: "create"}, func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "Chamado #%d aberto\n", id)
+		return err
+	})
+}
+
+// argID confere a quantidade de argumentos posicionais e interpreta o primeiro como ID do chamado
+func argID(pos []string, n int) (int, error) {
+	if len(pos) != n {
+		return 0, usof("esperado(s) %d argumento(s), recebido(s) %d", n, len(pos))
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(pos[0], "#"))
+	if err != nil || id <= 0 {
+		return 0, usof("ID de chamado inválido: %q", pos[0])
+	}
+	return id, nil
+}
@@ -0,0 +1,79 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"gopkg.in/yaml.v3"
+)
+
+// formato é o valor de --output
+type formato string
+
+const (
+	formatoTabela formato = "table"
+	formatoJSON   formato = "json"
+	formatoYAML   formato = "yaml"
+)
+
+func (f *formato) String() string {
+	if *f == "" {
+		return string(formatoTabela)
+	}
+	return string(*f)
+}
+
+func (f *formato) Set(v string) error {
+	switch formato(strings.ToLower(v)) {
+	case formatoTabela, "text":
+		*f = formatoTabela
+	case formatoJSON:
+		*f = formatoJSON
+	case formatoYAML, "yml":
+		*f = formatoYAML
+	default:
+		return fmt.Errorf("formato inválido %q (use table, json ou yaml)", v)
+	}
+	return nil
+}
+
+// tabela é o que vai para a saída "table": cabeçalho e linhas alinhados em colunas
+type tabela struct {
+	cabecalho []string
+	linhas    [][]string
+}
+
+// imprimir escreve dados em JSON/YAML ou, no formato tabela, chama texto para desenhar a versão legível
+func (e *ambiente) imprimir(dados any, texto func(w io.Writer) error) error {
+	switch e.formato {
+	case formatoJSON:
+		enc := json.NewEncoder(e.stdout)
+		enc.SetIndent("", "  ")
+		return enc.Encode(dados)
+	case formatoYAML:
+		enc := yaml.NewEncoder(e.stdout)
+		enc.SetIndent(2)
+		if err := enc.Encode(dados); err != nil {
+			return err
+		}
+		return enc.Close()
+	}
+	return texto(e.stdout)
+}
+
+// escrever desenha a tabela com colunas separadas por espaços (fácil de usar com cut/awk)
+func (t tabela) escrever(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, strings.Join(t.cabecalho, "\t"))
+	for _, l := range t.linhas {
+		for i := range l {
+			// Tab ou quebra dentro do valor desalinharia as colunas
+			l[i] = strings.Join(strings.Fields(l[i]), " ")
+		}
+		fmt.Fprintln(tw, strings.Join(l, "\t"))
+	}
+	return tw.Flush()
+}
@@ -1,44 +1,345 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"glpi-tui/internal/secrets"
 
 	"github.com/joho/godotenv"
 )
 
 // Config segura todas as variáveis de ambiente da aplicação
 type Config struct {
-	BaseURL      string
-	ClientID     string
-	ClientSecret string
-	Username     string
-	Password     string
+	Profile       string // Perfil do arquivo de configuração em uso ("" = só variáveis de ambiente)
+	BaseURL       string
+	API           string // Versão da API do servidor: v1 (apirest.php) ou v2 (api.php/v2); "auto" (padrão) ou "url" escolhem no Load
+	AppToken      string // App-Token da API v1 (opcional, conforme a configuração do servidor)
+	ClientID      string
+	ClientSecret  string
+	Username      string
+	Password      string
+	RefreshToken  string // Sessão guardada pelo auth login (permite entrar sem a senha)
+	DefaultEntity int    // Entidade sugerida ao abrir chamados
+	Theme         string // Fundo do terminal para as cores adaptativas: auto, dark ou light
+	Secrets       string // Onde procurar senha/client_secret ausentes: auto, keyring ou file
+
+	// Autenticação: password (padrão) ou, para GLPI atrás de SSO, authorization_code (navegador
+	// com PKCE) e device_code (código digitado em outro aparelho). Os dois últimos nunca pedem senha.
+	Auth         string
+	AuthorizeURL string // Endpoint de autorização (padrão: BaseURL + "/authorize")
+	DeviceURL    string // Endpoint de device authorization (padrão: BaseURL + "/device")
+	RedirectPort int    // Porta local do retorno do navegador (0 = qualquer porta livre)
+
+	segredos secrets.Store // Backend de onde vieram os segredos (nil = tudo em texto puro)
+
+	// OnSaveError é avisado quando SaveRefreshToken não consegue guardar a sessão renovada
+	// (ex.: a TUI mostra um aviso). Pode ser chamado de qualquer goroutine.
+	OnSaveError func(error)
+
+	DownloadDir string // Pasta onde os anexos baixados são salvos
+	CacheDir    string // Pasta do cache offline ("" = cache desligado)
+
+	PollInterval time.Duration // Intervalo da busca de novidades em background (0 = desligada)
+	Bell         bool          // Toca o sino do terminal quando chegam novidades
 }
 
-// Load carrega as variáveis do .env e retorna um erro se algo faltar
+// Métodos de autenticação aceitos em "auth" no perfil ou em GLPI_AUTH
+const (
+	AuthPassword = "password"
+	AuthCode     = "authorization_code"
+	AuthDevice   = "device_code"
+)
+
+// Versões de API aceitas em "api" no perfil ou em GLPI_API
+const (
+	APIAuto  = "auto" // Padrão: pela URL quando ela diz, senão consultando o servidor (veja DetectAPI)
+	APIByURL = "url"  // Pelo caminho da URL, sem consultar o servidor (veja APIFromURL)
+	APIV1    = "v1"   // REST legada: apirest.php com initSession e Session-Token
+	APIV2    = "v2"   // High-Level API: api.php/v2 com OAuth2
+)
+
+// DefaultPollInterval é usado quando GLPI_POLL_INTERVAL não está definido
+const DefaultPollInterval = 2 * time.Minute
+
+// minPollInterval evita que um valor baixo demais sobrecarregue o servidor
+const minPollInterval = 15 * time.Second
+
+// Load carrega as variáveis do .env e retorna um erro se algo faltar.
+// O perfil vem de GLPI_PROFILE ou do default_profile do arquivo de configuração.
 func Load() (*Config, error) {
+	return LoadProfile("")
+}
+
+// LoadProfile é a variante de Load que usa o perfil pedido (ex.: --profile).
+// Com um perfil, servidor e credenciais vêm só do arquivo de configuração, para as credenciais
+// de um cliente nunca irem para o servidor de outro; as demais opções continuam nas variáveis GLPI_*.
+// Senha e client_secret ausentes são procurados no backend de segredos (ver SecretStore).
+func LoadProfile(name string) (*Config, error) {
+	return load(name, true)
+}
+
+// LoadProfileForLogin carrega o perfil sem exigir senha e client_secret: é o que o
+// "glpi-tui auth login" usa antes de pedi-los e guardá-los
+func LoadProfileForLogin(name string) (*Config, error) {
+	return load(name, false)
+}
+
+func load(name string, exigirSegredos bool) (*Config, error) {
 	// Carrega o .env, mas não falha se o arquivo não existir (pode estar rodando via Docker envs reais)
 	_ = godotenv.Load()
 
 	cfg := &Config{
 		BaseURL:      os.Getenv("GLPI_BASE_URL"),
+		API:          os.Getenv("GLPI_API"),
+		AppToken:     os.Getenv("GLPI_APP_TOKEN"),
 		ClientID:     os.Getenv("GLPI_CLIENT_ID"),
 		ClientSecret: os.Getenv("GLPI_CLIENT_SECRET"),
 		Username:     os.Getenv("GLPI_USER"),
 		Password:     os.Getenv("GLPI_PASS"),
+		DownloadDir:  os.Getenv("GLPI_DOWNLOAD_DIR"),
+		CacheDir:     os.Getenv("GLPI_CACHE_DIR"),
+		Secrets:      os.Getenv("GLPI_SECRETS"),
+		Auth:         os.Getenv("GLPI_AUTH"),
+		AuthorizeURL: os.Getenv("GLPI_AUTHORIZE_URL"),
+		DeviceURL:    os.Getenv("GLPI_DEVICE_URL"),
+	}
+	if v := os.Getenv("GLPI_REDIRECT_PORT"); v != "" {
+		porta, err := strconv.Atoi(v)
+		if err != nil || porta < 0 || porta > 65535 {
+			return nil, fmt.Errorf("GLPI_REDIRECT_PORT inválido (%q)", v)
+		}
+		cfg.RedirectPort = porta
+	}
+
+	arq, err := ReadFile()
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		name = os.Getenv("GLPI_PROFILE")
+	}
+	if name == "" && arq != nil {
+		name = arq.DefaultProfile
+	}
+	if name != "" {
+		if err := cfg.aplicarPerfil(arq, name); err != nil {
+			return nil, err
+		}
+	}
+
+	// Sem pasta configurada, usa ~/Downloads (ou o diretório atual se não houver home)
+	if cfg.DownloadDir == "" {
+		cfg.DownloadDir = "."
+		if home, err := os.UserHomeDir(); err == nil {
+			cfg.DownloadDir = filepath.Join(home, "Downloads")
+		}
+	}
+
+	// Cache offline: pasta de cache do sistema por padrão, "off" desliga
+	switch cfg.CacheDir {
+	case "off":
+		cfg.CacheDir = ""
+	case "":
+		if dir, err := os.UserCacheDir(); err == nil {
+			cfg.CacheDir = filepath.Join(dir, "glpi-tui")
+		}
+	}
+
+	// Busca de novidades: duração Go ("90s", "5m"); "off" ou "0" desliga
+	switch v := os.Getenv("GLPI_POLL_INTERVAL"); v {
+	case "":
+		cfg.PollInterval = DefaultPollInterval
+	case "off", "0":
+		cfg.PollInterval = 0
+	default:
+		d, err := time.ParseDuration(v)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("GLPI_POLL_INTERVAL inválido (%q): use algo como 90s ou 5m", v)
+		}
+		cfg.PollInterval = max(d, minPollInterval)
+	}
+
+	if v := os.Getenv("GLPI_BELL"); v != "" {
+		bell, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("GLPI_BELL inválido (%q): use true ou false", v)
+		}
+		cfg.Bell = bell
+	}
+
+	switch cfg.Auth {
+	case "":
+		cfg.Auth = AuthPassword
+	case AuthPassword, AuthCode, AuthDevice:
+	default:
+		return nil, fmt.Errorf("auth inválido (%q): use %s, %s ou %s", cfg.Auth, AuthPassword, AuthCode, AuthDevice)
+	}
+	if err := cfg.resolverAPI(); err != nil {
+		return nil, err
+	}
+	if cfg.API == APIV1 && cfg.Interactive() {
+		return nil, fmt.Errorf("a API v1 (apirest.php) só aceita auth %s", AuthPassword)
+	}
+	if cfg.AuthorizeURL == "" {
+		cfg.AuthorizeURL = cfg.BaseURL + "/authorize"
+	}
+	if cfg.DeviceURL == "" {
+		cfg.DeviceURL = cfg.BaseURL + "/device"
+	}
+
+	switch cfg.Theme {
+	case "":
+		cfg.Theme = "auto"
+	case "auto", "dark", "light":
+	default:
+		return nil, fmt.Errorf("perfil %q: theme inválido (%q): use auto, dark ou light", cfg.Profile, cfg.Theme)
 	}
 
 	// Validação simples para garantir que não vamos tentar rodar sem credenciais
-	if cfg.BaseURL == "" {
-		return nil, fmt.Errorf("GLPI_BASE_URL é obrigatório")
+	if cfg.Profile != "" {
+		if cfg.BaseURL == "" || cfg.Username == "" || (cfg.ClientID == "" && cfg.API == APIV2) {
+			return nil, fmt.Errorf("perfil %q incompleto em %s: url, client_id e user são obrigatórios", cfg.Profile, FilePath())
+		}
+	} else {
+		if cfg.BaseURL == "" {
+			if arq != nil && len(arq.Profiles) > 0 {
+				return nil, fmt.Errorf("GLPI_BASE_URL é obrigatório (ou escolha um perfil com --profile: %s)", strings.Join(arq.Names(), ", "))
+			}
+			return nil, fmt.Errorf("GLPI_BASE_URL é obrigatório")
+		}
+		if cfg.ClientID == "" && cfg.API == APIV2 {
+			return nil, fmt.Errorf("GLPI_CLIENT_ID é obrigatório")
+		}
+		if cfg.Username == "" {
+			return nil, fmt.Errorf("GLPI_USER é obrigatório")
+		}
+	}
+	if !exigirSegredos {
+		return cfg, nil
 	}
-	if cfg.ClientID == "" || cfg.ClientSecret == "" {
-		return nil, fmt.Errorf("GLPI_CLIENT_ID e GLPI_CLIENT_SECRET são obrigatórios")
+
+	if cfg.Interactive() {
+		// Sem senha: só a sessão guardada (e o client_secret, se o cliente OAuth tiver um).
+		// Sem sessão o login abre o navegador ou mostra o código na hora.
+		if err := cfg.resolverSegredos(); err != nil {
+			return nil, err
+		}
+		return cfg, nil
+	}
+	if cfg.Password == "" || cfg.ClientSecret == "" {
+		if err := cfg.resolverSegredos(); err != nil {
+			return nil, err
+		}
 	}
-	if cfg.Username == "" || cfg.Password == "" {
-		return nil, fmt.Errorf("usuário e senha são obrigatórios")
+	if cfg.API == APIV1 {
+		// Sem OAuth: o initSession só precisa de usuário e senha (o App-Token fica no perfil)
+		if cfg.Password == "" {
+			return nil, fmt.Errorf("senha de %s em %s não encontrada: rode glpi-tui auth login (ou defina GLPI_PASS)", cfg.Username, cfg.BaseURL)
+		}
+		return cfg, nil
+	}
+	if cfg.ClientSecret == "" || (cfg.Password == "" && cfg.RefreshToken == "") {
+		return nil, fmt.Errorf("senha e client_secret de %s em %s não encontrados: rode glpi-tui auth login (ou defina GLPI_PASS e GLPI_CLIENT_SECRET)", cfg.Username, cfg.BaseURL)
 	}
 
 	return cfg, nil
 }
+
+// resolverSegredos completa senha, client_secret e refresh token com o que o auth login guardou
+func (cfg *Config) resolverSegredos() error {
+	store, err := cfg.SecretStore()
+	if err != nil {
+		return err
+	}
+	for _, s := range []struct {
+		campo *string
+		tipo  string
+	}{
+		{&cfg.Password, secrets.Password},
+		{&cfg.ClientSecret, secrets.ClientSecret},
+		{&cfg.RefreshToken, secrets.RefreshToken},
+	} {
+		if *s.campo != "" {
+			continue
+		}
+		v, err := store.Get(cfg.SecretKey(s.tipo))
+		if err != nil && !errors.Is(err, secrets.ErrNotFound) {
+			return fmt.Errorf("erro ao ler segredos do %s: %w", store.Name(), err)
+		}
+		*s.campo = v
+	}
+	cfg.segredos = store
+	return nil
+}
+
+// Interactive diz se o login depende do usuário no navegador ou em outro aparelho
+func (cfg *Config) Interactive() bool {
+	return cfg.Auth == AuthCode || cfg.Auth == AuthDevice
+}
+
+// SecretStore abre o backend de segredos configurado em secrets (perfil) ou GLPI_SECRETS
+func (cfg *Config) SecretStore() (secrets.Store, error) {
+	return secrets.Open(cfg.Secrets)
+}
+
+// SecretKey identifica no backend um segredo desta conta (servidor + usuário)
+func (cfg *Config) SecretKey(kind string) string {
+	return secrets.Key(cfg.BaseURL, cfg.Username, kind)
+}
+
+// SaveRefreshToken guarda a sessão renovada junto dos demais segredos, para o próximo início
+// não precisar da senha. Sem backend de segredos (tudo em texto puro) não faz nada.
+// Falhas também vão para OnSaveError, já que quem renova o token costuma só seguir em frente.
+func (cfg *Config) SaveRefreshToken(token string) error {
+	if cfg.segredos == nil || token == "" {
+		return nil
+	}
+	cfg.RefreshToken = token
+	err := cfg.segredos.Set(cfg.SecretKey(secrets.RefreshToken), token)
+	if err != nil && cfg.OnSaveError != nil {
+		cfg.OnSaveError(err)
+	}
+	return err
+}
+
+// UnlockSecrets pede já a frase-senha do arquivo cifrado, se for ele o backend, para a sessão
+// renovada poder ser guardada depois sem usar o terminal (veja secrets.Unlock)
+func (cfg *Config) UnlockSecrets() error {
+	if cfg.segredos == nil {
+		return nil
+	}
+	return secrets.Unlock(cfg.segredos)
+}
+
+// aplicarPerfil troca servidor e credenciais pelos do perfil
+func (cfg *Config) aplicarPerfil(arq *File, name string) error {
+	if arq == nil {
+		return fmt.Errorf("perfil %q pedido, mas %s não existe", name, FilePath())
+	}
+	p, ok := arq.Profiles[name]
+	if !ok {
+		return fmt.Errorf("perfil %q não existe em %s (disponíveis: %s)", name, FilePath(), strings.Join(arq.Names(), ", "))
+	}
+	cfg.Profile = name
+	cfg.BaseURL = strings.TrimRight(p.URL, "/")
+	cfg.API = p.API
+	cfg.AppToken = p.AppToken
+	cfg.ClientID = p.ClientID
+	cfg.ClientSecret = p.ClientSecret
+	cfg.Username = p.User
+	cfg.Password = p.Password
+	cfg.DefaultEntity = p.Entity
+	cfg.Theme = p.Theme
+	cfg.Secrets = p.Secrets
+	cfg.Auth = p.Auth
+	cfg.AuthorizeURL = p.AuthorizeURL
+	cfg.DeviceURL = p.DeviceURL
+	cfg.RedirectPort = p.RedirectPort
+	return nil
+}
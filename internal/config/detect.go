@@ -0,0 +1,116 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// detectTimeout limita cada consulta de DetectAPI, para um servidor fora do ar não travar o início
+const detectTimeout = 5 * time.Second
+
+// errInacessivel marca as falhas de DetectAPI em que o servidor nem chegou a responder
+var errInacessivel = errors.New("servidor inacessível")
+
+// APIFromURL escolhe a versão da API só pelo caminho: .../apirest.php é a v1, o resto
+// (.../api.php/v2) a v2. O servidor não é consultado; com um proxy que esconda o caminho,
+// use api: auto ou defina api: v1 ou api: v2 no perfil (ou GLPI_API).
+func APIFromURL(baseURL string) string {
+	if strings.Contains(strings.ToLower(baseURL), "apirest.php") {
+		return APIV1
+	}
+	return APIV2
+}
+
+// urlConclusiva diz se o caminho da URL já nomeia o ponto de entrada de uma das APIs
+func urlConclusiva(baseURL string) bool {
+	u := strings.ToLower(baseURL)
+	return strings.Contains(u, "apirest.php") || strings.Contains(u, "api.php")
+}
+
+// DetectAPI pergunta ao servidor qual API ele fala. A v1 responde ao GET /initSession sem
+// credenciais com uma lista ["ERROR_...", "mensagem"] (em geral ERROR_LOGIN_PARAMETERS_MISSING);
+// a v2 responde ao POST /token vazio com o erro OAuth {"error": "..."}.
+func DetectAPI(ctx context.Context, baseURL, appToken string) (string, error) {
+	base := strings.TrimRight(baseURL, "/")
+	hc := &http.Client{Timeout: detectTimeout}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", base+"/initSession", nil)
+	if err != nil {
+		return "", fmt.Errorf("erro na URL: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if appToken != "" {
+		req.Header.Set("App-Token", appToken)
+	}
+	corpo, err := sondar(hc, req)
+	if err != nil {
+		return "", err
+	}
+	var lista []any
+	if json.Unmarshal(corpo, &lista) == nil && len(lista) > 0 {
+		if codigo, ok := lista[0].(string); ok && strings.HasPrefix(codigo, "ERROR") {
+			return APIV1, nil
+		}
+	}
+
+	req, err = http.NewRequestWithContext(ctx, "POST", base+"/token", strings.NewReader(url.Values{}.Encode()))
+	if err != nil {
+		return "", fmt.Errorf("erro na URL: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if corpo, err = sondar(hc, req); err != nil {
+		return "", err
+	}
+	var oauth struct {
+		Error string `json:"error"`
+	}
+	if json.Unmarshal(corpo, &oauth) == nil && oauth.Error != "" {
+		return APIV2, nil
+	}
+	return "", fmt.Errorf("%s não respondeu como a API v1 (initSession) nem como a v2 (token): defina api: v1 ou api: v2", baseURL)
+}
+
+// sondar faz a requisição e devolve o corpo, qualquer que seja o status HTTP
+func sondar(hc *http.Client, req *http.Request) ([]byte, error) {
+	resp, err := hc.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w ao identificar a API de %s: %w", errInacessivel, req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+}
+
+// resolverAPI preenche cfg.API conforme o modo pedido. Em auto, uma URL que já diga a API é
+// seguida sem consultar o servidor; as demais (proxy, caminho reescrito) passam por DetectAPI.
+// Servidor inacessível cai na escolha pela URL, para o programa ainda abrir com o cache offline.
+func (cfg *Config) resolverAPI() error {
+	switch cfg.API {
+	case "", APIAuto:
+		if cfg.BaseURL == "" || urlConclusiva(cfg.BaseURL) {
+			cfg.API = APIFromURL(cfg.BaseURL)
+			return nil
+		}
+		api, err := DetectAPI(context.Background(), cfg.BaseURL, cfg.AppToken)
+		if errors.Is(err, errInacessivel) {
+			api, err = APIFromURL(cfg.BaseURL), nil
+		}
+		if err != nil {
+			return err
+		}
+		cfg.API = api
+	case APIByURL:
+		cfg.API = APIFromURL(cfg.BaseURL)
+	case APIV1, APIV2:
+	default:
+		return fmt.Errorf("api inválida (%q): use %s, %s, %s ou %s", cfg.API, APIAuto, APIByURL, APIV1, APIV2)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"glpi-tui/internal/glpimock"
+)
+
+func TestDetectAPI(t *testing.T) {
+	v1 := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/glpi/initSession" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `["ERROR_LOGIN_PARAMETERS_MISSING","parâmetros de login ausentes"]`)
+	})
+	outro := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "<html>página de login</html>")
+	})
+
+	casos := []struct {
+		nome    string
+		handler http.Handler
+		prefixo string
+		api     string
+	}{
+		{"v1 atrás de proxy", v1, "/glpi", APIV1},
+		{"v2", glpimock.New(glpimock.DefaultFixture()), "", APIV2},
+		{"nenhuma das duas", outro, "", ""},
+	}
+	for _, tc := range casos {
+		t.Run(tc.nome, func(t *testing.T) {
+			hs := httptest.NewServer(tc.handler)
+			defer hs.Close()
+
+			api, err := DetectAPI(context.Background(), hs.URL+tc.prefixo, "")
+			if tc.api == "" {
+				if err == nil {
+					t.Errorf("API = %q, esperado erro", api)
+				}
+				return
+			}
+			if err != nil || api != tc.api {
+				t.Errorf("API = %q (%v), esperado %q", api, err, tc.api)
+			}
+
+			// Em auto a URL sem apirest.php nem api.php passa pela consulta
+			cfg := &Config{BaseURL: hs.URL + tc.prefixo, API: APIAuto}
+			if err := cfg.resolverAPI(); err != nil || cfg.API != tc.api {
+				t.Errorf("resolverAPI: API = %q (%v), esperado %q", cfg.API, err, tc.api)
+			}
+		})
+	}
+}
+
+func TestAPIAutoSemServidorUsaURL(t *testing.T) {
+	hs := httptest.NewServer(http.NotFoundHandler())
+	url := hs.URL + "/glpi"
+	hs.Close() // Ninguém mais escuta na porta
+
+	cfg := &Config{BaseURL: url}
+	if err := cfg.resolverAPI(); err != nil || cfg.API != APIV2 {
+		t.Errorf("API = %q (%v), esperado a escolha pela URL (%s)", cfg.API, err, APIV2)
+	}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// File é o arquivo de configuração com os perfis de cada servidor GLPI.
+// Fica em GLPI_CONFIG ou, por padrão, em <pasta de configuração do usuário>/glpi-tui/config.yaml:
+//
+//	default_profile: cliente-a
+//	profiles:
+//	  cliente-a:
+//	    url: https://glpi.cliente-a.com.br/api.php/v2
+//	    client_id: ...
+//	    user: joao
+//	    entity: 3      # entidade sugerida ao abrir chamados
+//	    theme: dark    # auto, dark ou light
+//	    secrets: auto  # onde ficam senha e client_secret: auto, keyring ou file
+//	  cliente-b:
+//	    url: https://suporte.cliente-b.com/api.php/v2
+//	    client_id: ...
+//	    user: joao@cliente-b.com
+//	    auth: authorization_code  # SSO pelo navegador (ou device_code); a senha nunca passa pelo programa
+//	    redirect_port: 8765       # se o servidor exigir a porta exata do redirect_uri
+//	  legado:
+//	    url: https://glpi.antigo.com.br/apirest.php  # API REST v1 (GLPI 9.x/10.x), escolhida pela URL
+//	    app_token: ...                                # se o cliente de API do servidor exigir
+//	    user: joao
+//
+// password e client_secret também podem ficar no arquivo, mas o recomendado é guardá-los com
+// "glpi-tui auth login" no chaveiro do sistema ou no arquivo cifrado.
+type File struct {
+	DefaultProfile string             `yaml:"default_profile"`
+	Profiles       map[string]Profile `yaml:"profiles"`
+}
+
+// Profile é um servidor GLPI com as credenciais e preferências de acesso
+type Profile struct {
+	URL          string `yaml:"url"`
+	API          string `yaml:"api"`       // auto (padrão: pela URL ou consultando o servidor), url, v1 ou v2
+	AppToken     string `yaml:"app_token"` // App-Token da API v1, se o servidor exigir
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	User         string `yaml:"user"`
+	Password     string `yaml:"password"`
+	Entity       int    `yaml:"entity"`
+	Theme        string `yaml:"theme"`
+	Secrets      string `yaml:"secrets"` // auto, keyring ou file (ver glpi-tui auth login)
+
+	Auth         string `yaml:"auth"` // password, authorization_code ou device_code
+	AuthorizeURL string `yaml:"authorize_url"`
+	DeviceURL    string `yaml:"device_url"`
+	RedirectPort int    `yaml:"redirect_port"`
+}
+
+// FilePath devolve onde o arquivo de configuração é procurado
+func FilePath() string {
+	if p := os.Getenv("GLPI_CONFIG"); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "config.yaml"
+	}
+	return filepath.Join(dir, "glpi-tui", "config.yaml")
+}
+
+// ReadFile lê o arquivo de configuração; sem arquivo devolve nil sem erro
+func ReadFile() (*File, error) {
+	path := FilePath()
+	b, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler %s: %w", path, err)
+	}
+
+	var f File
+	if err := yaml.Unmarshal(b, &f); err != nil {
+		return nil, fmt.Errorf("%s inválido: %w", path, err)
+	}
+	if f.DefaultProfile != "" {
+		if _, ok := f.Profiles[f.DefaultProfile]; !ok {
+			return nil, fmt.Errorf("%s: default_profile %q não está em profiles", path, f.DefaultProfile)
+		}
+	}
+	return &f, nil
+}
+
+// Names devolve os nomes dos perfis em ordem alfabética
+func (f *File) Names() []string {
+	if f == nil {
+		return nil
+	}
+	nomes := make([]string, 0, len(f.Profiles))
+	for nome := range f.Profiles {
+		nomes = append(nomes, nome)
+	}
+	sort.Strings(nomes)
+	return nomes
+}
@@ -2,11 +2,11 @@ package domain
 
 import (
 	"fmt"
-	"html"
-	"regexp"
 	"strings"
 	"time"
 
+	"glpi-tui/internal/richtext"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
@@ -19,6 +19,29 @@ const (
 	StatusClosed  = 6
 )
 
+// Tipos de chamado do GLPI
+const (
+	TypeIncident = 1
+	TypeRequest  = 2
+)
+
+// TypeLabel devolve o nome do tipo de chamado
+func TypeLabel(t int) string {
+	switch t {
+	case TypeIncident:
+		return "Incidente"
+	case TypeRequest:
+		return "Requisição"
+	default:
+		return fmt.Sprintf("Tipo %d", t)
+	}
+}
+
+// UrgencyLabel devolve o nome da urgência (mesma escala de 1 a 5 da prioridade)
+func UrgencyLabel(u int) string {
+	return Chamado{Priority: u}.GetPriorityLabel()
+}
+
 // TicketStatus representa o objeto de status retornado pela API High-Level
 type TicketStatus struct {
 	ID   int    `json:"id"`
@@ -56,6 +79,7 @@ type Chamado struct {
 	Name     string       `json:"name"`
 	Content  string       `json:"content"`
 	Date     string       `json:"date"`
+	DateMod  string       `json:"date_mod"`
 	Status   TicketStatus `json:"status"`
 	Priority int          `json:"priority"`
 
@@ -65,6 +89,10 @@ type Chamado struct {
 	// Campos carregados sob demanda
 	Actors    []TicketActor    `json:"-"`
 	Followups []TicketFollowup `json:"-"`
+	Solutions []TicketSolution `json:"-"`
+	Tasks     []TicketTask     `json:"-"`
+	Documents []TicketDocument `json:"-"`
+	Timeline  []TimelineItem   `json:"-"` // Todos os itens acima, do mais recente para o mais antigo
 }
 
 func (c Chamado) Title() string { return c.Name }
@@ -80,7 +108,20 @@ func (c Chamado) FilterValue() string { return c.Name }
 
 func (c Chamado) getStatusInfo() (string, lipgloss.Color) {
 	// O switch agora verifica o ID dentro do objeto Status
-	switch c.Status.ID {
+	if label, cor := statusInfo(c.Status.ID); label != "" {
+		return label, cor
+	}
+	// Fallback usando o Nome retornado pela API se houver
+	name := c.Status.Name
+	if name == "" {
+		name = fmt.Sprintf("Status %d", c.Status.ID)
+	}
+	return name, lipgloss.Color("#888888")
+}
+
+// statusInfo devolve nome e cor dos status conhecidos (nome vazio = desconhecido)
+func statusInfo(id int) (string, lipgloss.Color) {
+	switch id {
 	case StatusNew:
 		return "Novo", lipgloss.Color("#04B575")
 	case StatusAssign:
@@ -94,15 +135,19 @@ func (c Chamado) getStatusInfo() (string, lipgloss.Color) {
 	case StatusClosed:
 		return "Fechado", lipgloss.Color("#000000")
 	default:
-		// Fallback usando o Nome retornado pela API se houver
-		name := c.Status.Name
-		if name == "" {
-			name = fmt.Sprintf("Status %d", c.Status.ID)
-		}
-		return name, lipgloss.Color("#888888")
+		return "", ""
 	}
 }
 
+// StatusColor devolve a cor usada para o status nas telas
+func StatusColor(id int) lipgloss.Color {
+	_, cor := statusInfo(id)
+	if cor == "" {
+		return lipgloss.Color("#888888")
+	}
+	return cor
+}
+
 func (c Chamado) GetPriorityLabel() string {
 	switch c.Priority {
 	case 1:
@@ -146,22 +191,19 @@ func (c Chamado) GetFormattedDate() string {
 	return t.Format("02/01/06 15:04")
 }
 
+// GetCleanContent devolve a descrição em texto puro (sem estilos nem quebra automática)
 func (c Chamado) GetCleanContent() string {
-	text := c.Content
-	text = strings.ReplaceAll(text, "&lt;", "<")
-	text = strings.ReplaceAll(text, "&gt;", ">")
-	text = strings.ReplaceAll(text, "&amp;", "&")
-	text = strings.ReplaceAll(text, "<br>", "\n")
-	text = strings.ReplaceAll(text, "<br/>", "\n")
-	text = strings.ReplaceAll(text, "<br />", "\n")
-	text = strings.ReplaceAll(text, "</p>", "\n\n")
-
-	// Remove todas as tags HTML
-	re := regexp.MustCompile(`<[^>]*>`)
-	text = re.ReplaceAllString(text, "")
+	return richtext.PlainText(c.Content)
+}
 
-	text = html.UnescapeString(text)
-	return strings.TrimSpace(text)
+// PendingSolution devolve a solução aguardando aprovação, se houver
+func (c Chamado) PendingSolution() *TicketSolution {
+	for i := range c.Solutions {
+		if c.Solutions[i].IsPendingApproval() {
+			return &c.Solutions[i]
+		}
+	}
+	return nil
 }
 
 // GetRequesters retorna uma string formatada com os nomes dos requerentes
@@ -192,21 +234,9 @@ func (c Chamado) GetTechnicians() string {
 	return strings.Join(names, ", ")
 }
 
+// GetCleanContent devolve o acompanhamento em texto puro (sem estilos nem quebra automática)
 func (f TicketFollowup) GetCleanContent() string {
-	// Reutiliza a lógica de limpeza que já temos (poderíamos refatorar para uma função utilitária global, mas por agora duplicamos a lógica simples para manter isolamento)
-	text := f.Content
-	text = strings.ReplaceAll(text, "&lt;", "<")
-	text = strings.ReplaceAll(text, "&gt;", ">")
-	text = strings.ReplaceAll(text, "&amp;", "&")
-	text = strings.ReplaceAll(text, "<br>", "\n")
-	text = strings.ReplaceAll(text, "<br/>", "\n")
-	text = strings.ReplaceAll(text, "</p>", "\n\n")
-
-	re := regexp.MustCompile(`<[^>]*>`)
-	text = re.ReplaceAllString(text, "")
-
-	text = html.UnescapeString(text)
-	return strings.TrimSpace(text)
+	return richtext.PlainText(f.Content)
 }
 
 func (f TicketFollowup) GetFormattedDate() string {
@@ -0,0 +1,67 @@
+package domain
+
+import "glpi-tui/internal/richtext"
+
+// Status de aprovação de uma solução (ITILSolution)
+const (
+	SolutionStatusNone     = 1 // Sem aprovação necessária
+	SolutionStatusWaiting  = 2 // Aguardando aprovação do requerente
+	SolutionStatusAccepted = 3
+	SolutionStatusRefused  = 4
+)
+
+// SolutionType representa um tipo de solução (dropdown SolutionType)
+type SolutionType struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// SolutionTemplate representa um modelo de solução (dropdown SolutionTemplate)
+type SolutionTemplate struct {
+	ID           int          `json:"id"`
+	Name         string       `json:"name"`
+	Content      string       `json:"content"`
+	SolutionType SolutionType `json:"solutiontype"`
+}
+
+// TicketSolution representa uma solução registrada na timeline
+// Endpoint: GET /Assistance/Ticket/{id}/Timeline/Solution
+type TicketSolution struct {
+	ID      int                `json:"id"`
+	Date    string             `json:"date_creation"`
+	Content string             `json:"content"`
+	Type    SolutionType       `json:"solutiontype"`
+	Status  int                `json:"status"`
+	User    TicketFollowupUser `json:"user"`
+}
+
+// IsPendingApproval indica se a solução ainda espera o aceite do requerente
+func (s TicketSolution) IsPendingApproval() bool {
+	return s.Status == SolutionStatusWaiting
+}
+
+// StatusLabel devolve o nome do status de aprovação
+func (s TicketSolution) StatusLabel() string {
+	switch s.Status {
+	case SolutionStatusWaiting:
+		return "Aguardando aprovação"
+	case SolutionStatusAccepted:
+		return "Aprovada"
+	case SolutionStatusRefused:
+		return "Recusada"
+	default:
+		return ""
+	}
+}
+
+func (s TicketSolution) GetCleanContent() string {
+	return richtext.PlainText(s.Content)
+}
+
+func (s TicketSolution) GetFormattedDate() string {
+	return FormatDate(s.Date)
+}
+
+func (t SolutionTemplate) GetCleanContent() string {
+	return richtext.PlainText(t.Content)
+}
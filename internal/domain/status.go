@@ -0,0 +1,120 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// StatusTransition é um destino permitido a partir de um status
+type StatusTransition struct {
+	To     int
+	Reopen bool // true quando o movimento reabre um chamado solucionado/fechado
+}
+
+// statusTransitions é a máquina de estados de status do chamado no GLPI.
+// Chamados solucionados ou fechados só voltam a andar por reabertura.
+var statusTransitions = map[int][]StatusTransition{
+	StatusNew: {
+		{To: StatusAssign}, {To: StatusPlanned}, {To: StatusPending}, {To: StatusSolved}, {To: StatusClosed},
+	},
+	StatusAssign: {
+		{To: StatusPlanned}, {To: StatusPending}, {To: StatusSolved}, {To: StatusClosed},
+	},
+	StatusPlanned: {
+		{To: StatusAssign}, {To: StatusPending}, {To: StatusSolved}, {To: StatusClosed},
+	},
+	StatusPending: {
+		{To: StatusAssign}, {To: StatusPlanned}, {To: StatusSolved}, {To: StatusClosed},
+	},
+	StatusSolved: {
+		{To: StatusClosed}, {To: StatusAssign, Reopen: true}, {To: StatusNew, Reopen: true},
+	},
+	StatusClosed: {
+		{To: StatusAssign, Reopen: true}, {To: StatusNew, Reopen: true},
+	},
+}
+
+// NextStatuses devolve os status para os quais um chamado em "from" pode ir
+func NextStatuses(from int) []StatusTransition {
+	return statusTransitions[from]
+}
+
+// CanTransition indica se o movimento from -> to é permitido
+func CanTransition(from, to int) bool {
+	for _, t := range statusTransitions[from] {
+		if t.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidateTransition devolve um erro descritivo quando o movimento não é permitido
+func ValidateTransition(from, to int) error {
+	if from == to {
+		return fmt.Errorf("o chamado já está como %s", StatusLabel(to))
+	}
+	if !CanTransition(from, to) {
+		return fmt.Errorf("não é permitido mudar de %s para %s", StatusLabel(from), StatusLabel(to))
+	}
+	return nil
+}
+
+// AssignStatus devolve o status que o chamado em from passa a ter quando ganha um técnico
+// ("atribuir a mim"): Atribuído se a máquina de estados permitir sem reabrir, o próprio from se
+// já estiver atribuído. Solucionados e fechados são recusados, porque a atribuição os reabriria.
+func AssignStatus(from int) (int, error) {
+	if from == StatusAssign {
+		return from, nil
+	}
+	for _, t := range statusTransitions[from] {
+		if t.To != StatusAssign {
+			continue
+		}
+		if t.Reopen {
+			return 0, fmt.Errorf("o chamado está %s; reabra antes de atribuir", strings.ToLower(StatusLabel(from)))
+		}
+		return StatusAssign, nil
+	}
+	return 0, ValidateTransition(from, StatusAssign)
+}
+
+// StatusLabel devolve o nome em português do status
+func StatusLabel(id int) string {
+	label, _ := statusInfo(id)
+	if label == "" {
+		return fmt.Sprintf("Status %d", id)
+	}
+	return label
+}
+
+// statusByName traduz nomes de status (português, com ou sem acento, e inglês) para os IDs do GLPI
+var statusByName = map[string]int{
+	"novo":        StatusNew,
+	"atribuido":   StatusAssign,
+	"atribuído":   StatusAssign,
+	"planejado":   StatusPlanned,
+	"pendente":    StatusPending,
+	"solucionado": StatusSolved,
+	"fechado":     StatusClosed,
+
+	"new":      StatusNew,
+	"assigned": StatusAssign,
+	"planned":  StatusPlanned,
+	"pending":  StatusPending,
+	"solved":   StatusSolved,
+	"closed":   StatusClosed,
+}
+
+// ParseStatus aceita o nome do status ("pendente", "solved") ou o ID numérico
+func ParseStatus(s string) (int, error) {
+	if id, ok := statusByName[strings.ToLower(strings.TrimSpace(s))]; ok {
+		return id, nil
+	}
+	id, err := strconv.Atoi(s)
+	if err != nil || statusTransitions[id] == nil {
+		return 0, fmt.Errorf("status desconhecido: %q", s)
+	}
+	return id, nil
+}
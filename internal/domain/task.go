@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"glpi-tui/internal/richtext"
+)
+
+// Estados de uma tarefa (TicketTask)
+const (
+	TaskStateInformation = 0
+	TaskStateTodo        = 1
+	TaskStateDone        = 2
+)
+
+// TaskStateLabel devolve o nome do estado da tarefa
+func TaskStateLabel(state int) string {
+	switch state {
+	case TaskStateInformation:
+		return "Informação"
+	case TaskStateTodo:
+		return "A fazer"
+	case TaskStateDone:
+		return "Feita"
+	default:
+		return fmt.Sprintf("Estado %d", state)
+	}
+}
+
+// TicketTask representa uma tarefa da timeline, usada para registrar esforço
+// Endpoint: GET /Assistance/Ticket/{id}/Timeline/Task
+type TicketTask struct {
+	ID         int                `json:"id"`
+	Date       string             `json:"date"`
+	Content    string             `json:"content"`
+	ActionTime int                `json:"actiontime"` // Tempo gasto, em segundos
+	State      int                `json:"state"`
+	User       TicketFollowupUser `json:"user"`      // Quem registrou
+	Technician TicketFollowupUser `json:"user_tech"` // Técnico responsável
+	BeginPlan  string             `json:"begin"`     // Início planejado (vazio = sem planejamento)
+	EndPlan    string             `json:"end"`       // Fim planejado
+}
+
+// Duration devolve o tempo gasto na tarefa
+func (t TicketTask) Duration() time.Duration {
+	return time.Duration(t.ActionTime) * time.Second
+}
+
+// FormatDuration mostra a duração como "1h30" / "45min"
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Minute)
+	h := int(d.Hours())
+	m := int(d.Minutes()) % 60
+	switch {
+	case h > 0 && m > 0:
+		return fmt.Sprintf("%dh%02d", h, m)
+	case h > 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dmin", m)
+	}
+}
+
+func (t TicketTask) GetCleanContent() string {
+	return richtext.PlainText(t.Content)
+}
+
+func (t TicketTask) GetFormattedDate() string {
+	return FormatDate(t.Date)
+}
+
+// GetPlanning devolve o intervalo planejado formatado (vazio quando não planejada)
+func (t TicketTask) GetPlanning() string {
+	if t.BeginPlan == "" {
+		return ""
+	}
+	if t.EndPlan == "" {
+		return FormatDate(t.BeginPlan)
+	}
+	return FormatDate(t.BeginPlan) + " → " + FormatDate(t.EndPlan)
+}
+
+// TotalActionTime soma o tempo gasto em todas as tarefas
+func TotalActionTime(tasks []TicketTask) time.Duration {
+	var total time.Duration
+	for _, t := range tasks {
+		total += t.Duration()
+	}
+	return total
+}
@@ -0,0 +1,212 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"glpi-tui/internal/richtext"
+)
+
+// TimelineType identifica o tipo de um item da timeline do chamado
+type TimelineType string
+
+const (
+	TimelineFollowup   TimelineType = "Followup"
+	TimelineTask       TimelineType = "Task"
+	TimelineSolution   TimelineType = "Solution"
+	TimelineDocument   TimelineType = "Document"
+	TimelineValidation TimelineType = "Validation"
+)
+
+// ParseTimelineType normaliza o campo "type" do envelope da timeline.
+// Aceita variações como "ITILFollowup", "TicketTask", "Document_Item" e minúsculas.
+func ParseTimelineType(raw string) (TimelineType, bool) {
+	t := strings.ToLower(raw)
+	switch {
+	case strings.Contains(t, "followup"):
+		return TimelineFollowup, true
+	case strings.Contains(t, "task"):
+		return TimelineTask, true
+	case strings.Contains(t, "solution"):
+		return TimelineSolution, true
+	case strings.Contains(t, "document"):
+		return TimelineDocument, true
+	case strings.Contains(t, "validation"):
+		return TimelineValidation, true
+	default:
+		return "", false
+	}
+}
+
+// Status de uma validação (aprovação) do chamado
+const (
+	ValidationStatusWaiting  = 2
+	ValidationStatusAccepted = 3
+	ValidationStatusRefused  = 4
+)
+
+// TicketValidation representa um pedido de aprovação na timeline
+type TicketValidation struct {
+	ID                int                `json:"id"`
+	SubmissionDate    string             `json:"submission_date"`
+	ValidationDate    string             `json:"validation_date"`
+	CommentSubmission string             `json:"comment_submission"`
+	CommentValidation string             `json:"comment_validation"`
+	Status            int                `json:"status"`
+	Requester         TicketFollowupUser `json:"requester"`
+	Approver          TicketFollowupUser `json:"approver"`
+}
+
+// StatusLabel devolve o nome do status da validação
+func (v TicketValidation) StatusLabel() string {
+	switch v.Status {
+	case ValidationStatusWaiting:
+		return "Aguardando"
+	case ValidationStatusAccepted:
+		return "Aprovada"
+	case ValidationStatusRefused:
+		return "Recusada"
+	default:
+		return ""
+	}
+}
+
+func (v TicketValidation) GetCleanSubmission() string {
+	return richtext.PlainText(v.CommentSubmission)
+}
+
+func (v TicketValidation) GetCleanValidation() string {
+	return richtext.PlainText(v.CommentValidation)
+}
+
+// TicketDocument representa um documento anexado ao chamado
+type TicketDocument struct {
+	ID       int                `json:"id"`
+	Name     string             `json:"name"`
+	Filename string             `json:"filename"`
+	Mime     string             `json:"mime"`
+	Date     string             `json:"date_creation"`
+	User     TicketFollowupUser `json:"user"`
+}
+
+// TimelineItem é a união tipada de um item da timeline: apenas o ponteiro do Type vem preenchido
+type TimelineItem struct {
+	Type       TimelineType
+	Followup   *TicketFollowup
+	Task       *TicketTask
+	Solution   *TicketSolution
+	Document   *TicketDocument
+	Validation *TicketValidation
+}
+
+// ID devolve o ID do item dentro do seu tipo
+func (i TimelineItem) ID() int {
+	switch i.Type {
+	case TimelineFollowup:
+		return i.Followup.ID
+	case TimelineTask:
+		return i.Task.ID
+	case TimelineSolution:
+		return i.Solution.ID
+	case TimelineDocument:
+		return i.Document.ID
+	case TimelineValidation:
+		return i.Validation.ID
+	}
+	return 0
+}
+
+// RawDate devolve a data do item como veio da API
+func (i TimelineItem) RawDate() string {
+	switch i.Type {
+	case TimelineFollowup:
+		return i.Followup.Date
+	case TimelineTask:
+		return i.Task.Date
+	case TimelineSolution:
+		return i.Solution.Date
+	case TimelineDocument:
+		return i.Document.Date
+	case TimelineValidation:
+		return i.Validation.SubmissionDate
+	}
+	return ""
+}
+
+// Time devolve a data do item (zero se não for possível interpretar)
+func (i TimelineItem) Time() time.Time {
+	return parseAPIDate(i.RawDate())
+}
+
+// Author devolve o nome de quem gerou o item
+func (i TimelineItem) Author() string {
+	switch i.Type {
+	case TimelineFollowup:
+		return i.Followup.User.Name
+	case TimelineTask:
+		if i.Task.Technician.Name != "" {
+			return i.Task.Technician.Name
+		}
+		return i.Task.User.Name
+	case TimelineSolution:
+		return i.Solution.User.Name
+	case TimelineDocument:
+		return i.Document.User.Name
+	case TimelineValidation:
+		return i.Validation.Requester.Name
+	}
+	return ""
+}
+
+// SortTimeline ordena os itens do mais recente para o mais antigo (empate: maior ID primeiro)
+func SortTimeline(items []TimelineItem) {
+	sort.SliceStable(items, func(a, b int) bool {
+		ta, tb := items[a].Time(), items[b].Time()
+		if !ta.Equal(tb) {
+			return ta.After(tb)
+		}
+		return items[a].ID() > items[b].ID()
+	})
+}
+
+// SetTimeline grava a timeline no chamado e separa os itens por tipo
+// (Followups, Tasks, Solutions, Documents) para quem só precisa de um deles.
+func (c *Chamado) SetTimeline(items []TimelineItem) {
+	SortTimeline(items)
+	c.Timeline = items
+	c.Followups = []TicketFollowup{}
+	c.Tasks = []TicketTask{}
+	c.Solutions = []TicketSolution{}
+	c.Documents = []TicketDocument{}
+	for _, it := range items {
+		switch it.Type {
+		case TimelineFollowup:
+			c.Followups = append(c.Followups, *it.Followup)
+		case TimelineTask:
+			c.Tasks = append(c.Tasks, *it.Task)
+		case TimelineSolution:
+			c.Solutions = append(c.Solutions, *it.Solution)
+		case TimelineDocument:
+			c.Documents = append(c.Documents, *it.Document)
+		}
+	}
+}
+
+// parseAPIDate interpreta datas da API (RFC3339 ou SQL)
+func parseAPIDate(v string) time.Time {
+	for _, layout := range []string{time.RFC3339, "2006-01-02 15:04:05"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// FormatDate formata datas da API (RFC3339 ou SQL) no padrão brasileiro
+func FormatDate(v string) string {
+	if t := parseAPIDate(v); !t.IsZero() {
+		return t.Format("02/01/06 15:04")
+	}
+	return v
+}
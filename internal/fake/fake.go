@@ -0,0 +1,761 @@
+// Package fake é um GLPI em memória que implementa api.Backend: chamados, atores e timeline
+// semeados, atraso e falhas configuráveis. Serve para rodar a TUI sem servidor (glpi-tui --demo)
+// e para exercitar o Update do model com respostas previsíveis.
+package fake
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"glpi-tui/internal/api"
+	"glpi-tui/internal/domain"
+	"glpi-tui/internal/markdown"
+)
+
+// dateLayout é o formato de data que o GLPI devolve
+const dateLayout = "2006-01-02 15:04:05"
+
+var _ api.Backend = (*Backend)(nil)
+
+// ticket é um chamado guardado com tudo que a API devolveria em chamadas separadas
+type ticket struct {
+	domain.Chamado
+	atores   []domain.TicketActor
+	timeline []domain.TimelineItem
+	arquivos map[int][]byte // Conteúdo dos documentos, por ID
+}
+
+// Backend é o GLPI em memória. Os campos exportados podem ser mudados a qualquer momento,
+// inclusive com a TUI rodando.
+type Backend struct {
+	// Latency é o atraso de cada chamada, como se fosse a rede (respeita o cancelamento do ctx)
+	Latency time.Duration
+	// FailRate é a fração das chamadas (0 a 1) que falham com HTTP 503, um erro temporário
+	FailRate float64
+
+	mu       sync.Mutex
+	falhas   map[string]error
+	aleat    *rand.Rand
+	usuario  domain.TicketFollowupUser // Quem está "logado"
+	grupos   []int
+	logado   bool
+	meuID    int   // Preenchido por GetMyID, como no client de verdade
+	meusGrps []int // Preenchido por GetMyGroups
+
+	tickets        map[int]*ticket
+	proximoID      int // Próximo ID de chamado
+	proximoItem    int // Próximo ID de item da timeline e de documento
+	tiposSolucao   []domain.SolutionType
+	modelosSolucao []domain.SolutionTemplate
+}
+
+// New cria um GLPI vazio com o usuário informado logado e membro dos grupos
+func New(usuario domain.TicketFollowupUser, grupos []int) *Backend {
+	return &Backend{
+		falhas:      map[string]error{},
+		aleat:       rand.New(rand.NewSource(time.Now().UnixNano())),
+		usuario:     usuario,
+		grupos:      grupos,
+		tickets:     map[int]*ticket{},
+		proximoID:   1,
+		proximoItem: 1,
+	}
+}
+
+// FailOn faz a operação (nome do método sem o sufixo Context, ex: "GetTicketsPage") falhar
+// sempre com err; err nil volta ao normal
+func (b *Backend) FailOn(op string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		delete(b.falhas, op)
+		return
+	}
+	b.falhas[op] = err
+}
+
+// AddTicket guarda um chamado com seus atores e timeline e devolve o ID usado
+// (t.ID zero = próximo ID livre). Datas vazias viram a hora atual.
+func (b *Backend) AddTicket(t domain.Chamado, atores []domain.TicketActor, timeline []domain.TimelineItem) int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	agora := time.Now().Format(dateLayout)
+	if t.ID == 0 {
+		t.ID = b.proximoID
+	}
+	b.proximoID = max(b.proximoID, t.ID+1)
+	if t.Date == "" {
+		t.Date = agora
+	}
+	if t.DateMod == "" {
+		t.DateMod = t.Date
+	}
+	t.Actors, t.Followups, t.Solutions, t.Tasks, t.Documents, t.Timeline = nil, nil, nil, nil, nil, nil
+
+	for _, it := range timeline {
+		b.proximoItem = max(b.proximoItem, it.ID()+1)
+	}
+	b.tickets[t.ID] = &ticket{
+		Chamado:  t,
+		atores:   append([]domain.TicketActor(nil), atores...),
+		timeline: append([]domain.TimelineItem(nil), timeline...),
+		arquivos: map[int][]byte{},
+	}
+	return t.ID
+}
+
+// SetSolutionOptions define os tipos e modelos de solução oferecidos no diálogo de solução
+func (b *Backend) SetSolutionOptions(tipos []domain.SolutionType, modelos []domain.SolutionTemplate) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.tiposSolucao, b.modelosSolucao = tipos, modelos
+}
+
+// chamada simula a ida ao servidor: espera a latência e decide se a operação falha
+func (b *Backend) chamada(ctx context.Context, op string) error {
+	if b.Latency > 0 {
+		select {
+		case <-time.After(b.Latency):
+		case <-ctx.Done():
+			return fmt.Errorf("erro de conexão ao %s: %w", op, ctx.Err())
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err, ok := b.falhas[op]; ok {
+		return err
+	}
+	if b.FailRate > 0 && b.aleat.Float64() < b.FailRate {
+		return &api.StatusError{What: op, StatusCode: http.StatusServiceUnavailable, Body: "falha simulada"}
+	}
+	if !b.logado && op != "Login" {
+		return fmt.Errorf("client não autenticado")
+	}
+	return nil
+}
+
+// chamadoLocked devolve o chamado guardado ou o 404 que a API devolveria. Deve ser chamado com mu travado.
+func (b *Backend) chamadoLocked(op string, id int) (*ticket, error) {
+	t, ok := b.tickets[id]
+	if !ok {
+		return nil, &api.StatusError{What: op, StatusCode: http.StatusNotFound, Body: fmt.Sprintf("chamado %d não existe", id)}
+	}
+	return t, nil
+}
+
+// novoItemLocked acrescenta um item à timeline e marca o chamado como alterado. Deve ser chamado com mu travado.
+func (b *Backend) novoItemLocked(t *ticket, it domain.TimelineItem) {
+	t.timeline = append(t.timeline, it)
+	t.DateMod = time.Now().Format(dateLayout)
+}
+
+func (b *Backend) proximoItemLocked() int {
+	id := b.proximoItem
+	b.proximoItem++
+	return id
+}
+
+func (b *Backend) Login() error {
+	return b.LoginContext(context.Background())
+}
+
+func (b *Backend) LoginContext(ctx context.Context) error {
+	if err := b.chamada(ctx, "Login"); err != nil {
+		return fmt.Errorf("login falhou: %w", err)
+	}
+	b.mu.Lock()
+	b.logado = true
+	b.mu.Unlock()
+	return nil
+}
+
+// Interactive é sempre false: o fake não tem login pelo navegador
+func (b *Backend) Interactive() bool { return false }
+
+func (b *Backend) SetInteract(func(instrucoes string)) {}
+
+func (b *Backend) GetMyID() error {
+	return b.GetMyIDContext(context.Background())
+}
+
+func (b *Backend) GetMyIDContext(ctx context.Context) error {
+	if err := b.chamada(ctx, "GetMyID"); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.meuID = b.usuario.ID
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *Backend) GetMyGroups() error {
+	return b.GetMyGroupsContext(context.Background())
+}
+
+func (b *Backend) GetMyGroupsContext(ctx context.Context) error {
+	if err := b.chamada(ctx, "GetMyGroups"); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	b.meusGrps = append([]int{}, b.grupos...)
+	b.mu.Unlock()
+	return nil
+}
+
+func (b *Backend) MyUserID() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.meuID
+}
+
+func (b *Backend) MyGroupIDs() []int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.meusGrps
+}
+
+func (b *Backend) GetTicketsPage(q api.TicketQuery) (api.TicketPage, error) {
+	return b.GetTicketsPageContext(context.Background(), q)
+}
+
+// GetTicketsPageContext aplica escopo e filtro, ordena e pagina como o servidor faria
+func (b *Backend) GetTicketsPageContext(ctx context.Context, q api.TicketQuery) (api.TicketPage, error) {
+	if err := b.chamada(ctx, "GetTicketsPage"); err != nil {
+		return api.TicketPage{}, err
+	}
+	if q.Start < 0 {
+		q.Start = 0
+	}
+	if q.Limit <= 0 {
+		q.Limit = api.DefaultPageSize
+	}
+	if q.Sort == "" {
+		q.Sort = "date_mod:desc"
+	}
+	menor, err := comparador(q.Sort)
+	if err != nil {
+		return api.TicketPage{}, err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var todos []domain.Chamado
+	for _, t := range b.tickets {
+		if casa(t, q.Scope) && casa(t, q.Filter) {
+			todos = append(todos, t.Chamado)
+		}
+	}
+	sort.SliceStable(todos, func(i, j int) bool { return menor(todos[i], todos[j]) })
+
+	pagina := []domain.Chamado{}
+	if q.Start < len(todos) {
+		pagina = todos[q.Start:min(q.Start+q.Limit, len(todos))]
+	}
+	return api.TicketPage{Tickets: pagina, Start: q.Start, Total: len(todos)}, nil
+}
+
+// comparador traduz o "property:direction" de TicketQuery.Sort
+func comparador(ordem string) (func(a, b domain.Chamado) bool, error) {
+	prop, dir, _ := strings.Cut(ordem, ":")
+	var menor func(a, b domain.Chamado) bool
+	switch prop {
+	case "id":
+		menor = func(a, b domain.Chamado) bool { return a.ID < b.ID }
+	case "name":
+		menor = func(a, b domain.Chamado) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case "date":
+		menor = func(a, b domain.Chamado) bool { return a.Date < b.Date }
+	case "date_mod":
+		menor = func(a, b domain.Chamado) bool { return a.DateMod < b.DateMod }
+	case "priority":
+		menor = func(a, b domain.Chamado) bool { return a.Priority < b.Priority }
+	case "status", "status.id":
+		menor = func(a, b domain.Chamado) bool { return a.Status.ID < b.Status.ID }
+	default:
+		return nil, &api.StatusError{What: "chamados", StatusCode: http.StatusBadRequest, Body: "ordenação inválida: " + prop}
+	}
+	if strings.EqualFold(dir, "desc") || dir == "" {
+		return func(a, b domain.Chamado) bool { return menor(b, a) }, nil
+	}
+	return menor, nil
+}
+
+// casa confere o chamado contra o filtro, campo a campo, com a mesma semântica do RSQL da v2
+func casa(t *ticket, f api.TicketFilter) bool {
+	if len(f.Statuses) > 0 && !contem(f.Statuses, t.Status.ID) {
+		return false
+	}
+	if f.MinPriority > 0 && t.Priority < f.MinPriority {
+		return false
+	}
+	if f.EntityID != nil && t.Entity.ID != *f.EntityID {
+		return false
+	}
+	if f.AssignedTo > 0 && !t.temAtor("User", "assigned", []int{f.AssignedTo}) {
+		return false
+	}
+	if f.RequesterID > 0 && !t.temAtor("User", "requester", []int{f.RequesterID}) {
+		return false
+	}
+	if len(f.AssignedGroups) > 0 && !t.temAtor("Group", "assigned", f.AssignedGroups) {
+		return false
+	}
+	if f.Unassigned && t.atribuido() {
+		return false
+	}
+	if !dentro(t.Date, f.OpenedAfter, f.OpenedBefore) || !dentro(t.DateMod, f.ModifiedAfter, f.ModifiedBefore) {
+		return false
+	}
+	if text := strings.ToLower(strings.TrimSpace(f.Text)); text != "" &&
+		!strings.Contains(strings.ToLower(t.Name), text) && !strings.Contains(strings.ToLower(t.Content), text) {
+		return false
+	}
+	return true
+}
+
+// atribuido indica se algum técnico ou grupo está atribuído ao chamado
+func (t *ticket) atribuido() bool {
+	for _, a := range t.atores {
+		if a.Role == "assigned" && (a.Type == "User" || a.Type == "Group") {
+			return true
+		}
+	}
+	return false
+}
+
+func (t *ticket) temAtor(tipo, papel string, ids []int) bool {
+	for _, a := range t.atores {
+		if a.Type == tipo && a.Role == papel && contem(ids, a.ID) {
+			return true
+		}
+	}
+	return false
+}
+
+func dentro(data string, depois, antes time.Time) bool {
+	d, err := time.ParseInLocation(dateLayout, data, time.Local)
+	if err != nil {
+		return depois.IsZero() && antes.IsZero()
+	}
+	return (depois.IsZero() || !d.Before(depois)) && (antes.IsZero() || !d.After(antes))
+}
+
+func contem(ids []int, id int) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Backend) GetTicketContext(ctx context.Context, ticketID int) (domain.Chamado, error) {
+	if err := b.chamada(ctx, "GetTicket"); err != nil {
+		return domain.Chamado{}, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, err := b.chamadoLocked("chamado", ticketID)
+	if err != nil {
+		return domain.Chamado{}, err
+	}
+	return t.Chamado, nil
+}
+
+func (b *Backend) CreateTicket(in api.TicketInput) (int, error) {
+	return b.CreateTicketContext(context.Background(), in)
+}
+
+func (b *Backend) CreateTicketContext(ctx context.Context, in api.TicketInput) (int, error) {
+	if err := in.Validate(); err != nil {
+		return 0, fmt.Errorf("chamado inválido: %w", err)
+	}
+	if err := b.chamada(ctx, "CreateTicket"); err != nil {
+		return 0, err
+	}
+
+	b.mu.Lock()
+	requerentes := in.RequesterIDs
+	if len(requerentes) == 0 {
+		requerentes = []int{b.usuario.ID}
+	}
+	var atores []domain.TicketActor
+	for _, id := range requerentes {
+		atores = append(atores, b.atorLocked(id, "requester"))
+	}
+	for _, id := range in.ObserverIDs {
+		atores = append(atores, b.atorLocked(id, "observer"))
+	}
+	b.mu.Unlock()
+
+	return b.AddTicket(domain.Chamado{
+		Name:     strings.TrimSpace(in.Name),
+		Content:  markdown.ToHTML(in.Content),
+		Status:   domain.TicketStatus{ID: domain.StatusNew},
+		Priority: in.Urgency,
+		Entity:   domain.TicketEntity{ID: in.EntityID, Name: fmt.Sprintf("Entidade %d", in.EntityID)},
+	}, atores, nil), nil
+}
+
+// atorLocked monta o ator de um usuário; só o usuário logado tem nome conhecido
+func (b *Backend) atorLocked(id int, papel string) domain.TicketActor {
+	nome := fmt.Sprintf("Usuário %d", id)
+	if id == b.usuario.ID {
+		nome = b.usuario.Name
+	}
+	return domain.TicketActor{ID: id, Name: nome, Type: "User", Role: papel}
+}
+
+func (b *Backend) AssignTicketViaUpdate(ticketID, entityID int) error {
+	return b.AssignTicketViaUpdateContext(context.Background(), ticketID, entityID)
+}
+
+func (b *Backend) AssignTicketViaUpdateContext(ctx context.Context, ticketID, entityID int) error {
+	if b.MyUserID() == 0 {
+		return fmt.Errorf("ID do usuário desconhecido. GetMyID foi chamado?")
+	}
+	if err := b.chamada(ctx, "AssignTicketViaUpdate"); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, err := b.chamadoLocked("patch chamado", ticketID)
+	if err != nil {
+		return err
+	}
+	to, err := domain.AssignStatus(t.Status.ID)
+	if err != nil {
+		return err
+	}
+	if !t.temAtor("User", "assigned", []int{b.usuario.ID}) {
+		t.atores = append(t.atores, b.atorLocked(b.usuario.ID, "assigned"))
+	}
+	t.Status = domain.TicketStatus{ID: to}
+	t.DateMod = time.Now().Format(dateLayout)
+	return nil
+}
+
+func (b *Backend) ChangeTicketStatus(ticketID, entityID, from, to int) error {
+	return b.ChangeTicketStatusContext(context.Background(), ticketID, entityID, from, to)
+}
+
+func (b *Backend) ChangeTicketStatusContext(ctx context.Context, ticketID, entityID, from, to int) error {
+	if err := domain.ValidateTransition(from, to); err != nil {
+		return err
+	}
+	if err := b.chamada(ctx, "ChangeTicketStatus"); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, err := b.chamadoLocked("patch chamado", ticketID)
+	if err != nil {
+		return err
+	}
+	t.Status = domain.TicketStatus{ID: to}
+	t.DateMod = time.Now().Format(dateLayout)
+	return nil
+}
+
+func (b *Backend) GetTicketActorsContext(ctx context.Context, ticketID int) ([]domain.TicketActor, error) {
+	if err := b.chamada(ctx, "GetTicketActors"); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, err := b.chamadoLocked("atores", ticketID)
+	if err != nil {
+		return nil, err
+	}
+	return append([]domain.TicketActor{}, t.atores...), nil
+}
+
+func (b *Backend) GetTicketFollowupsContext(ctx context.Context, ticketID int) ([]domain.TicketFollowup, error) {
+	if err := b.chamada(ctx, "GetTicketFollowups"); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, err := b.chamadoLocked("followups", ticketID)
+	if err != nil {
+		return nil, err
+	}
+	var fs []domain.TicketFollowup
+	for _, it := range t.timeline {
+		if it.Type == domain.TimelineFollowup {
+			fs = append(fs, *it.Followup)
+		}
+	}
+	return fs, nil
+}
+
+// GetTicketTimelineContext devolve cópias dos itens: a TUI pode mexer neles sem alterar o fake
+func (b *Backend) GetTicketTimelineContext(ctx context.Context, ticketID int) ([]domain.TimelineItem, error) {
+	if err := b.chamada(ctx, "GetTicketTimeline"); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, err := b.chamadoLocked("timeline", ticketID)
+	if err != nil {
+		return nil, err
+	}
+	items := make([]domain.TimelineItem, len(t.timeline))
+	for i, it := range t.timeline {
+		items[i] = copiar(it)
+	}
+	domain.SortTimeline(items)
+	return items, nil
+}
+
+func copiar(it domain.TimelineItem) domain.TimelineItem {
+	switch {
+	case it.Followup != nil:
+		v := *it.Followup
+		it.Followup = &v
+	case it.Task != nil:
+		v := *it.Task
+		it.Task = &v
+	case it.Solution != nil:
+		v := *it.Solution
+		it.Solution = &v
+	case it.Document != nil:
+		v := *it.Document
+		it.Document = &v
+	case it.Validation != nil:
+		v := *it.Validation
+		it.Validation = &v
+	}
+	return it
+}
+
+func (b *Backend) CreateTicketFollowup(ticketID int, content string) error {
+	return b.CreateTicketFollowupContext(context.Background(), ticketID, content)
+}
+
+func (b *Backend) CreateTicketFollowupContext(ctx context.Context, ticketID int, content string) error {
+	if err := b.chamada(ctx, "CreateTicketFollowup"); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, err := b.chamadoLocked("criar followup", ticketID)
+	if err != nil {
+		return err
+	}
+	b.novoItemLocked(t, domain.TimelineItem{Type: domain.TimelineFollowup, Followup: &domain.TicketFollowup{
+		ID:      b.proximoItemLocked(),
+		Date:    time.Now().Format(dateLayout),
+		Content: markdown.ToHTML(content),
+		User:    b.usuario,
+	}})
+	return nil
+}
+
+func (b *Backend) CreateTicketTask(ticketID, entityID int, in api.TaskInput) error {
+	if err := in.Validate(); err != nil {
+		return fmt.Errorf("tarefa inválida: %w", err)
+	}
+	if err := b.chamada(context.Background(), "CreateTicketTask"); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, err := b.chamadoLocked("criar tarefa", ticketID)
+	if err != nil {
+		return err
+	}
+	task := &domain.TicketTask{
+		ID:         b.proximoItemLocked(),
+		Date:       time.Now().Format(dateLayout),
+		Content:    markdown.ToHTML(in.Content),
+		ActionTime: int(in.ActionTime.Seconds()),
+		State:      in.State,
+		User:       b.usuario,
+		Technician: b.usuario,
+	}
+	if !in.BeginPlan.IsZero() {
+		task.BeginPlan = in.BeginPlan.Format(dateLayout)
+		task.EndPlan = in.EndPlan.Format(dateLayout)
+	}
+	b.novoItemLocked(t, domain.TimelineItem{Type: domain.TimelineTask, Task: task})
+	return nil
+}
+
+// CreateTicketSolution registra a solução aguardando aprovação e marca o chamado como Solucionado
+func (b *Backend) CreateTicketSolution(ticketID, entityID int, in api.SolutionInput) error {
+	if in.Content == "" {
+		return fmt.Errorf("a solução precisa de uma descrição")
+	}
+	if err := b.chamada(context.Background(), "CreateTicketSolution"); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, err := b.chamadoLocked("criar solução", ticketID)
+	if err != nil {
+		return err
+	}
+	tipo := domain.SolutionType{ID: in.SolutionTypeID}
+	for _, st := range b.tiposSolucao {
+		if st.ID == in.SolutionTypeID {
+			tipo = st
+		}
+	}
+	b.novoItemLocked(t, domain.TimelineItem{Type: domain.TimelineSolution, Solution: &domain.TicketSolution{
+		ID:      b.proximoItemLocked(),
+		Date:    time.Now().Format(dateLayout),
+		Content: markdown.ToHTML(in.Content),
+		Type:    tipo,
+		Status:  domain.SolutionStatusWaiting,
+		User:    b.usuario,
+	}})
+	t.Status = domain.TicketStatus{ID: domain.StatusSolved}
+	return nil
+}
+
+func (b *Backend) ApproveTicketSolution(ticketID, entityID, solutionID int) error {
+	return b.revisarSolucao("ApproveTicketSolution", ticketID, solutionID, domain.SolutionStatusAccepted, "")
+}
+
+func (b *Backend) RefuseTicketSolution(ticketID, entityID, solutionID int, reason string) error {
+	return b.revisarSolucao("RefuseTicketSolution", ticketID, solutionID, domain.SolutionStatusRefused, reason)
+}
+
+// revisarSolucao aprova (fecha o chamado) ou recusa (reabre, com o motivo como acompanhamento)
+func (b *Backend) revisarSolucao(op string, ticketID, solutionID, status int, motivo string) error {
+	if err := b.chamada(context.Background(), op); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, err := b.chamadoLocked("atualizar solução", ticketID)
+	if err != nil {
+		return err
+	}
+	var sol *domain.TicketSolution
+	for _, it := range t.timeline {
+		if it.Solution != nil && it.Solution.ID == solutionID {
+			sol = it.Solution
+		}
+	}
+	if sol == nil || !sol.IsPendingApproval() {
+		return &api.StatusError{What: "atualizar solução", StatusCode: http.StatusBadRequest, Body: "nenhuma solução pendente com esse ID"}
+	}
+
+	sol.Status = status
+	t.Status = domain.TicketStatus{ID: domain.StatusClosed}
+	if status == domain.SolutionStatusRefused {
+		t.Status = domain.TicketStatus{ID: domain.StatusAssign}
+	}
+	t.DateMod = time.Now().Format(dateLayout)
+	if motivo != "" {
+		b.novoItemLocked(t, domain.TimelineItem{Type: domain.TimelineFollowup, Followup: &domain.TicketFollowup{
+			ID:      b.proximoItemLocked(),
+			Date:    t.DateMod,
+			Content: markdown.ToHTML(motivo),
+			User:    b.usuario,
+		}})
+	}
+	return nil
+}
+
+func (b *Backend) GetSolutionTypes() ([]domain.SolutionType, error) {
+	if err := b.chamada(context.Background(), "GetSolutionTypes"); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]domain.SolutionType{}, b.tiposSolucao...), nil
+}
+
+func (b *Backend) GetSolutionTemplates() ([]domain.SolutionTemplate, error) {
+	if err := b.chamada(context.Background(), "GetSolutionTemplates"); err != nil {
+		return nil, err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]domain.SolutionTemplate{}, b.modelosSolucao...), nil
+}
+
+// AddDocument anexa um documento com o conteúdo informado ao chamado e devolve o ID dele
+func (b *Backend) AddDocument(ticketID int, nome string, conteudo []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	t, err := b.chamadoLocked("upload", ticketID)
+	if err != nil {
+		return 0, err
+	}
+	id := b.proximoItemLocked()
+	t.arquivos[id] = conteudo
+	b.novoItemLocked(t, domain.TimelineItem{Type: domain.TimelineDocument, Document: &domain.TicketDocument{
+		ID:       id,
+		Name:     nome,
+		Filename: nome,
+		Date:     time.Now().Format(dateLayout),
+		User:     b.usuario,
+	}})
+	return id, nil
+}
+
+// DownloadDocumentToDir grava o documento em dir; nomes repetidos ganham o ID na frente
+func (b *Backend) DownloadDocumentToDir(ctx context.Context, doc domain.TicketDocument, dir string, progress api.ProgressFunc) (string, error) {
+	if err := b.chamada(ctx, "DownloadDocument"); err != nil {
+		return "", err
+	}
+	b.mu.Lock()
+	var conteudo []byte
+	achou := false
+	for _, t := range b.tickets {
+		if c, ok := t.arquivos[doc.ID]; ok {
+			conteudo, achou = c, true
+		}
+	}
+	b.mu.Unlock()
+	if !achou {
+		return "", &api.StatusError{What: "download", StatusCode: http.StatusNotFound, Body: fmt.Sprintf("documento %d não existe", doc.ID)}
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", fmt.Errorf("erro ao criar pasta de download: %w", err)
+	}
+	path := filepath.Join(dir, filepath.Base(doc.Filename))
+	if _, err := os.Stat(path); err == nil {
+		path = filepath.Join(dir, fmt.Sprintf("%d-%s", doc.ID, filepath.Base(doc.Filename)))
+	}
+	if err := os.WriteFile(path, conteudo, 0o644); err != nil {
+		return "", fmt.Errorf("erro ao gravar documento: %w", err)
+	}
+	if progress != nil {
+		progress(int64(len(conteudo)), int64(len(conteudo)))
+	}
+	return path, nil
+}
+
+// UploadDocumentContext lê o arquivo local e o anexa ao chamado
+func (b *Backend) UploadDocumentContext(ctx context.Context, in api.UploadInput, progress api.ProgressFunc) error {
+	conteudo, err := os.ReadFile(in.Path)
+	if err != nil {
+		return fmt.Errorf("arquivo inválido: %w", err)
+	}
+	if err := b.chamada(ctx, "UploadDocument"); err != nil {
+		return err
+	}
+	if _, err := b.AddDocument(in.TicketID, filepath.Base(in.Path), conteudo); err != nil {
+		return err
+	}
+	if progress != nil {
+		progress(int64(len(conteudo)), int64(len(conteudo)))
+	}
+	return nil
+}
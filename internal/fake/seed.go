@@ -0,0 +1,150 @@
+package fake
+
+import (
+	"time"
+
+	"glpi-tui/internal/domain"
+)
+
+// Pessoas e grupos do GLPI de demonstração
+var (
+	Eu      = domain.TicketFollowupUser{ID: 2, Name: "Ana Souza"}
+	Joao    = domain.TicketFollowupUser{ID: 7, Name: "João Silva"}
+	Maria   = domain.TicketFollowupUser{ID: 9, Name: "Maria Oliveira"}
+	Suporte = domain.TicketActor{ID: 3, Name: "Suporte N1", Type: "Group"}
+	Redes   = domain.TicketActor{ID: 4, Name: "Redes", Type: "Group"}
+)
+
+var (
+	raiz   = domain.TicketEntity{ID: 0, Name: "Entidade raiz"}
+	filial = domain.TicketEntity{ID: 2, Name: "Entidade raiz > Filial Centro"}
+)
+
+// Demo devolve um GLPI com uma dezena de chamados em vários status, prioridades e entidades,
+// com atores, acompanhamentos, tarefas, uma solução aguardando aprovação e um anexo.
+// As datas são relativas à hora atual, para que os filtros "hoje" e "últimos 7 dias" tenham o que mostrar.
+func Demo() *Backend {
+	b := New(Eu, []int{Suporte.ID})
+	agora := time.Now()
+	ha := func(d time.Duration) string { return agora.Add(-d).Format(dateLayout) }
+	dia := 24 * time.Hour
+
+	b.SetSolutionOptions(
+		[]domain.SolutionType{{ID: 1, Name: "Resolvido remotamente"}, {ID: 2, Name: "Substituição de equipamento"}},
+		[]domain.SolutionTemplate{
+			{ID: 1, Name: "Senha redefinida", Content: "Senha redefinida. Troque-a no próximo acesso.", SolutionType: domain.SolutionType{ID: 1, Name: "Resolvido remotamente"}},
+			{ID: 2, Name: "Equipamento trocado", Content: "Equipamento substituído por um do estoque.", SolutionType: domain.SolutionType{ID: 2, Name: "Substituição de equipamento"}},
+		},
+	)
+
+	b.AddTicket(domain.Chamado{
+		ID: 101, Name: "Impressora do 2º andar não imprime", Content: "<p>A impressora HP do 2º andar mostra <b>papel atolado</b>, mas não há papel preso.</p>",
+		Date: ha(3 * dia), DateMod: ha(2 * time.Hour), Status: domain.TicketStatus{ID: domain.StatusAssign}, Priority: 4, Entity: filial,
+	}, []domain.TicketActor{
+		ator(Joao, "requester"), ator(Eu, "assigned"), grupo(Suporte, "assigned"),
+	}, []domain.TimelineItem{
+		acompanhamento(1001, ha(3*dia-time.Hour), Eu, "<p>Vou passar aí depois do almoço.</p>"),
+		tarefa(1002, ha(2*dia), Eu, "<p>Limpeza do rolo de tração.</p>", domain.TaskStateDone, time.Hour),
+		acompanhamento(1003, ha(2*time.Hour), Joao, "<p>Voltou a travar hoje de manhã.</p>"),
+	})
+
+	b.AddTicket(domain.Chamado{
+		ID: 102, Name: "Sem acesso à VPN", Content: "<p>Desde ontem o cliente da VPN dá <i>timeout</i> na autenticação.</p>",
+		Date: ha(26 * time.Hour), DateMod: ha(26 * time.Hour), Status: domain.TicketStatus{ID: domain.StatusNew}, Priority: 5, Entity: raiz,
+	}, []domain.TicketActor{
+		ator(Maria, "requester"), grupo(Redes, "assigned"),
+	}, nil)
+
+	b.AddTicket(domain.Chamado{
+		ID: 103, Name: "Trocar senha do e-mail", Content: "<p>Esqueci a senha do webmail.</p>",
+		Date: ha(5 * dia), DateMod: ha(4 * time.Hour), Status: domain.TicketStatus{ID: domain.StatusSolved}, Priority: 3, Entity: raiz,
+	}, []domain.TicketActor{
+		ator(Eu, "requester"), ator(Joao, "assigned"), grupo(Suporte, "assigned"),
+	}, []domain.TimelineItem{
+		acompanhamento(1004, ha(5*dia-2*time.Hour), Joao, "<p>Pode confirmar seu ramal?</p>"),
+		acompanhamento(1005, ha(5*dia-time.Hour), Eu, "<p>Ramal 2231.</p>"),
+		{Type: domain.TimelineSolution, Solution: &domain.TicketSolution{
+			ID: 1006, Date: ha(4 * time.Hour), Content: "<p>Senha redefinida. Troque-a no próximo acesso.</p>",
+			Type: domain.SolutionType{ID: 1, Name: "Resolvido remotamente"}, Status: domain.SolutionStatusWaiting, User: Joao,
+		}},
+	})
+
+	b.AddTicket(domain.Chamado{
+		ID: 104, Name: "Notebook novo para estagiário", Content: "<p>Estagiário começa na segunda-feira.</p>",
+		Date: ha(8 * dia), DateMod: ha(1 * dia), Status: domain.TicketStatus{ID: domain.StatusPlanned}, Priority: 2, Entity: filial,
+	}, []domain.TicketActor{
+		ator(Maria, "requester"), ator(Eu, "assigned"), ator(Joao, "observer"),
+	}, []domain.TimelineItem{
+		{Type: domain.TimelineValidation, Validation: &domain.TicketValidation{
+			ID: 1007, SubmissionDate: ha(7 * dia), ValidationDate: ha(6 * dia),
+			CommentSubmission: "Compra dentro do orçamento?", CommentValidation: "Aprovado.",
+			Status: domain.ValidationStatusAccepted, Requester: Eu, Approver: Maria,
+		}},
+		func() domain.TimelineItem {
+			it := tarefa(1008, ha(1*dia), Eu, "<p>Instalar imagem padrão.</p>", domain.TaskStateTodo, 2*time.Hour)
+			it.Task.BeginPlan = agora.Add(dia).Format(dateLayout)
+			it.Task.EndPlan = agora.Add(dia + 2*time.Hour).Format(dateLayout)
+			return it
+		}(),
+	})
+
+	b.AddTicket(domain.Chamado{
+		ID: 105, Name: "Lentidão no sistema de ponto", Content: "<p>O sistema de ponto demora mais de um minuto para abrir.</p>",
+		Date: ha(2 * dia), DateMod: ha(30 * time.Minute), Status: domain.TicketStatus{ID: domain.StatusPending}, Priority: 3, Entity: raiz,
+	}, []domain.TicketActor{
+		ator(Joao, "requester"), grupo(Suporte, "assigned"),
+	}, []domain.TimelineItem{
+		acompanhamento(1009, ha(30*time.Minute), Maria, "<p>Aguardando retorno do fornecedor.</p>"),
+	})
+
+	b.AddTicket(domain.Chamado{
+		ID: 106, Name: "Monitor piscando", Content: "<p>O monitor da recepção pisca ao ligar.</p>",
+		Date: ha(20 * dia), DateMod: ha(15 * dia), Status: domain.TicketStatus{ID: domain.StatusClosed}, Priority: 2, Entity: filial,
+	}, []domain.TicketActor{
+		ator(Maria, "requester"), ator(Eu, "assigned"),
+	}, []domain.TimelineItem{
+		{Type: domain.TimelineSolution, Solution: &domain.TicketSolution{
+			ID: 1010, Date: ha(16 * dia), Content: "<p>Cabo HDMI trocado.</p>",
+			Type: domain.SolutionType{ID: 2, Name: "Substituição de equipamento"}, Status: domain.SolutionStatusAccepted, User: Eu,
+		}},
+	})
+
+	b.AddTicket(domain.Chamado{
+		ID: 107, Name: "Instalar software de desenho", Content: "<p>Preciso do Inkscape na máquina CAD-03.</p>",
+		Date: ha(3 * time.Hour), DateMod: ha(3 * time.Hour), Status: domain.TicketStatus{ID: domain.StatusNew}, Priority: 1, Entity: filial,
+	}, []domain.TicketActor{
+		ator(Joao, "requester"), grupo(Suporte, "assigned"),
+	}, nil)
+
+	b.AddTicket(domain.Chamado{
+		ID: 108, Name: "Switch do rack B reiniciando", Content: "<p>O switch reinicia a cada ~40 minutos, derrubando o andar.</p>",
+		Date: ha(6 * time.Hour), DateMod: ha(10 * time.Minute), Status: domain.TicketStatus{ID: domain.StatusAssign}, Priority: 6, Entity: raiz,
+	}, []domain.TicketActor{
+		ator(Maria, "requester"), ator(Joao, "assigned"), grupo(Redes, "assigned"), ator(Eu, "observer"),
+	}, []domain.TimelineItem{
+		acompanhamento(1011, ha(5*time.Hour), Joao, "<p>Log do switch em anexo.</p>"),
+		tarefa(1012, ha(10*time.Minute), Joao, "<p>Firmware atualizado.</p>", domain.TaskStateDone, 45*time.Minute),
+	})
+	b.AddDocument(108, "switch-rackB.log", []byte("Oct 14 10:02:11 sw-b %SYS-5-RESTART: System restarted\n"))
+
+	return b
+}
+
+func ator(u domain.TicketFollowupUser, papel string) domain.TicketActor {
+	return domain.TicketActor{ID: u.ID, Name: u.Name, Type: "User", Role: papel}
+}
+
+func grupo(g domain.TicketActor, papel string) domain.TicketActor {
+	g.Role = papel
+	return g
+}
+
+func acompanhamento(id int, data string, u domain.TicketFollowupUser, html string) domain.TimelineItem {
+	return domain.TimelineItem{Type: domain.TimelineFollowup, Followup: &domain.TicketFollowup{ID: id, Date: data, Content: html, User: u}}
+}
+
+func tarefa(id int, data string, u domain.TicketFollowupUser, html string, estado int, duracao time.Duration) domain.TimelineItem {
+	return domain.TimelineItem{Type: domain.TimelineTask, Task: &domain.TicketTask{
+		ID: id, Date: data, Content: html, ActionTime: int(duracao.Seconds()), State: estado, User: u, Technician: u,
+	}}
+}
@@ -0,0 +1,116 @@
+package glpimock
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"glpi-tui/internal/domain"
+)
+
+// Fixture é o estado inicial do servidor, lido de um arquivo JSON (veja fixture.json)
+type Fixture struct {
+	TokenTTL int      `json:"token_ttl"` // Validade do access_token em segundos (0 = 3600)
+	Clients  []Client `json:"clients"`   // Clientes OAuth aceitos (vazio = qualquer client_id)
+	Users    []User   `json:"users"`
+	Groups   []Group  `json:"groups"`
+	Tickets  []Ticket `json:"tickets"`
+	Faults   []Fault  `json:"faults"` // Falhas injetadas desde o início
+}
+
+// Client é um cliente OAuth cadastrado no GLPI
+type Client struct {
+	ID     string `json:"client_id"`
+	Secret string `json:"client_secret"` // Vazio = cliente público, sem segredo
+}
+
+// User é uma conta que pode fazer login pelo grant password
+type User struct {
+	ID       int    `json:"id"`
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Name     string `json:"name"`   // Nome exibido em atores e acompanhamentos
+	Groups   []int  `json:"groups"` // Grupos do usuário (GET /Administration/User/Me/Group)
+}
+
+// Group é um grupo de técnicos
+type Group struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+// Ticket é um chamado no formato do schema Ticket da v2, com a equipe e os acompanhamentos junto
+type Ticket struct {
+	domain.Chamado
+	Team      []domain.TicketActor    `json:"team"`
+	Followups []domain.TicketFollowup `json:"followups"`
+}
+
+// Fault é uma falha injetada: requisições com o método e o caminho informados recebem Status e Body
+// em vez da resposta normal. Path aceita os curingas de path.Match (ex: /Assistance/Ticket/*/TeamMember).
+type Fault struct {
+	Method string `json:"method"` // Vazio = qualquer método
+	Path   string `json:"path"`
+	Status int    `json:"status"`
+	Body   string `json:"body"`  // Vazio = corpo de erro padrão do GLPI
+	Times  int    `json:"times"` // Quantas vezes falhar (0 = sempre)
+}
+
+//go:embed fixture.json
+var fixturePadrao []byte
+
+// DefaultFixture devolve a fixture embutida: dois técnicos, dois grupos e uma dezena de chamados
+func DefaultFixture() *Fixture {
+	fx, err := ParseFixture(fixturePadrao)
+	if err != nil {
+		panic(fmt.Sprintf("fixture embutida inválida: %v", err))
+	}
+	return fx
+}
+
+// LoadFixture lê a fixture de um arquivo JSON
+func LoadFixture(path string) (*Fixture, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("erro ao ler fixture: %w", err)
+	}
+	fx, err := ParseFixture(data)
+	if err != nil {
+		return nil, fmt.Errorf("fixture %s: %w", path, err)
+	}
+	return fx, nil
+}
+
+// ParseFixture decodifica e confere a fixture; campos desconhecidos são recusados para pegar erros de digitação
+func ParseFixture(data []byte) (*Fixture, error) {
+	var fx Fixture
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&fx); err != nil {
+		return nil, fmt.Errorf("JSON inválido: %w", err)
+	}
+
+	ids := map[int]bool{}
+	for _, t := range fx.Tickets {
+		if t.ID <= 0 {
+			return nil, fmt.Errorf("chamado sem id: %q", t.Name)
+		}
+		if ids[t.ID] {
+			return nil, fmt.Errorf("chamado %d repetido", t.ID)
+		}
+		ids[t.ID] = true
+	}
+	for _, u := range fx.Users {
+		if u.ID <= 0 || u.Username == "" {
+			return nil, fmt.Errorf("usuário precisa de id e username: %+v", u)
+		}
+	}
+	for _, f := range fx.Faults {
+		if f.Path == "" || f.Status < 400 {
+			return nil, fmt.Errorf("falha precisa de path e status >= 400: %+v", f)
+		}
+	}
+	return &fx, nil
+}
@@ -0,0 +1,359 @@
+package glpimock
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"glpi-tui/internal/domain"
+)
+
+// rsqlNo é um nó da expressão do parâmetro "filter". membro é o ator da equipe em avaliação
+// pelos campos team.* (nil = nenhum escolhido ainda).
+type rsqlNo interface {
+	casa(t *Ticket, membro *domain.TicketActor) bool
+	usaEquipe() bool
+}
+
+type rsqlE []rsqlNo  // ";" (AND)
+type rsqlOu []rsqlNo // "," (OR)
+
+type rsqlComparacao struct {
+	campo   string
+	op      string
+	valores []string
+}
+
+// Um grupo como (team.type==User;team.id==2;team.role==assigned) vale se um mesmo ator da equipe
+// satisfizer todas as comparações. Fora de grupos, cada comparação team.* procura o seu ator.
+//
+// Essa é uma suposição, não algo conferido num GLPI de verdade: é a semântica de que o
+// api.TicketFilter depende para combinar "atribuído a" com "solicitante" (dois grupos, dois atores).
+// No GLPI a propriedade team vira um JOIN na tradução do RSQL para SQL
+// (https://github.com/glpi-project/glpi/tree/main/src/Glpi/Api/HL), e se cada linha do JOIN for
+// avaliada inteira, dois grupos team.* ligados por ";" nunca casam com atores diferentes.
+// Se o servidor se comportar assim, ajuste aqui e no teamMemberRSQL juntos.
+func (e rsqlE) casa(t *Ticket, membro *domain.TicketActor) bool {
+	if membro == nil && e.usaEquipe() && soComparacoes(e) {
+		return algumMembro(t, func(m *domain.TicketActor) bool { return e.casa(t, m) })
+	}
+	for _, n := range e {
+		if !n.casa(t, membro) {
+			return false
+		}
+	}
+	return true
+}
+
+func (o rsqlOu) casa(t *Ticket, membro *domain.TicketActor) bool {
+	for _, n := range o {
+		if n.casa(t, membro) {
+			return true
+		}
+	}
+	return false
+}
+
+func (e rsqlE) usaEquipe() bool  { return algumUsaEquipe(e) }
+func (o rsqlOu) usaEquipe() bool { return algumUsaEquipe(o) }
+
+func (c rsqlComparacao) usaEquipe() bool { return strings.HasPrefix(c.campo, "team.") }
+
+func (c rsqlComparacao) casa(t *Ticket, membro *domain.TicketActor) bool {
+	if c.usaEquipe() && membro == nil {
+		return algumMembro(t, func(m *domain.TicketActor) bool { return c.casa(t, m) })
+	}
+	v, _ := valorCampo(t, membro, c.campo)
+	return comparar(v, c.op, c.valores)
+}
+
+func algumUsaEquipe(nos []rsqlNo) bool {
+	for _, n := range nos {
+		if n.usaEquipe() {
+			return true
+		}
+	}
+	return false
+}
+
+func soComparacoes(nos []rsqlNo) bool {
+	for _, n := range nos {
+		if _, ok := n.(rsqlComparacao); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+func algumMembro(t *Ticket, f func(m *domain.TicketActor) bool) bool {
+	for i := range t.Team {
+		if f(&t.Team[i]) {
+			return true
+		}
+	}
+	return false
+}
+
+// valorCampo devolve o campo do chamado (ou do ator, para team.*) como texto
+func valorCampo(t *Ticket, membro *domain.TicketActor, campo string) (string, bool) {
+	switch campo {
+	case "id":
+		return strconv.Itoa(t.ID), true
+	case "name":
+		return t.Name, true
+	case "content":
+		return t.Content, true
+	case "date":
+		return t.Date, true
+	case "date_mod":
+		return t.DateMod, true
+	case "priority":
+		return strconv.Itoa(t.Priority), true
+	case "status", "status.id":
+		return strconv.Itoa(t.Status.ID), true
+	case "entity", "entity.id":
+		return strconv.Itoa(t.Entity.ID), true
+	case "entity.name":
+		return t.Entity.Name, true
+	case "team.id", "team.type", "team.role", "team.name":
+		if membro == nil {
+			return "", true
+		}
+		switch campo {
+		case "team.id":
+			return strconv.Itoa(membro.ID), true
+		case "team.type":
+			return membro.Type, true
+		case "team.role":
+			return membro.Role, true
+		default:
+			return membro.Name, true
+		}
+	}
+	return "", false
+}
+
+// comparar aplica o operador RSQL; números são comparados como números e datas no formato do GLPI como texto
+func comparar(v, op string, valores []string) bool {
+	switch op {
+	case "==":
+		return curinga(v, valores[0], false)
+	case "!=":
+		return !curinga(v, valores[0], false)
+	case "=like=":
+		return curinga(v, valores[0], false)
+	case "=ilike=":
+		return curinga(v, valores[0], true)
+	case "=in=", "=out=":
+		achou := false
+		for _, x := range valores {
+			achou = achou || v == x
+		}
+		return achou == (op == "=in=")
+	case "=gt=", "=ge=", "=lt=", "=le=":
+		c := ordem(v, valores[0])
+		switch op {
+		case "=gt=":
+			return c > 0
+		case "=ge=":
+			return c >= 0
+		case "=lt=":
+			return c < 0
+		default:
+			return c <= 0
+		}
+	}
+	return false
+}
+
+func ordem(a, b string) int {
+	x, errA := strconv.Atoi(a)
+	y, errB := strconv.Atoi(b)
+	if errA != nil || errB != nil {
+		return strings.Compare(a, b)
+	}
+	return x - y
+}
+
+// curinga compara v com o padrão, em que "*" casa qualquer sequência
+func curinga(v, padrao string, semCaixa bool) bool {
+	if semCaixa {
+		v, padrao = strings.ToLower(v), strings.ToLower(padrao)
+	}
+	partes := strings.Split(padrao, "*")
+	if len(partes) == 1 {
+		return v == padrao
+	}
+	if !strings.HasPrefix(v, partes[0]) {
+		return false
+	}
+	v = v[len(partes[0]):]
+	for _, p := range partes[1 : len(partes)-1] {
+		i := strings.Index(v, p)
+		if i < 0 {
+			return false
+		}
+		v = v[i+len(p):]
+	}
+	return strings.HasSuffix(v, partes[len(partes)-1])
+}
+
+// parseRSQL interpreta o parâmetro "filter" da v2 (ex: status.id=in=(1,2);(name=ilike="*vpn*",content=ilike="*vpn*"))
+func parseRSQL(s string) (rsqlNo, error) {
+	p := &rsqlParser{s: s}
+	n, err := p.ou()
+	if err != nil {
+		return nil, err
+	}
+	if p.i < len(p.s) {
+		return nil, fmt.Errorf("caractere inesperado %q na posição %d", p.s[p.i], p.i)
+	}
+	return n, nil
+}
+
+type rsqlParser struct {
+	s string
+	i int
+}
+
+var rsqlOperadores = map[string]bool{
+	"==": true, "!=": true, "=in=": true, "=out=": true, "=like=": true, "=ilike=": true,
+	"=gt=": true, "=ge=": true, "=lt=": true, "=le=": true,
+}
+
+func (p *rsqlParser) ou() (rsqlNo, error) {
+	var nos rsqlOu
+	for {
+		n, err := p.e()
+		if err != nil {
+			return nil, err
+		}
+		nos = append(nos, n)
+		if !p.consome(',') {
+			break
+		}
+	}
+	if len(nos) == 1 {
+		return nos[0], nil
+	}
+	return nos, nil
+}
+
+func (p *rsqlParser) e() (rsqlNo, error) {
+	var nos rsqlE
+	for {
+		n, err := p.primario()
+		if err != nil {
+			return nil, err
+		}
+		nos = append(nos, n)
+		if !p.consome(';') {
+			break
+		}
+	}
+	if len(nos) == 1 {
+		return nos[0], nil
+	}
+	return nos, nil
+}
+
+func (p *rsqlParser) primario() (rsqlNo, error) {
+	if p.consome('(') {
+		n, err := p.ou()
+		if err != nil {
+			return nil, err
+		}
+		if !p.consome(')') {
+			return nil, fmt.Errorf("falta ')' na posição %d", p.i)
+		}
+		// Um grupo entre parênteses continua sendo um E, mesmo com um único item
+		if c, ok := n.(rsqlComparacao); ok {
+			return rsqlE{c}, nil
+		}
+		return n, nil
+	}
+	return p.comparacao()
+}
+
+func (p *rsqlParser) comparacao() (rsqlNo, error) {
+	inicio := p.i
+	for p.i < len(p.s) && strings.IndexByte("=!<>;,()\"", p.s[p.i]) < 0 {
+		p.i++
+	}
+	campo := p.s[inicio:p.i]
+	if _, ok := valorCampo(&Ticket{}, nil, campo); !ok {
+		return nil, fmt.Errorf("campo desconhecido %q", campo)
+	}
+
+	op := ""
+	if strings.HasPrefix(p.s[p.i:], "==") || strings.HasPrefix(p.s[p.i:], "!=") {
+		op = p.s[p.i : p.i+2]
+	} else if fim := strings.IndexByte(p.s[min(p.i+1, len(p.s)):], '='); p.i < len(p.s) && p.s[p.i] == '=' && fim >= 0 {
+		op = p.s[p.i : p.i+fim+2]
+	}
+	if !rsqlOperadores[op] {
+		return nil, fmt.Errorf("operador inválido depois de %q", campo)
+	}
+	p.i += len(op)
+
+	var valores []string
+	if p.consome('(') {
+		for {
+			v, err := p.valor()
+			if err != nil {
+				return nil, err
+			}
+			valores = append(valores, v)
+			if !p.consome(',') {
+				break
+			}
+		}
+		if !p.consome(')') {
+			return nil, fmt.Errorf("falta ')' na lista de %q", campo)
+		}
+	} else {
+		v, err := p.valor()
+		if err != nil {
+			return nil, err
+		}
+		valores = []string{v}
+	}
+	return rsqlComparacao{campo: campo, op: op, valores: valores}, nil
+}
+
+// valor lê um valor entre aspas (com \" e \\) ou sem aspas até o próximo separador
+func (p *rsqlParser) valor() (string, error) {
+	if p.consome('"') {
+		var sb strings.Builder
+		for p.i < len(p.s) {
+			c := p.s[p.i]
+			p.i++
+			switch {
+			case c == '\\' && p.i < len(p.s):
+				sb.WriteByte(p.s[p.i])
+				p.i++
+			case c == '"':
+				return sb.String(), nil
+			default:
+				sb.WriteByte(c)
+			}
+		}
+		return "", fmt.Errorf("aspas não fechadas")
+	}
+	inicio := p.i
+	for p.i < len(p.s) && strings.IndexByte(";,()", p.s[p.i]) < 0 {
+		p.i++
+	}
+	if p.i == inicio {
+		return "", fmt.Errorf("valor vazio na posição %d", p.i)
+	}
+	return p.s[inicio:p.i], nil
+}
+
+func (p *rsqlParser) consome(c byte) bool {
+	if p.i < len(p.s) && p.s[p.i] == c {
+		p.i++
+		return true
+	}
+	return false
+}
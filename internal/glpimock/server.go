@@ -0,0 +1,694 @@
+// Package glpimock é um servidor GLPI falso que fala o subconjunto da High-Level API (v2) usado
+// pelo client: /token, /Assistance/Ticket (lista com Content-Range, leitura, criação e PATCH),
+// TeamMember, Timeline/Followup e /Administration/User/Me. Os dados vêm de uma Fixture e ficam
+// em memória; falhas e latência podem ser injetadas para exercitar as retentativas.
+//
+// Em testes de integração:
+//
+//	srv := httptest.NewServer(glpimock.New(glpimock.DefaultFixture()))
+//	defer srv.Close()
+//	cfg := &config.Config{BaseURL: srv.URL, ClientID: "glpi-tui", ClientSecret: "segredo", Username: "ana", Password: "ana123"}
+//
+// Fora deles, o comando cmd/glpi-mock sobe o mesmo servidor numa porta local.
+package glpimock
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	mrand "math/rand"
+	"net/http"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"glpi-tui/internal/domain"
+)
+
+// dateLayout é o formato de data que o GLPI devolve
+const dateLayout = "2006-01-02 15:04:05"
+
+// Server é o http.Handler do GLPI falso. Latency e FailRate devem ser definidos antes de servir requisições.
+type Server struct {
+	// Latency atrasa cada resposta, como se fosse a rede
+	Latency time.Duration
+	// FailRate é a fração das requisições (0 a 1), fora o /token, que recebem HTTP 503
+	FailRate float64
+
+	mu          sync.Mutex
+	ttl         time.Duration
+	clients     []Client
+	users       []User
+	groups      map[int]Group
+	tickets     map[int]*Ticket
+	faults      []Fault
+	tokens      map[string]sessao // access_token → sessão
+	refresh     map[string]int    // refresh_token → users_id
+	proximoID   int               // Próximo ID de chamado
+	proximoItem int               // Próximo ID de acompanhamento
+	aleat       *mrand.Rand
+}
+
+type sessao struct {
+	userID int
+	expira time.Time
+}
+
+// New cria o servidor com uma cópia dos dados da fixture (a fixture pode ser reaproveitada)
+func New(fx *Fixture) *Server {
+	s := &Server{
+		ttl:         time.Hour,
+		clients:     append([]Client(nil), fx.Clients...),
+		users:       append([]User(nil), fx.Users...),
+		groups:      map[int]Group{},
+		tickets:     map[int]*Ticket{},
+		faults:      append([]Fault(nil), fx.Faults...),
+		tokens:      map[string]sessao{},
+		refresh:     map[string]int{},
+		proximoID:   1,
+		proximoItem: 1,
+		aleat:       mrand.New(mrand.NewSource(time.Now().UnixNano())),
+	}
+	if fx.TokenTTL > 0 {
+		s.ttl = time.Duration(fx.TokenTTL) * time.Second
+	}
+	for _, g := range fx.Groups {
+		s.groups[g.ID] = g
+	}
+	for _, t := range fx.Tickets {
+		t.Team = append([]domain.TicketActor(nil), t.Team...)
+		t.Followups = append([]domain.TicketFollowup(nil), t.Followups...)
+		s.tickets[t.ID] = &t
+		s.proximoID = max(s.proximoID, t.ID+1)
+		for _, f := range t.Followups {
+			s.proximoItem = max(s.proximoItem, f.ID+1)
+		}
+	}
+	return s
+}
+
+// Inject acrescenta uma falha às que vieram da fixture
+func (s *Server) Inject(f Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = append(s.faults, f)
+}
+
+// ClearFaults remove todas as falhas injetadas
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = nil
+}
+
+// ExpireTokens invalida os access_tokens emitidos, para exercitar a renovação após um 401
+func (s *Server) ExpireTokens() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens = map[string]sessao{}
+}
+
+// Ticket devolve uma cópia do chamado como está agora (ok = false se não existir)
+func (s *Server) Ticket(id int) (Ticket, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.tickets[id]
+	if !ok {
+		return Ticket{}, false
+	}
+	c := *t
+	c.Team = append([]domain.TicketActor(nil), t.Team...)
+	c.Followups = append([]domain.TicketFollowup(nil), t.Followups...)
+	return c, true
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if s.Latency > 0 {
+		select {
+		case <-time.After(s.Latency):
+		case <-r.Context().Done():
+			return
+		}
+	}
+	if s.falhaInjetada(w, r) {
+		return
+	}
+	if r.URL.Path == "/token" {
+		s.token(w, r)
+		return
+	}
+
+	userID, ok := s.autenticar(r)
+	if !ok {
+		erroGLPI(w, http.StatusUnauthorized, "ERROR_UNAUTHENTICATED", "token ausente, inválido ou expirado")
+		return
+	}
+
+	partes := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	case rota(partes, "Administration", "User", "Me"):
+		s.me(w, r, userID)
+	case rota(partes, "Administration", "User", "Me", "Group"):
+		s.meusGrupos(w, r, userID)
+	case rota(partes, "Assistance", "Ticket"):
+		switch r.Method {
+		case http.MethodGet:
+			s.listarChamados(w, r)
+		case http.MethodPost:
+			s.criarChamado(w, r, userID)
+		default:
+			metodoInvalido(w)
+		}
+	case rota(partes, "Assistance", "Ticket", "*"):
+		switch r.Method {
+		case http.MethodGet:
+			s.chamado(w, r, partes[2])
+		case http.MethodPatch:
+			s.atualizarChamado(w, r, partes[2])
+		default:
+			metodoInvalido(w)
+		}
+	case rota(partes, "Assistance", "Ticket", "*", "TeamMember"):
+		s.equipe(w, r, partes[2])
+	case rota(partes, "Assistance", "Ticket", "*", "Timeline"), rota(partes, "Assistance", "Ticket", "*", "Timeline", "Followup"):
+		switch r.Method {
+		case http.MethodGet:
+			s.acompanhamentos(w, r, partes[2])
+		case http.MethodPost:
+			if len(partes) == 4 { // Só acompanhamentos podem ser criados
+				metodoInvalido(w)
+				return
+			}
+			s.criarAcompanhamento(w, r, partes[2], userID)
+		default:
+			metodoInvalido(w)
+		}
+	default:
+		erroGLPI(w, http.StatusNotFound, "ERROR_NOT_FOUND", "rota não implementada no mock: "+r.Method+" "+r.URL.Path)
+	}
+}
+
+// rota confere os segmentos do caminho; "*" casa qualquer segmento
+func rota(partes []string, esperado ...string) bool {
+	if len(partes) != len(esperado) {
+		return false
+	}
+	for i, e := range esperado {
+		if e != "*" && e != partes[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// falhaInjetada responde com a primeira falha que casar com a requisição, ou com o 503 do FailRate
+func (s *Server) falhaInjetada(w http.ResponseWriter, r *http.Request) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for i, f := range s.faults {
+		if f.Method != "" && !strings.EqualFold(f.Method, r.Method) {
+			continue
+		}
+		if ok, _ := path.Match(f.Path, r.URL.Path); !ok {
+			continue
+		}
+		if f.Times > 0 {
+			if f.Times == 1 {
+				s.faults = append(s.faults[:i:i], s.faults[i+1:]...)
+			} else {
+				s.faults[i].Times--
+			}
+		}
+		if f.Body != "" {
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(f.Status)
+			fmt.Fprint(w, f.Body)
+		} else {
+			erroGLPI(w, f.Status, "ERROR_INJECTED", "falha injetada")
+		}
+		return true
+	}
+
+	if r.URL.Path != "/token" && s.FailRate > 0 && s.aleat.Float64() < s.FailRate {
+		erroGLPI(w, http.StatusServiceUnavailable, "ERROR_INJECTED", "falha simulada")
+		return true
+	}
+	return false
+}
+
+// token implementa os grants password e refresh_token do OAuth2; aceita corpo JSON ou formulário
+func (s *Server) token(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		metodoInvalido(w)
+		return
+	}
+	campos := map[string]string{}
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "application/json") {
+		if err := json.NewDecoder(r.Body).Decode(&campos); err != nil {
+			erroOAuth(w, "invalid_request", "JSON inválido")
+			return
+		}
+	} else {
+		if err := r.ParseForm(); err != nil {
+			erroOAuth(w, "invalid_request", "formulário inválido")
+			return
+		}
+		for k := range r.PostForm {
+			campos[k] = r.PostForm.Get(k)
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.clienteValido(campos["client_id"], campos["client_secret"]) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"error":"invalid_client","error_description":"cliente OAuth desconhecido"}`)
+		return
+	}
+
+	userID := 0
+	switch campos["grant_type"] {
+	case "password":
+		for _, u := range s.users {
+			if u.Username == campos["username"] && u.Password != "" && u.Password == campos["password"] {
+				userID = u.ID
+			}
+		}
+		if userID == 0 {
+			erroOAuth(w, "invalid_grant", "usuário ou senha inválidos")
+			return
+		}
+	case "refresh_token":
+		var ok bool
+		if userID, ok = s.refresh[campos["refresh_token"]]; !ok {
+			erroOAuth(w, "invalid_grant", "refresh_token inválido")
+			return
+		}
+		delete(s.refresh, campos["refresh_token"]) // Rotaciona, como o GLPI
+	default:
+		erroOAuth(w, "unsupported_grant_type", "grant não suportado pelo mock: "+campos["grant_type"])
+		return
+	}
+
+	access, renovacao := aleatorio(), aleatorio()
+	s.tokens[access] = sessao{userID: userID, expira: time.Now().Add(s.ttl)}
+	s.refresh[renovacao] = userID
+	escreverJSON(w, http.StatusOK, map[string]any{
+		"access_token":  access,
+		"token_type":    "Bearer",
+		"expires_in":    int(s.ttl.Seconds()),
+		"refresh_token": renovacao,
+	})
+}
+
+func (s *Server) clienteValido(id, secret string) bool {
+	if len(s.clients) == 0 {
+		return true
+	}
+	for _, c := range s.clients {
+		if c.ID == id && (c.Secret == "" || c.Secret == secret) {
+			return true
+		}
+	}
+	return false
+}
+
+// autenticar confere o Bearer token e devolve o usuário dono dele
+func (s *Server) autenticar(r *http.Request) (int, bool) {
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok {
+		return 0, false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sess, ok := s.tokens[token]
+	if !ok || time.Now().After(sess.expira) {
+		delete(s.tokens, token)
+		return 0, false
+	}
+	return sess.userID, true
+}
+
+func (s *Server) usuario(id int) (User, bool) {
+	for _, u := range s.users {
+		if u.ID == id {
+			return u, true
+		}
+	}
+	return User{}, false
+}
+
+func (s *Server) me(w http.ResponseWriter, r *http.Request, userID int) {
+	if r.Method != http.MethodGet {
+		metodoInvalido(w)
+		return
+	}
+	s.mu.Lock()
+	u, _ := s.usuario(userID)
+	s.mu.Unlock()
+	escreverJSON(w, http.StatusOK, map[string]any{"id": u.ID, "username": u.Username, "realname": u.Name})
+}
+
+func (s *Server) meusGrupos(w http.ResponseWriter, r *http.Request, userID int) {
+	if r.Method != http.MethodGet {
+		metodoInvalido(w)
+		return
+	}
+	s.mu.Lock()
+	u, _ := s.usuario(userID)
+	grupos := []Group{}
+	for _, id := range u.Groups {
+		g, ok := s.groups[id]
+		if !ok {
+			g = Group{ID: id, Name: fmt.Sprintf("Grupo %d", id)}
+		}
+		grupos = append(grupos, g)
+	}
+	s.mu.Unlock()
+	escreverJSON(w, http.StatusOK, grupos)
+}
+
+// listarChamados aplica filter, sort, start e limit. Como o GLPI, responde 206 com Content-Range
+// quando a página não traz todos os itens.
+func (s *Server) listarChamados(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	start, err1 := inteiro(q.Get("start"), 0)
+	limit, err2 := inteiro(q.Get("limit"), 20)
+	if err1 != nil || err2 != nil || start < 0 || limit <= 0 {
+		erroGLPI(w, http.StatusBadRequest, "ERROR_BAD_REQUEST", "start/limit inválidos")
+		return
+	}
+	var filtro rsqlNo
+	if f := q.Get("filter"); f != "" {
+		var err error
+		if filtro, err = parseRSQL(f); err != nil {
+			erroGLPI(w, http.StatusBadRequest, "ERROR_RSQL", "filtro inválido: "+err.Error())
+			return
+		}
+	}
+	menor, err := comparador(q.Get("sort"))
+	if err != nil {
+		erroGLPI(w, http.StatusBadRequest, "ERROR_BAD_REQUEST", err.Error())
+		return
+	}
+
+	s.mu.Lock()
+	var todos []itemLista
+	for _, t := range s.tickets {
+		if filtro == nil || filtro.casa(t, nil) {
+			todos = append(todos, itemLista{comNomes(t.Chamado), append([]domain.TicketActor{}, t.Team...)})
+		}
+	}
+	s.mu.Unlock()
+	sort.SliceStable(todos, func(i, j int) bool { return menor(todos[i].Chamado, todos[j].Chamado) })
+
+	pagina := []itemLista{}
+	if start < len(todos) {
+		pagina = todos[start:min(start+limit, len(todos))]
+	}
+	status := http.StatusOK
+	if len(pagina) < len(todos) {
+		status = http.StatusPartialContent
+	}
+	if len(pagina) == 0 {
+		w.Header().Set("Content-Range", fmt.Sprintf("*/%d", len(todos)))
+	} else {
+		w.Header().Set("Content-Range", fmt.Sprintf("%d-%d/%d", start, start+len(pagina)-1, len(todos)))
+	}
+	escreverJSON(w, status, pagina)
+}
+
+// comparador traduz o "property:direction" do parâmetro sort (vazio = date_mod:desc)
+func comparador(ordem string) (func(a, b domain.Chamado) bool, error) {
+	if ordem == "" {
+		ordem = "date_mod:desc"
+	}
+	prop, dir, _ := strings.Cut(ordem, ":")
+	var menor func(a, b domain.Chamado) bool
+	switch prop {
+	case "id":
+		menor = func(a, b domain.Chamado) bool { return a.ID < b.ID }
+	case "name":
+		menor = func(a, b domain.Chamado) bool { return strings.ToLower(a.Name) < strings.ToLower(b.Name) }
+	case "date":
+		menor = func(a, b domain.Chamado) bool { return a.Date < b.Date }
+	case "date_mod":
+		menor = func(a, b domain.Chamado) bool { return a.DateMod < b.DateMod }
+	case "priority":
+		menor = func(a, b domain.Chamado) bool { return a.Priority < b.Priority }
+	case "status", "status.id":
+		menor = func(a, b domain.Chamado) bool { return a.Status.ID < b.Status.ID }
+	default:
+		return nil, fmt.Errorf("ordenação inválida: %s", prop)
+	}
+	switch strings.ToLower(dir) {
+	case "", "asc":
+		return menor, nil
+	case "desc":
+		return func(a, b domain.Chamado) bool { return menor(b, a) }, nil
+	default:
+		return nil, fmt.Errorf("direção de ordenação inválida: %s", dir)
+	}
+}
+
+// itemLista é o chamado como a lista devolve: o schema Ticket traz a equipe em "team"
+type itemLista struct {
+	domain.Chamado
+	Team []domain.TicketActor `json:"team"`
+}
+
+// comNomes preenche o nome do status, que o GLPI sempre devolve junto com o ID
+func comNomes(c domain.Chamado) domain.Chamado {
+	if c.Status.Name == "" {
+		c.Status.Name = domain.StatusLabel(c.Status.ID)
+	}
+	return c
+}
+
+// chamadoPorID devolve o chamado do segmento {id} ou responde 404. Deve ser chamado com mu travado.
+func (s *Server) chamadoPorID(w http.ResponseWriter, segmento string) (*Ticket, bool) {
+	id, err := strconv.Atoi(segmento)
+	t, ok := s.tickets[id]
+	if err != nil || !ok {
+		erroGLPI(w, http.StatusNotFound, "ERROR_ITEM_NOT_FOUND", "chamado não encontrado: "+segmento)
+		return nil, false
+	}
+	return t, true
+}
+
+func (s *Server) chamado(w http.ResponseWriter, r *http.Request, segmento string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.chamadoPorID(w, segmento); ok {
+		escreverJSON(w, http.StatusOK, comNomes(t.Chamado))
+	}
+}
+
+// criarChamado recebe o mesmo corpo do formulário do GLPI; sem requerentes, o autor vira requerente
+func (s *Server) criarChamado(w http.ResponseWriter, r *http.Request, userID int) {
+	var in struct {
+		Name         string `json:"name"`
+		Content      string `json:"content"`
+		Urgency      int    `json:"urgency"`
+		EntityID     int    `json:"entities_id"`
+		RequesterIDs []int  `json:"_users_id_requester"`
+		ObserverIDs  []int  `json:"_users_id_observer"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil {
+		erroGLPI(w, http.StatusBadRequest, "ERROR_BAD_REQUEST", "JSON inválido: "+err.Error())
+		return
+	}
+	if strings.TrimSpace(in.Name) == "" || strings.TrimSpace(in.Content) == "" {
+		erroGLPI(w, http.StatusBadRequest, "ERROR_BAD_REQUEST", "name e content são obrigatórios")
+		return
+	}
+	if len(in.RequesterIDs) == 0 {
+		in.RequesterIDs = []int{userID}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	agora := time.Now().Format(dateLayout)
+	t := &Ticket{Chamado: domain.Chamado{
+		ID:       s.proximoID,
+		Name:     in.Name,
+		Content:  in.Content,
+		Date:     agora,
+		DateMod:  agora,
+		Status:   domain.TicketStatus{ID: domain.StatusNew},
+		Priority: max(in.Urgency, 1),
+		Entity:   domain.TicketEntity{ID: in.EntityID, Name: fmt.Sprintf("Entidade %d", in.EntityID)},
+	}}
+	if in.EntityID == 0 {
+		t.Entity.Name = "Entidade raiz"
+	}
+	for _, id := range in.RequesterIDs {
+		t.Team = append(t.Team, s.ator(id, "requester"))
+	}
+	for _, id := range in.ObserverIDs {
+		t.Team = append(t.Team, s.ator(id, "observer"))
+	}
+	s.tickets[t.ID] = t
+	s.proximoID++
+
+	w.Header().Set("Location", fmt.Sprintf("/Assistance/Ticket/%d", t.ID))
+	escreverJSON(w, http.StatusCreated, map[string]any{"id": t.ID, "href": fmt.Sprintf("/Assistance/Ticket/%d", t.ID)})
+}
+
+// ator monta o membro da equipe de um usuário. Deve ser chamado com mu travado.
+func (s *Server) ator(userID int, papel string) domain.TicketActor {
+	nome := fmt.Sprintf("Usuário %d", userID)
+	if u, ok := s.usuario(userID); ok {
+		nome = u.Name
+	}
+	return domain.TicketActor{ID: userID, Name: nome, Type: "User", Role: papel}
+}
+
+// atualizarChamado aplica o PATCH com envelope "input": status, priority, name, content e
+// users_id_assign (acrescenta o técnico à equipe)
+func (s *Server) atualizarChamado(w http.ResponseWriter, r *http.Request, segmento string) {
+	var corpo struct {
+		Input *struct {
+			Status        *int    `json:"status"`
+			Priority      *int    `json:"priority"`
+			Name          *string `json:"name"`
+			Content       *string `json:"content"`
+			UsersIDAssign *int    `json:"users_id_assign"`
+		} `json:"input"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&corpo); err != nil || corpo.Input == nil {
+		erroGLPI(w, http.StatusBadRequest, "ERROR_BAD_REQUEST", `corpo precisa do envelope "input"`)
+		return
+	}
+	in := corpo.Input
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.chamadoPorID(w, segmento)
+	if !ok {
+		return
+	}
+	if in.Status != nil {
+		if *in.Status < domain.StatusNew || *in.Status > domain.StatusClosed {
+			erroGLPI(w, http.StatusBadRequest, "ERROR_BAD_REQUEST", fmt.Sprintf("status inválido: %d", *in.Status))
+			return
+		}
+		t.Status = domain.TicketStatus{ID: *in.Status}
+	}
+	if in.Priority != nil {
+		t.Priority = *in.Priority
+	}
+	if in.Name != nil {
+		t.Name = *in.Name
+	}
+	if in.Content != nil {
+		t.Content = *in.Content
+	}
+	if in.UsersIDAssign != nil {
+		tecnico := s.ator(*in.UsersIDAssign, "assigned")
+		if !algumMembro(t, func(m *domain.TicketActor) bool { return *m == tecnico }) {
+			t.Team = append(t.Team, tecnico)
+		}
+	}
+	t.DateMod = time.Now().Format(dateLayout)
+	escreverJSON(w, http.StatusOK, comNomes(t.Chamado))
+}
+
+func (s *Server) equipe(w http.ResponseWriter, r *http.Request, segmento string) {
+	if r.Method != http.MethodGet {
+		metodoInvalido(w)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if t, ok := s.chamadoPorID(w, segmento); ok {
+		escreverJSON(w, http.StatusOK, append([]domain.TicketActor{}, t.Team...))
+	}
+}
+
+// acompanhamentos devolve os acompanhamentos no envelope {"type", "item"} da timeline.
+// O mock só guarda acompanhamentos, então a timeline completa é a mesma lista.
+func (s *Server) acompanhamentos(w http.ResponseWriter, r *http.Request, segmento string) {
+	type envelope struct {
+		Type string                `json:"type"`
+		Item domain.TicketFollowup `json:"item"`
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.chamadoPorID(w, segmento)
+	if !ok {
+		return
+	}
+	itens := []envelope{}
+	for _, f := range t.Followups {
+		itens = append(itens, envelope{Type: "Followup", Item: f})
+	}
+	escreverJSON(w, http.StatusOK, itens)
+}
+
+func (s *Server) criarAcompanhamento(w http.ResponseWriter, r *http.Request, segmento string, userID int) {
+	var in struct {
+		Content string `json:"content"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&in); err != nil || strings.TrimSpace(in.Content) == "" {
+		erroGLPI(w, http.StatusBadRequest, "ERROR_BAD_REQUEST", "content é obrigatório")
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	t, ok := s.chamadoPorID(w, segmento)
+	if !ok {
+		return
+	}
+	autor := s.ator(userID, "")
+	f := domain.TicketFollowup{
+		ID:      s.proximoItem,
+		Date:    time.Now().Format(dateLayout),
+		Content: in.Content,
+		User:    domain.TicketFollowupUser{ID: autor.ID, Name: autor.Name},
+	}
+	s.proximoItem++
+	t.Followups = append(t.Followups, f)
+	t.DateMod = f.Date
+	escreverJSON(w, http.StatusCreated, map[string]any{"id": f.ID, "href": fmt.Sprintf("/Assistance/Ticket/%d/Timeline/Followup/%d", t.ID, f.ID)})
+}
+
+func inteiro(v string, padrao int) (int, error) {
+	if v == "" {
+		return padrao, nil
+	}
+	return strconv.Atoi(v)
+}
+
+func aleatorio() string {
+	b := make([]byte, 20)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+func escreverJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// erroGLPI responde no formato de erro da High-Level API
+func erroGLPI(w http.ResponseWriter, status int, codigo, detalhe string) {
+	escreverJSON(w, status, map[string]any{"status": codigo, "title": http.StatusText(status), "detail": detalhe})
+}
+
+func erroOAuth(w http.ResponseWriter, codigo, descricao string) {
+	escreverJSON(w, http.StatusBadRequest, map[string]string{"error": codigo, "error_description": descricao})
+}
+
+func metodoInvalido(w http.ResponseWriter) {
+	erroGLPI(w, http.StatusMethodNotAllowed, "ERROR_METHOD_NOT_ALLOWED", "método não suportado pelo mock")
+}
@@ -0,0 +1,236 @@
+// Package markdown converte o Markdown digitado nas respostas em HTML aceito pelo GLPI.
+//
+// Cobre o subconjunto usado no dia a dia de um chamado: parágrafos (quebras de linha
+// preservadas), títulos, listas (inclusive aninhadas), citações, blocos de código,
+// negrito, itálico, código inline e links. Todo o texto é escapado: HTML digitado
+// pelo usuário aparece literal, nunca é repassado ao GLPI.
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var (
+	reHeading   = regexp.MustCompile(`^(#{1,6})\s+(.*?)\s*#*\s*$`)
+	reListItem  = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+	reRule      = regexp.MustCompile(`^\s{0,3}([-*_])(\s*[-*_]){2,}\s*$`)
+	reFence     = regexp.MustCompile("^\\s{0,3}(```+|~~~+)\\s*(\\S*)")
+	reQuote     = regexp.MustCompile(`^\s{0,3}>\s?(.*)$`)
+	reCode      = regexp.MustCompile("`([^`]+)`")
+	reEscape    = regexp.MustCompile(`\\([\\` + "`" + `*_\[\]()#>+.!-])`)
+	reLink      = regexp.MustCompile(`\[([^\]]+)\]\((` + alvoLink + `)\)`)
+	reURL       = regexp.MustCompile(`\bhttps?://(?:[^\s<>()]|\([^\s<>()]*\))+`)
+	reStrong    = regexp.MustCompile(`\*\*(\S(?:.*?\S)?)\*\*|__(\S(?:.*?\S)?)__`)
+	reEm        = regexp.MustCompile(`\*(\S(?:[^*]*?\S)?)\*`)
+	reEmUnder   = regexp.MustCompile(`(^|[^\w])_(\S(?:[^_]*?\S)?)_($|[^\w])`)
+	reToken     = regexp.MustCompile("\x00(\\d+)\x00")
+	safeSchemes = []string{"http://", "https://", "mailto:"}
+)
+
+// alvoLink é o destino de [texto](destino): parênteses equilibrados (até dois níveis, como em
+// .../wiki/Go_(programming_language)) fazem parte do endereço; o ")" que sobra fecha o link
+const alvoLink = `(?:[^()\s]|\((?:[^()\s]|\([^()\s]*\))*\))+`
+
+// ToHTML converte Markdown em HTML seguro para o campo content do GLPI
+func ToHTML(src string) string {
+	src = strings.ReplaceAll(src, "\r\n", "\n")
+	src = strings.ReplaceAll(src, "\t", "    ")
+	return renderBlocks(strings.Split(strings.Trim(src, "\n"), "\n"))
+}
+
+// renderBlocks percorre as linhas identificando blocos (parágrafo, lista, código...)
+func renderBlocks(lines []string) string {
+	var sb strings.Builder
+	var para []string
+
+	flush := func() {
+		if len(para) == 0 {
+			return
+		}
+		parts := make([]string, len(para))
+		for i, l := range para {
+			parts[i] = inline(strings.TrimSpace(l))
+		}
+		// Quebra simples vira <br>: quem escreve chamado espera ver as linhas como digitou
+		sb.WriteString("<p>" + strings.Join(parts, "<br>") + "</p>")
+		para = nil
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+
+		switch {
+		case strings.TrimSpace(line) == "":
+			flush()
+
+		case reFence.MatchString(line):
+			flush()
+			fence := reFence.FindStringSubmatch(line)[1]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			sb.WriteString("<pre><code>" + html.EscapeString(strings.Join(code, "\n")) + "</code></pre>")
+
+		case reHeading.MatchString(line):
+			flush()
+			m := reHeading.FindStringSubmatch(line)
+			sb.WriteString(fmt.Sprintf("<h%d>%s</h%d>", len(m[1]), inline(m[2]), len(m[1])))
+
+		case reRule.MatchString(line):
+			flush()
+			sb.WriteString("<hr>")
+
+		case reQuote.MatchString(line):
+			flush()
+			var quoted []string
+			for ; i < len(lines) && reQuote.MatchString(lines[i]); i++ {
+				quoted = append(quoted, reQuote.FindStringSubmatch(lines[i])[1])
+			}
+			i--
+			sb.WriteString("<blockquote>" + renderBlocks(quoted) + "</blockquote>")
+
+		case reListItem.MatchString(line) && (len(para) == 0 || !isOrdered(line)):
+			// Um número no meio do parágrafo ("2. tentativa") só abre lista após linha em branco
+			flush()
+			out, n := renderList(lines[i:])
+			sb.WriteString(out)
+			i += n - 1
+
+		default:
+			para = append(para, line)
+		}
+	}
+	flush()
+	return sb.String()
+}
+
+// renderList consome a lista que começa em lines[0] e devolve o HTML e quantas linhas usou
+func renderList(lines []string) (string, int) {
+	first := reListItem.FindStringSubmatch(lines[0])
+	base := len(first[1])
+	content := base + len(first[2]) + 1 // Coluna onde começa o texto do item
+	ordered := isOrdered(lines[0])
+
+	var items []*listItem
+	used := 0
+
+	for used < len(lines) {
+		line := lines[used]
+		m := reListItem.FindStringSubmatch(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		switch {
+		case m != nil && len(m[1]) <= base+1 && isOrdered(line) == ordered:
+			items = append(items, &listItem{lines: []string{m[3]}})
+		case m != nil && len(m[1]) <= base+1:
+			// Troca de tipo de lista no mesmo nível encerra esta lista
+			return wrapList(ordered, first[2], itemsHTML(items)), used
+		case strings.TrimSpace(line) == "":
+			// Linha em branco só continua a lista se a próxima linha ainda pertencer a ela
+			if used+1 >= len(lines) {
+				return wrapList(ordered, first[2], itemsHTML(items)), used + 1
+			}
+			next := lines[used+1]
+			nextIndent := len(next) - len(strings.TrimLeft(next, " "))
+			if !reListItem.MatchString(next) && nextIndent <= base {
+				return wrapList(ordered, first[2], itemsHTML(items)), used
+			}
+			items[len(items)-1].lines = append(items[len(items)-1].lines, "")
+		case indent > base:
+			// Continuação ou sublista do item atual
+			items[len(items)-1].lines = append(items[len(items)-1].lines, line[min(indent, content):])
+		default:
+			return wrapList(ordered, first[2], itemsHTML(items)), used
+		}
+		used++
+	}
+	return wrapList(ordered, first[2], itemsHTML(items)), used
+}
+
+// listItem guarda a primeira linha do item e as linhas de continuação/sublista já sem recuo
+type listItem struct{ lines []string }
+
+func itemsHTML(items []*listItem) string {
+	var sb strings.Builder
+	for _, it := range items {
+		lines := it.lines
+		sb.WriteString("<li>" + inline(strings.TrimSpace(lines[0])))
+		if rest := lines[1:]; len(rest) > 0 {
+			sb.WriteString(renderBlocks(rest))
+		}
+		sb.WriteString("</li>")
+	}
+	return sb.String()
+}
+
+func wrapList(ordered bool, marker, items string) string {
+	if !ordered {
+		return "<ul>" + items + "</ul>"
+	}
+	start := strings.TrimRight(marker, ".)")
+	if n, err := strconv.Atoi(start); err == nil && n != 1 {
+		return fmt.Sprintf(`<ol start="%d">%s</ol>`, n, items)
+	}
+	return "<ol>" + items + "</ol>"
+}
+
+func isOrdered(line string) bool {
+	m := reListItem.FindStringSubmatch(line)
+	return m != nil && m[2][0] >= '0' && m[2][0] <= '9'
+}
+
+// inline trata a formatação dentro de uma linha. Trechos já convertidos (código, links)
+// ficam guardados como marcadores para não serem escapados ou formatados de novo.
+func inline(text string) string {
+	var saved []string
+	protect := func(s string) string {
+		saved = append(saved, s)
+		return "\x00" + strconv.Itoa(len(saved)-1) + "\x00"
+	}
+
+	text = reCode.ReplaceAllStringFunc(text, func(m string) string {
+		return protect("<code>" + html.EscapeString(reCode.FindStringSubmatch(m)[1]) + "</code>")
+	})
+	text = reEscape.ReplaceAllStringFunc(text, func(m string) string {
+		return protect(html.EscapeString(m[1:]))
+	})
+	text = reLink.ReplaceAllStringFunc(text, func(m string) string {
+		sm := reLink.FindStringSubmatch(m)
+		if !isSafeURL(sm[2]) {
+			return protect(inline(sm[1])) // Esquemas como javascript: viram só o texto
+		}
+		return protect(fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(sm[2]), inline(sm[1])))
+	})
+	text = reURL.ReplaceAllStringFunc(text, func(m string) string {
+		url := strings.TrimRight(m, ".,;:!?")
+		return protect(fmt.Sprintf(`<a href="%s">%s</a>`, html.EscapeString(url), html.EscapeString(url))) + m[len(url):]
+	})
+
+	text = html.EscapeString(text)
+	text = reStrong.ReplaceAllStringFunc(text, func(m string) string {
+		sm := reStrong.FindStringSubmatch(m)
+		return "<strong>" + sm[1] + sm[2] + "</strong>"
+	})
+	text = reEm.ReplaceAllString(text, "<em>$1</em>")
+	text = reEmUnder.ReplaceAllString(text, "$1<em>$2</em>$3")
+
+	return reToken.ReplaceAllStringFunc(text, func(m string) string {
+		i, _ := strconv.Atoi(reToken.FindStringSubmatch(m)[1])
+		return saved[i]
+	})
+}
+
+func isSafeURL(u string) bool {
+	lower := strings.ToLower(u)
+	for _, s := range safeSchemes {
+		if strings.HasPrefix(lower, s) {
+			return true
+		}
+	}
+	return false
+}
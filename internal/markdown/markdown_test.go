@@ -0,0 +1,49 @@
+package markdown
+
+import "testing"
+
+func TestToHTML(t *testing.T) {
+	casos := []struct {
+		nome, md, html string
+	}{
+		{"parágrafo com quebras", "linha 1\nlinha 2\r\n\r\noutro", "<p>linha 1<br>linha 2</p><p>outro</p>"},
+		{"título", "## Passos ##", "<h2>Passos</h2>"},
+		{"citação", "> citação\n> segunda", "<blockquote><p>citação<br>segunda</p></blockquote>"},
+		{"linha horizontal", "antes\n\n---\n\ndepois", "<p>antes</p><hr><p>depois</p>"},
+
+		{"lista", "- a\n- b", "<ul><li>a</li><li>b</li></ul>"},
+		{"lista numerada", "1. um\n2. dois", "<ol><li>um</li><li>dois</li></ol>"},
+		{"lista numerada a partir de 3", "3. três\n4. quatro", `<ol start="3"><li>três</li><li>quatro</li></ol>`},
+		{"lista aninhada", "- a\n- b\n  - c\n  - d\n- e", "<ul><li>a</li><li>b<ul><li>c</li><li>d</li></ul></li><li>e</li></ul>"},
+		{"numerada com sublista", "1. um\n   - x\n2. dois", "<ol><li>um<ul><li>x</li></ul></li><li>dois</li></ol>"},
+		{"número no meio do parágrafo", "texto\n2. tentativa", "<p>texto<br>2. tentativa</p>"},
+
+		{"bloco de código", "```go\nif a < b {\n\t**x**\n}\n```", "<pre><code>if a &lt; b {\n    **x**\n}</code></pre>"},
+		{"código inline", "rode `ls <dir>` e **pronto**", "<p>rode <code>ls &lt;dir&gt;</code> e <strong>pronto</strong></p>"},
+		{"ênfase", "*itálico*, _também_ e __forte__", "<p><em>itálico</em>, <em>também</em> e <strong>forte</strong></p>"},
+		{"sem ênfase dentro de palavras", "nome_do_arquivo", "<p>nome_do_arquivo</p>"},
+
+		{"HTML digitado é escapado", `<script>alert(1)</script> & "aspas"`, "<p>&lt;script&gt;alert(1)&lt;/script&gt; &amp; &#34;aspas&#34;</p>"},
+		{"barra invertida", `\*literal\* e \[x\]`, "<p>*literal* e [x]</p>"},
+
+		{"link", "[site](https://glpi.example.com/a?b=1&c=2)", `<p><a href="https://glpi.example.com/a?b=1&amp;c=2">site</a></p>`},
+		{"link mailto", "[ti](mailto:ti@example.com)", `<p><a href="mailto:ti@example.com">ti</a></p>`},
+		{"link com parênteses", "[Go](https://en.wikipedia.org/wiki/Go_(programming_language))",
+			`<p><a href="https://en.wikipedia.org/wiki/Go_(programming_language)">Go</a></p>`},
+		{"link entre parênteses", "(ver [doc](https://a.example/x))", `<p>(ver <a href="https://a.example/x">doc</a>)</p>`},
+		{"javascript vira texto", "[x](javascript:alert(1))", "<p>x</p>"},
+		{"data vira texto", "[d](data:text/html,oi) fim", "<p>d fim</p>"},
+
+		{"URL solta", "veja https://glpi.example.com/front/ticket.php.", `<p>veja <a href="https://glpi.example.com/front/ticket.php">https://glpi.example.com/front/ticket.php</a>.</p>`},
+		{"URL solta com parênteses", "https://en.wikipedia.org/wiki/Go_(programming_language)",
+			`<p><a href="https://en.wikipedia.org/wiki/Go_(programming_language)">https://en.wikipedia.org/wiki/Go_(programming_language)</a></p>`},
+		{"URL solta entre parênteses", "(veja https://example.com/a)", `<p>(veja <a href="https://example.com/a">https://example.com/a</a>)</p>`},
+	}
+	for _, tc := range casos {
+		t.Run(tc.nome, func(t *testing.T) {
+			if got := ToHTML(tc.md); got != tc.html {
+				t.Errorf("ToHTML(%q)\n = %q\nesperado %q", tc.md, got, tc.html)
+			}
+		})
+	}
+}
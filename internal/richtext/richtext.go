@@ -0,0 +1,554 @@
+// Package richtext converte o HTML rico do GLPI (descrições, acompanhamentos, soluções...)
+// em texto para o terminal.
+//
+// Render preserva a estrutura (parágrafos, listas, tabelas, citações, blocos de código),
+// aplica negrito/itálico/sublinhado, troca imagens por marcadores, numera os links como
+// notas de rodapé e quebra as linhas na largura pedida. PlainText faz a mesma conversão
+// sem cores e sem quebra, para quando o texto vai parar num campo editável.
+package richtext
+
+import (
+	"fmt"
+	stdhtml "html"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/lipgloss/table"
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// larguraMinima evita colunas absurdamente estreitas em listas/citações muito aninhadas
+const larguraMinima = 10
+
+// Render converte o HTML em texto estilizado com as linhas quebradas em width colunas (0 = sem quebra)
+func Render(src string, width int) string {
+	return render(src, width, true)
+}
+
+// PlainText converte o HTML em texto puro, sem estilos e sem quebra de linha automática
+func PlainText(src string) string {
+	return render(src, 0, false)
+}
+
+// estilo acumula a formatação inline herdada dos elementos ancestrais
+type estilo struct {
+	negrito, italico, sublinhado, riscado bool
+	codigo, link, nota, imagem, titulo    bool
+}
+
+// trecho é um pedaço de texto inline com sua formatação; quebra representa um <br>
+type trecho struct {
+	texto  string
+	estilo estilo
+	quebra bool
+}
+
+type renderer struct {
+	styled       bool
+	links        []string // URLs na ordem em que viraram notas [1], [2]...
+	profundidade int      // Nível de aninhamento das listas (muda o marcador)
+	cache        map[estilo]lipgloss.Style
+}
+
+func render(src string, width int, styled bool) string {
+	src = normalizar(src)
+	if strings.TrimSpace(src) == "" {
+		return ""
+	}
+
+	body := &html.Node{Type: html.ElementNode, Data: "body", DataAtom: atom.Body}
+	nodes, err := html.ParseFragment(strings.NewReader(src), body)
+	if err != nil {
+		return strings.TrimSpace(src)
+	}
+	for _, n := range nodes {
+		body.AppendChild(n)
+	}
+
+	r := &renderer{styled: styled, cache: map[estilo]lipgloss.Style{}}
+	linhas := r.blocos(body, width, false)
+
+	if len(r.links) > 0 {
+		linhas = append(linhas, "")
+		for i, l := range r.links {
+			linhas = append(linhas, r.aplicar(estilo{nota: true}, fmt.Sprintf("[%d] %s", i+1, l)))
+		}
+	}
+	return strings.TrimRight(strings.Join(linhas, "\n"), "\n ")
+}
+
+// normalizar desfaz o escape duplo que o GLPI aplica ao conteúdo salvo (&lt;p&gt;...)
+// e trata conteúdo sem tag nenhuma (e-mails em texto puro) preservando as quebras de linha
+func normalizar(src string) string {
+	if !strings.Contains(src, "<") && strings.Contains(src, "&lt;") {
+		src = stdhtml.UnescapeString(src)
+	}
+	if !strings.Contains(src, "<") {
+		return strings.ReplaceAll(src, "\n", "<br>")
+	}
+	return src
+}
+
+// blocos renderiza os filhos de n: texto inline vira parágrafo quebrado na largura,
+// elementos de bloco são renderizados à parte. Em modo compacto (itens de lista)
+// não há linha em branco entre os blocos.
+func (r *renderer) blocos(n *html.Node, width int, compacto bool) []string {
+	var out []string
+	var inline []trecho
+	espacoAntes := false // O bloco anterior pede linha em branco depois dele
+
+	add := func(linhas []string, espacado bool) {
+		if len(linhas) == 0 {
+			return
+		}
+		if !compacto && len(out) > 0 && (espacado || espacoAntes) && out[len(out)-1] != "" {
+			out = append(out, "")
+		}
+		out = append(out, linhas...)
+		espacoAntes = espacado
+	}
+	flush := func() {
+		if temTexto(inline) {
+			add(r.quebrar(inline, width), false)
+		}
+		inline = nil
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		if c.Type == html.ElementNode && isBloco(c.DataAtom) {
+			flush()
+			add(r.bloco(c, width), espacado(c.DataAtom))
+			continue
+		}
+		inline = r.inline(c, inline, estilo{})
+	}
+	flush()
+	return out
+}
+
+func (r *renderer) bloco(n *html.Node, width int) []string {
+	switch n.DataAtom {
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		return r.quebrar(r.filhosInline(n, estilo{titulo: true}), width)
+
+	case atom.Ul, atom.Ol:
+		return r.lista(n, width)
+
+	case atom.Li:
+		// <li> solto, fora de <ul>/<ol>
+		return r.itemLista(n, "• ", width)
+
+	case atom.Blockquote:
+		barra := r.aplicar(estilo{nota: true}, "│ ")
+		interno := r.blocos(n, larguraUtil(width, 2), false)
+		for i, l := range interno {
+			interno[i] = barra + l
+		}
+		return interno
+
+	case atom.Pre:
+		texto := strings.Trim(textoPuro(n), "\n")
+		linhas := strings.Split(texto, "\n")
+		for i, l := range linhas {
+			linhas[i] = r.aplicar(estilo{codigo: true}, "  "+l)
+		}
+		return linhas
+
+	case atom.Hr:
+		w := width
+		if w <= 0 {
+			w = 20
+		}
+		return []string{r.aplicar(estilo{nota: true}, strings.Repeat("─", w))}
+
+	case atom.Table:
+		return r.tabela(n, width)
+	}
+	return r.blocos(n, width, n.DataAtom == atom.Div)
+}
+
+func (r *renderer) lista(n *html.Node, width int) []string {
+	ordenada := n.DataAtom == atom.Ol
+	num := 1
+	if v, err := strconv.Atoi(attr(n, "start")); err == nil {
+		num = v
+	}
+	marcadores := []string{"• ", "◦ ", "▪ "}
+	nivel := r.profundidade
+	r.profundidade++
+	defer func() { r.profundidade-- }()
+
+	var out []string
+	for li := n.FirstChild; li != nil; li = li.NextSibling {
+		if li.Type != html.ElementNode {
+			continue // Espaços entre os <li>
+		}
+		marcador := marcadores[nivel%len(marcadores)]
+		if ordenada {
+			marcador = fmt.Sprintf("%d. ", num)
+			num++
+		}
+		out = append(out, r.itemLista(li, marcador, width)...)
+	}
+	return out
+}
+
+// itemLista põe o marcador na primeira linha e alinha as demais logo depois dele
+func (r *renderer) itemLista(li *html.Node, marcador string, width int) []string {
+	recuo := lipgloss.Width(marcador)
+	interno := r.blocos(li, larguraUtil(width, recuo), true)
+	if len(interno) == 0 {
+		interno = []string{""}
+	}
+	for i, l := range interno {
+		if i == 0 {
+			interno[i] = marcador + l
+		} else {
+			interno[i] = strings.Repeat(" ", recuo) + l
+		}
+	}
+	return interno
+}
+
+func (r *renderer) tabela(n *html.Node, width int) []string {
+	var cabecalho []string
+	var linhas [][]string
+	colunas := 0
+
+	var visitar func(*html.Node)
+	visitar = func(n *html.Node) {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || c.DataAtom == atom.Table {
+				continue // Tabelas aninhadas entram como texto da célula
+			}
+			if c.DataAtom != atom.Tr {
+				visitar(c) // thead, tbody, tfoot
+				continue
+			}
+			var celulas []string
+			soTh := true
+			for td := c.FirstChild; td != nil; td = td.NextSibling {
+				if td.Type != html.ElementNode || (td.DataAtom != atom.Td && td.DataAtom != atom.Th) {
+					continue
+				}
+				soTh = soTh && td.DataAtom == atom.Th
+				celulas = append(celulas, strings.Join(r.quebrar(r.filhosInline(td, estilo{}), 0), " "))
+			}
+			colunas = max(colunas, len(celulas))
+			if cabecalho == nil && len(linhas) == 0 && soTh && len(celulas) > 0 {
+				cabecalho = celulas
+			} else if len(celulas) > 0 {
+				linhas = append(linhas, celulas)
+			}
+		}
+	}
+	visitar(n)
+	if colunas == 0 {
+		return nil
+	}
+
+	// O lipgloss exige o mesmo número de colunas em todas as linhas
+	completar := func(l []string) []string {
+		for len(l) < colunas {
+			l = append(l, "")
+		}
+		return l
+	}
+	t := table.New().Border(lipgloss.NormalBorder())
+	if r.styled {
+		t = t.BorderStyle(lipgloss.NewStyle().Foreground(lipgloss.Color("240")))
+	}
+	if cabecalho != nil {
+		t = t.Headers(completar(cabecalho)...)
+	}
+	for _, l := range linhas {
+		t = t.Row(completar(l)...)
+	}
+	if width > 0 {
+		t = t.Width(width)
+	}
+	return strings.Split(t.Render(), "\n")
+}
+
+// inline acumula os trechos de texto de n (e descendentes) com a formatação herdada
+func (r *renderer) inline(n *html.Node, acc []trecho, est estilo) []trecho {
+	switch n.Type {
+	case html.TextNode:
+		return append(acc, trecho{texto: n.Data, estilo: est})
+	case html.ElementNode:
+	default:
+		return acc
+	}
+
+	switch n.DataAtom {
+	case atom.Script, atom.Style, atom.Head, atom.Title:
+		return acc
+	case atom.Br:
+		return append(acc, trecho{quebra: true})
+	case atom.B, atom.Strong:
+		est.negrito = true
+	case atom.I, atom.Em, atom.Cite:
+		est.italico = true
+	case atom.U, atom.Ins:
+		est.sublinhado = true
+	case atom.S, atom.Strike, atom.Del:
+		est.riscado = true
+	case atom.Code, atom.Tt, atom.Kbd, atom.Samp:
+		est.codigo = true
+	case atom.Img:
+		return append(acc, trecho{texto: marcadorImagem(n), estilo: estilo{imagem: true}})
+	case atom.A:
+		return r.link(n, acc, est)
+	}
+
+	// Bloco dentro de contexto inline (ex: <p> dentro de <span>): vira quebra de linha
+	bloco := isBloco(n.DataAtom)
+	if bloco {
+		acc = append(acc, trecho{quebra: true})
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		acc = r.inline(c, acc, est)
+	}
+	if bloco {
+		acc = append(acc, trecho{quebra: true})
+	}
+	return acc
+}
+
+// link mostra o texto do link e guarda a URL como nota de rodapé numerada.
+// Links cujo texto já é a própria URL não ganham nota.
+func (r *renderer) link(n *html.Node, acc []trecho, est estilo) []trecho {
+	href := strings.TrimSpace(attr(n, "href"))
+	est.link = href != ""
+
+	inicio := len(acc)
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		acc = r.inline(c, acc, est)
+	}
+	if href == "" {
+		return acc
+	}
+
+	var texto strings.Builder
+	for _, t := range acc[inicio:] {
+		texto.WriteString(t.texto)
+	}
+	visivel := strings.TrimSpace(texto.String())
+	switch {
+	case visivel == "":
+		return append(acc, trecho{texto: href, estilo: est})
+	case visivel == href || "mailto:"+visivel == href:
+		return acc
+	}
+
+	r.links = append(r.links, href)
+	return append(acc, trecho{texto: fmt.Sprintf("[%d]", len(r.links)), estilo: estilo{nota: true}})
+}
+
+func (r *renderer) filhosInline(n *html.Node, est estilo) []trecho {
+	var acc []trecho
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		acc = r.inline(c, acc, est)
+	}
+	return acc
+}
+
+// quebrar junta os trechos em linhas de no máximo width colunas, colapsando espaços como o HTML faz
+func (r *renderer) quebrar(trechos []trecho, width int) []string {
+	var linhas []string
+	var atual strings.Builder
+	larguraAtual := 0
+	espacoPendente := false
+
+	fechar := func() {
+		linhas = append(linhas, atual.String())
+		atual.Reset()
+		larguraAtual = 0
+		espacoPendente = false
+	}
+
+	for _, t := range trechos {
+		if t.quebra {
+			if larguraAtual > 0 || len(linhas) > 0 {
+				fechar()
+			}
+			continue
+		}
+		if t.texto == "" {
+			continue
+		}
+		if isEspaco(t.texto[0]) && larguraAtual > 0 {
+			espacoPendente = true
+		}
+
+		palavras := strings.Fields(t.texto)
+		for i, p := range palavras {
+			if i > 0 {
+				espacoPendente = true
+			}
+			w := lipgloss.Width(p)
+			extra := 0
+			if espacoPendente && larguraAtual > 0 {
+				extra = 1
+			}
+			if width > 0 && larguraAtual > 0 && larguraAtual+extra+w > width {
+				fechar()
+				extra = 0
+			}
+			if extra == 1 {
+				atual.WriteString(" ")
+				larguraAtual++
+			}
+			espacoPendente = false
+
+			// Palavra maior que a linha inteira (URLs, hashes): corta à força
+			for width > 0 && larguraAtual+w > width {
+				cabe, resto := cortar(p, width-larguraAtual)
+				atual.WriteString(r.aplicar(t.estilo, cabe))
+				fechar()
+				p, w = resto, lipgloss.Width(resto)
+			}
+			atual.WriteString(r.aplicar(t.estilo, p))
+			larguraAtual += w
+		}
+		if len(palavras) > 0 && isEspaco(t.texto[len(t.texto)-1]) {
+			espacoPendente = true
+		}
+	}
+	if larguraAtual > 0 {
+		fechar()
+	}
+
+	// Remove linhas vazias nas pontas geradas por <br> no início/fim do parágrafo
+	for len(linhas) > 0 && strings.TrimSpace(linhas[len(linhas)-1]) == "" {
+		linhas = linhas[:len(linhas)-1]
+	}
+	return linhas
+}
+
+// aplicar devolve o texto com o estilo; sem estilos (PlainText) devolve o texto intacto
+func (r *renderer) aplicar(est estilo, texto string) string {
+	if !r.styled || est == (estilo{}) {
+		return texto
+	}
+	s, ok := r.cache[est]
+	if !ok {
+		s = lipgloss.NewStyle().
+			Bold(est.negrito || est.titulo).
+			Italic(est.italico).
+			Underline(est.sublinhado || est.link || est.titulo).
+			Strikethrough(est.riscado)
+		switch {
+		case est.codigo:
+			s = s.Foreground(lipgloss.Color("#E5C07B"))
+		case est.link:
+			s = s.Foreground(lipgloss.Color("#61AFEF"))
+		case est.imagem:
+			s = s.Foreground(lipgloss.Color("#C678DD"))
+		case est.nota:
+			s = s.Foreground(lipgloss.Color("241"))
+		case est.titulo:
+			s = s.Foreground(lipgloss.Color("#7D56F4"))
+		}
+		r.cache[est] = s
+	}
+	return s.Render(texto)
+}
+
+// marcadorImagem descreve a imagem inline: texto alternativo ou o documento do GLPI de onde ela vem
+func marcadorImagem(n *html.Node) string {
+	if alt := strings.TrimSpace(attr(n, "alt")); alt != "" {
+		return "[imagem: " + alt + "]"
+	}
+	if u, err := url.Parse(attr(n, "src")); err == nil {
+		if id := u.Query().Get("docid"); id != "" {
+			return "[imagem: documento #" + id + "]"
+		}
+		if nome := strings.TrimSpace(u.Path[strings.LastIndex(u.Path, "/")+1:]); nome != "" && u.Scheme != "data" {
+			return "[imagem: " + nome + "]"
+		}
+	}
+	return "[imagem]"
+}
+
+// cortar separa s em um prefixo de até w colunas e o resto
+func cortar(s string, w int) (string, string) {
+	larg := 0
+	for i, r := range s {
+		rw := lipgloss.Width(string(r))
+		if larg+rw > w && i > 0 {
+			return s[:i], s[i:]
+		}
+		larg += rw
+	}
+	return s, ""
+}
+
+func textoPuro(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+	if n.DataAtom == atom.Br {
+		return "\n"
+	}
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		sb.WriteString(textoPuro(c))
+	}
+	return sb.String()
+}
+
+func attr(n *html.Node, nome string) string {
+	for _, a := range n.Attr {
+		if a.Key == nome {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func temTexto(trechos []trecho) bool {
+	for _, t := range trechos {
+		if strings.TrimSpace(t.texto) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+func isEspaco(b byte) bool {
+	return b == ' ' || b == '\n' || b == '\t' || b == '\r' || b == '\f'
+}
+
+// larguraUtil desconta o recuo da largura disponível (0 continua significando "sem quebra")
+func larguraUtil(width, recuo int) int {
+	if width <= 0 {
+		return 0
+	}
+	return max(width-recuo, larguraMinima)
+}
+
+func isBloco(a atom.Atom) bool {
+	switch a {
+	case atom.P, atom.Div, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Ul, atom.Ol, atom.Li, atom.Blockquote, atom.Pre, atom.Table, atom.Hr,
+		atom.Section, atom.Article, atom.Header, atom.Footer, atom.Main, atom.Nav, atom.Aside,
+		atom.Center, atom.Address, atom.Figure, atom.Figcaption, atom.Form, atom.Fieldset,
+		atom.Details, atom.Summary, atom.Dl, atom.Dt, atom.Dd:
+		return true
+	}
+	return false
+}
+
+// espacado diz quais blocos ganham linha em branco antes e depois (div e afins ficam colados)
+func espacado(a atom.Atom) bool {
+	switch a {
+	case atom.P, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6,
+		atom.Ul, atom.Ol, atom.Blockquote, atom.Pre, atom.Table, atom.Hr:
+		return true
+	}
+	return false
+}
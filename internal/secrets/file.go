@@ -0,0 +1,243 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"filippo.io/age"
+)
+
+// fileStore guarda os segredos num JSON cifrado com age (scrypt + frase-senha).
+// A frase-senha vem de GLPI_PASSPHRASE ou é pedida no terminal uma vez por execução.
+type fileStore struct {
+	path string
+
+	mu    sync.Mutex
+	frase string
+	dados map[string]string // Conteúdo decifrado; nil = ainda não lido
+}
+
+var (
+	arquivosMu sync.Mutex
+	arquivos   = map[string]*fileStore{}
+)
+
+// ErrPromptDisabled indica que a frase-senha precisaria ser digitada, mas o terminal está ocupado
+var ErrPromptDisabled = errors.New("frase-senha necessária, mas o terminal está ocupado pela interface; defina GLPI_PASSPHRASE")
+
+var semPrompt atomic.Bool
+
+// AllowPrompt liga ou desliga a leitura da frase-senha no terminal. Com a TUI na tela ela fica
+// desligada: quem precisar da frase recebe ErrPromptDisabled em vez de disputar o terminal.
+func AllowPrompt(ok bool) { semPrompt.Store(!ok) }
+
+// Unlock deixa a frase-senha do arquivo cifrado em memória, pedindo-a agora se preciso (nova,
+// com confirmação, se o arquivo ainda não existir). Assim uma gravação posterior, como o
+// refresh token renovado com a TUI na tela, não depende do terminal. No chaveiro não faz nada.
+func Unlock(s Store) error {
+	f, ok := s.(*fileStore)
+	if !ok {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.carregar(); err != nil {
+		return err
+	}
+	if f.frase == "" {
+		frase, err := pedirFrase("Nova frase-senha para "+f.path+": ", true)
+		if err != nil {
+			return err
+		}
+		f.frase = frase
+	}
+	return nil
+}
+
+// File devolve o backend de arquivo cifrado em path. O mesmo path devolve sempre o mesmo
+// backend, para a frase-senha ser pedida uma vez só (ex.: ao trocar de perfil).
+func File(path string) Store {
+	arquivosMu.Lock()
+	defer arquivosMu.Unlock()
+	s, ok := arquivos[path]
+	if !ok {
+		s = &fileStore{path: path}
+		arquivos[path] = s
+	}
+	return s
+}
+
+// FilePath devolve onde o arquivo cifrado fica: GLPI_CREDENTIALS ou junto do config.yaml
+func FilePath() string {
+	if p := os.Getenv("GLPI_CREDENTIALS"); p != "" {
+		return p
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "credentials.age"
+	}
+	return filepath.Join(dir, "glpi-tui", "credentials.age")
+}
+
+func (s *fileStore) Name() string { return "arquivo cifrado " + s.path }
+
+func (s *fileStore) Get(key string) (string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.carregar(); err != nil {
+		return "", err
+	}
+	v, ok := s.dados[key]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return v, nil
+}
+
+func (s *fileStore) Set(key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.carregar(); err != nil {
+		return err
+	}
+	if s.dados[key] == value {
+		return nil
+	}
+	s.dados[key] = value
+	return s.salvar()
+}
+
+func (s *fileStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.carregar(); err != nil {
+		return err
+	}
+	if _, ok := s.dados[key]; !ok {
+		return nil
+	}
+	delete(s.dados, key)
+	return s.salvar()
+}
+
+// carregar decifra o arquivo na primeira leitura. Sem arquivo começa vazio: a frase-senha
+// só é pedida (com confirmação) quando o primeiro segredo for gravado.
+func (s *fileStore) carregar() error {
+	if s.dados != nil {
+		return nil
+	}
+	cifrado, err := os.ReadFile(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		s.dados = map[string]string{}
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao ler %s: %w", s.path, err)
+	}
+
+	frase := s.frase
+	if frase == "" {
+		if frase, err = pedirFrase("Frase-senha de "+s.path+": ", false); err != nil {
+			return err
+		}
+	}
+	id, err := age.NewScryptIdentity(frase)
+	if err != nil {
+		return err
+	}
+	r, err := age.Decrypt(bytes.NewReader(cifrado), id)
+	if err != nil {
+		return fmt.Errorf("não foi possível abrir %s (frase-senha incorreta?): %w", s.path, err)
+	}
+	texto, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("erro ao decifrar %s: %w", s.path, err)
+	}
+	dados := map[string]string{}
+	if err := json.Unmarshal(texto, &dados); err != nil {
+		return fmt.Errorf("%s corrompido: %w", s.path, err)
+	}
+	s.frase, s.dados = frase, dados
+	return nil
+}
+
+// salvar cifra o conteúdo num arquivo temporário e troca pelo original
+func (s *fileStore) salvar() error {
+	if s.frase == "" {
+		frase, err := pedirFrase("Nova frase-senha para "+s.path+": ", true)
+		if err != nil {
+			return err
+		}
+		s.frase = frase
+	}
+	rcpt, err := age.NewScryptRecipient(s.frase)
+	if err != nil {
+		return err
+	}
+	texto, err := json.Marshal(s.dados)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("erro ao criar a pasta de %s: %w", s.path, err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".credentials-*")
+	if err != nil {
+		return fmt.Errorf("erro ao gravar %s: %w", s.path, err)
+	}
+	defer os.Remove(tmp.Name()) // Sem efeito depois do Rename
+
+	w, err := age.Encrypt(tmp, rcpt)
+	if err == nil {
+		_, err = w.Write(texto)
+	}
+	if err == nil {
+		err = w.Close()
+	}
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return fmt.Errorf("erro ao gravar %s: %w", s.path, err)
+	}
+	if err := os.Rename(tmp.Name(), s.path); err != nil {
+		return fmt.Errorf("erro ao gravar %s: %w", s.path, err)
+	}
+	return nil
+}
+
+// pedirFrase lê a frase-senha de GLPI_PASSPHRASE ou do terminal; nova pede confirmação
+func pedirFrase(prompt string, nova bool) (string, error) {
+	if v := os.Getenv("GLPI_PASSPHRASE"); v != "" {
+		return v, nil
+	}
+	if semPrompt.Load() {
+		return "", ErrPromptDisabled
+	}
+	frase, err := PromptPassword(prompt)
+	if err != nil {
+		return "", err
+	}
+	if frase == "" {
+		return "", fmt.Errorf("frase-senha vazia")
+	}
+	if nova {
+		conf, err := PromptPassword("Repita a frase-senha: ")
+		if err != nil {
+			return "", err
+		}
+		if conf != frase {
+			return "", fmt.Errorf("as frases-senha não conferem")
+		}
+	}
+	return frase, nil
+}
@@ -0,0 +1,44 @@
+package secrets
+
+import (
+	"errors"
+
+	"github.com/zalando/go-keyring"
+)
+
+// servicoKeyring agrupa os segredos do programa no chaveiro
+const servicoKeyring = "glpi-tui"
+
+type keyringStore struct{}
+
+// Keyring devolve o backend do chaveiro do sistema
+func Keyring() Store { return keyringStore{} }
+
+// keyringDisponivel testa o chaveiro com uma leitura: sem Secret Service a leitura falha
+// com erro de conexão em vez de "não encontrado"
+func keyringDisponivel() bool {
+	_, err := keyring.Get(servicoKeyring, "teste-disponibilidade")
+	return err == nil || errors.Is(err, keyring.ErrNotFound)
+}
+
+func (keyringStore) Get(key string) (string, error) {
+	v, err := keyring.Get(servicoKeyring, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	}
+	return v, err
+}
+
+func (keyringStore) Set(key, value string) error {
+	return keyring.Set(servicoKeyring, key, value)
+}
+
+func (keyringStore) Delete(key string) error {
+	err := keyring.Delete(servicoKeyring, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (keyringStore) Name() string { return "chaveiro do sistema" }
@@ -0,0 +1,99 @@
+// Package secrets guarda senha, client_secret e refresh token fora do .env: no chaveiro do
+// sistema (Secret Service no Linux, Keychain no macOS, Credential Manager no Windows) ou,
+// onde não houver chaveiro, num arquivo cifrado com age e uma frase-senha.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/charmbracelet/x/term"
+)
+
+// Tipos de segredo guardados por conta
+const (
+	Password     = "password"
+	ClientSecret = "client_secret"
+	RefreshToken = "refresh_token"
+)
+
+// Backends aceitos em "secrets" no perfil ou em GLPI_SECRETS
+const (
+	BackendAuto    = "auto" // Chaveiro se disponível, senão arquivo cifrado
+	BackendKeyring = "keyring"
+	BackendFile    = "file"
+)
+
+// ErrNotFound indica que o segredo não está guardado
+var ErrNotFound = errors.New("segredo não encontrado")
+
+// Store é um lugar onde os segredos ficam guardados
+type Store interface {
+	Get(key string) (string, error) // ErrNotFound se não existir
+	Set(key, value string) error
+	Delete(key string) error
+	Name() string // Para mensagens ("chaveiro do sistema", "arquivo cifrado ...")
+}
+
+// Key identifica um segredo de uma conta: o mesmo usuário em servidores diferentes tem segredos diferentes
+func Key(baseURL, user, kind string) string {
+	return user + "@" + strings.TrimRight(baseURL, "/") + ":" + kind
+}
+
+// Open abre o backend pedido; "auto" (ou vazio) usa o chaveiro quando ele responde
+func Open(backend string) (Store, error) {
+	switch backend {
+	case "", BackendAuto:
+		if keyringDisponivel() {
+			return Keyring(), nil
+		}
+		return File(FilePath()), nil
+	case BackendKeyring:
+		if !keyringDisponivel() {
+			return nil, fmt.Errorf("chaveiro do sistema indisponível (no Linux é preciso o Secret Service, ex.: gnome-keyring); use secrets: file")
+		}
+		return Keyring(), nil
+	case BackendFile:
+		return File(FilePath()), nil
+	}
+	return nil, fmt.Errorf("backend de segredos desconhecido %q: use auto, keyring ou file", backend)
+}
+
+// PromptPassword pede um segredo no terminal sem ecoar o que é digitado.
+// Fora de um terminal lê uma linha do stdin (ex.: echo "$SENHA" | glpi-tui auth login).
+func PromptPassword(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	if !term.IsTerminal(os.Stdin.Fd()) {
+		return lerLinha()
+	}
+	b, err := term.ReadPassword(os.Stdin.Fd())
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("erro ao ler do terminal: %w", err)
+	}
+	return string(b), nil
+}
+
+// lerLinha lê do stdin byte a byte até a quebra de linha, sem bufferizar o resto da entrada
+func lerLinha() (string, error) {
+	var sb strings.Builder
+	b := make([]byte, 1)
+	for {
+		n, err := os.Stdin.Read(b)
+		if n == 1 {
+			if b[0] == '\n' {
+				break
+			}
+			sb.WriteByte(b[0])
+		}
+		if err != nil {
+			if sb.Len() > 0 {
+				break
+			}
+			return "", fmt.Errorf("erro ao ler do stdin: %w", err)
+		}
+	}
+	return strings.TrimRight(sb.String(), "\r"), nil
+}
@@ -0,0 +1,156 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// severidade define cor, ícone e tempo na tela de um aviso da barra de status
+type severidade int
+
+const (
+	sevInfo severidade = iota
+	sevSucesso
+	sevAlerta
+	sevErro
+)
+
+// maxLogErros limita o histórico de erros guardado na sessão
+const maxLogErros = 200
+
+// aviso é uma notificação da barra de status; alertas e erros também vão para o log
+type aviso struct {
+	id     int
+	sev    severidade
+	texto  string
+	quando time.Time
+}
+
+// avisoExpiradoMsg tira o aviso da barra de status quando o tempo dele acaba
+type avisoExpiradoMsg struct{ id int }
+
+func (s severidade) duracao() time.Duration {
+	switch s {
+	case sevErro:
+		return 10 * time.Second
+	case sevAlerta:
+		return 6 * time.Second
+	}
+	return 4 * time.Second
+}
+
+func (s severidade) icone() string {
+	switch s {
+	case sevSucesso:
+		return "✔"
+	case sevAlerta:
+		return "⚠"
+	case sevErro:
+		return "✖"
+	}
+	return "ℹ"
+}
+
+func (s severidade) cor() lipgloss.Color {
+	switch s {
+	case sevSucesso:
+		return lipgloss.Color("#04B575")
+	case sevAlerta:
+		return lipgloss.Color("208")
+	case sevErro:
+		return lipgloss.Color("196")
+	}
+	return lipgloss.Color("69")
+}
+
+// notificar mostra o aviso na barra de status e agenda a saída dele
+func (m *model) notificar(sev severidade, texto string) tea.Cmd {
+	m.avisoSeq++
+	a := aviso{id: m.avisoSeq, sev: sev, texto: texto, quando: time.Now()}
+	m.avisos = append(m.avisos, a)
+	if sev >= sevAlerta {
+		m.logErros = append(m.logErros, a)
+		if len(m.logErros) > maxLogErros {
+			m.logErros = m.logErros[len(m.logErros)-maxLogErros:]
+		}
+	}
+	return tea.Tick(sev.duracao(), func(time.Time) tea.Msg { return avisoExpiradoMsg{id: a.id} })
+}
+
+// notificarErro registra uma falha recuperável: o programa segue funcionando
+func (m *model) notificarErro(err error) tea.Cmd {
+	return m.notificar(sevErro, err.Error())
+}
+
+// expirarAviso tira da barra o aviso cujo tempo acabou
+func (m *model) expirarAviso(id int) {
+	for i, a := range m.avisos {
+		if a.id == id {
+			m.avisos = append(m.avisos[:i:i], m.avisos[i+1:]...)
+			return
+		}
+	}
+}
+
+// renderBarraStatus mostra o aviso mais recente ou, sem avisos, o atalho para o log de erros
+func (m model) renderBarraStatus() string {
+	largura := max(m.width, 20)
+	if n := len(m.avisos); n > 0 {
+		a := m.avisos[n-1]
+		texto := a.sev.icone() + " " + primeiraLinha(a.texto)
+		if n > 1 {
+			texto = fmt.Sprintf("%s (+%d)", texto, n-1)
+		}
+		return lipgloss.NewStyle().Foreground(a.sev.cor()).Bold(a.sev == sevErro).MaxWidth(largura).Render(texto)
+	}
+	if len(m.logErros) > 0 {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("240")).MaxWidth(largura).
+			Render(fmt.Sprintf("[L] Log de erros (%d)", len(m.logErros)))
+	}
+	return ""
+}
+
+// abrirLog mostra o painel com os erros da sessão, do mais recente para o mais antigo
+func (m *model) abrirLog() {
+	m.vendoLog = true
+	m.logViewport = viewport.New(max(m.width, 20), max(m.height-3, 5))
+	m.logViewport.SetContent(renderLogErros(m.logErros, m.logViewport.Width))
+}
+
+func renderLogErros(log []aviso, width int) string {
+	if len(log) == 0 {
+		return lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render("Nenhum erro nesta sessão.")
+	}
+	horaStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	textoStyle := lipgloss.NewStyle().Width(max(20, width-2)).PaddingLeft(2)
+
+	var sb strings.Builder
+	for i := len(log) - 1; i >= 0; i-- {
+		a := log[i]
+		sb.WriteString(horaStyle.Render(a.quando.Format("15:04:05")) + " " +
+			lipgloss.NewStyle().Foreground(a.sev.cor()).Render(a.sev.icone()) + "\n")
+		sb.WriteString(textoStyle.Render(a.texto) + "\n")
+	}
+	return sb.String()
+}
+
+// viewLog desenha o painel do log de erros
+func (m model) viewLog() string {
+	titulo := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("196")).Padding(0, 1).
+		Render(fmt.Sprintf("Log de erros (%d)", len(m.logErros)))
+	ajuda := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("↑/↓ Rolar • [c] Limpar • [Esc/L] Fechar")
+	return titulo + "\n" + m.logViewport.View() + "\n" + ajuda
+}
+
+// primeiraLinha evita que erros com corpo de resposta (várias linhas) quebrem a barra de status
+func primeiraLinha(s string) string {
+	if i := strings.IndexByte(s, '\n'); i >= 0 {
+		return s[:i] + " …"
+	}
+	return s
+}
@@ -0,0 +1,265 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"glpi-tui/internal/api"
+	"glpi-tui/internal/domain"
+
+	"github.com/charmbracelet/bubbles/filepicker"
+	"github.com/charmbracelet/bubbles/progress"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Etapas do painel de documentos
+type modoDocumentos int
+
+const (
+	docsLista   modoDocumentos = iota // Navegando pelos anexos (Enter baixa)
+	docsArquivo                       // Escolhendo o arquivo local a enviar
+	docsDestino                       // Escolhendo onde anexar: chamado ou um acompanhamento
+)
+
+// transferenciaMsg carrega o andamento de um download/upload e o canal de onde vem o próximo aviso
+type transferenciaMsg struct {
+	ch           <-chan tea.Msg
+	feito, total int64
+}
+
+// transferenciaConcluidaMsg encerra a transferência (caminho só vem preenchido no download)
+type transferenciaConcluidaMsg struct {
+	ticketID int
+	upload   bool
+	caminho  string
+	err      error
+}
+
+// painelDocumentos lista os anexos do chamado, baixa e envia arquivos (teclas "d" e "f" nos detalhes)
+type painelDocumentos struct {
+	modo    modoDocumentos
+	cursor  int
+	seletor filepicker.Model
+	arquivo string // Arquivo local escolhido para upload
+	destino int    // 0 = chamado; i > 0 = i-ésimo acompanhamento
+
+	transferindo bool
+	descricao    string // "Baixando x" / "Enviando y"
+	feito, total int64
+	barra        progress.Model
+
+	aviso string // Resultado da última operação
+	erro  string
+}
+
+func novoPainelDocumentos(largura, altura int) painelDocumentos {
+	fp := filepicker.New()
+	fp.AutoHeight = false
+	fp.Height = max(altura/3, 5)
+	fp.ShowPermissions = false
+	if dir, err := os.Getwd(); err == nil {
+		fp.CurrentDirectory = dir
+	}
+
+	return painelDocumentos{
+		seletor: fp,
+		barra:   progress.New(progress.WithDefaultGradient(), progress.WithWidth(max(largura-10, 20))),
+	}
+}
+
+// abrirSeletor passa para a escolha do arquivo local (lê o diretório atual)
+func (p painelDocumentos) abrirSeletor() (painelDocumentos, tea.Cmd) {
+	p.modo = docsArquivo
+	p.erro = ""
+	return p, p.seletor.Init()
+}
+
+// update trata a navegação do painel. Downloads e uploads são disparados pelo model,
+// que conhece o client e o chamado; aqui só se devolve a intenção.
+func (p painelDocumentos) update(msg tea.Msg, docs []domain.TicketDocument, followups []domain.TicketFollowup) (painelDocumentos, tea.Cmd, *domain.TicketDocument, bool) {
+	switch p.modo {
+	case docsLista:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "up", "k":
+				if p.cursor > 0 {
+					p.cursor--
+				}
+			case "down", "j":
+				if p.cursor < len(docs)-1 {
+					p.cursor++
+				}
+			case "enter":
+				if p.cursor < len(docs) {
+					d := docs[p.cursor]
+					return p, nil, &d, false
+				}
+			case "f":
+				var cmd tea.Cmd
+				p, cmd = p.abrirSeletor()
+				return p, cmd, nil, false
+			}
+		}
+
+	case docsArquivo:
+		if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "esc" {
+			p.modo = docsLista
+			return p, nil, nil, false
+		}
+		var cmd tea.Cmd
+		p.seletor, cmd = p.seletor.Update(msg)
+		if ok, path := p.seletor.DidSelectFile(msg); ok {
+			p.arquivo = path
+			p.destino = 0
+			p.modo = docsDestino
+		}
+		return p, cmd, nil, false
+
+	case docsDestino:
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "esc":
+				p.modo = docsArquivo
+			case "left", "h":
+				p.destino = ciclar(p.destino-1, -1, len(followups)) + 1
+			case "right", "l":
+				p.destino = ciclar(p.destino-1, 1, len(followups)) + 1
+			case "enter":
+				return p, nil, nil, true
+			}
+		}
+	}
+	return p, nil, nil, false
+}
+
+// followupDestino devolve o ID do acompanhamento escolhido (0 = anexar ao chamado)
+func (p painelDocumentos) followupDestino(followups []domain.TicketFollowup) int {
+	if p.destino == 0 || p.destino > len(followups) {
+		return 0
+	}
+	return followups[p.destino-1].ID
+}
+
+func (p painelDocumentos) view(docs []domain.TicketDocument, followups []domain.TicketFollowup, pasta string) string {
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("69")).
+		Padding(0, 1).
+		MarginTop(1)
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	erroStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+
+	var sb strings.Builder
+	var ajuda string
+
+	switch {
+	case p.transferindo:
+		sb.WriteString(p.descricao + "\n")
+		pct := 0.0
+		if p.total > 0 {
+			pct = float64(p.feito) / float64(p.total)
+			sb.WriteString(p.barra.ViewAs(pct) + "\n")
+			sb.WriteString(hint.Render(fmt.Sprintf("%s de %s", formatarBytes(p.feito), formatarBytes(p.total))))
+		} else {
+			// Servidor não informou o tamanho: mostra só o volume já transferido
+			sb.WriteString(hint.Render(formatarBytes(p.feito) + " transferidos"))
+		}
+		ajuda = "Aguarde o fim da transferência • Esc nos detalhes cancela"
+
+	case p.modo == docsArquivo:
+		sb.WriteString("📎 Anexar arquivo — " + hint.Render(p.seletor.CurrentDirectory) + "\n")
+		sb.WriteString(p.seletor.View())
+		ajuda = "↑/↓: Navegar • →/Enter: Abrir/Escolher • ←: Voltar pasta • Esc: Cancelar"
+
+	case p.modo == docsDestino:
+		sb.WriteString(fmt.Sprintf("📎 %s\n", filepath.Base(p.arquivo)))
+		alvo := "Chamado"
+		if id := p.followupDestino(followups); id != 0 {
+			f := followups[p.destino-1]
+			alvo = fmt.Sprintf("Acompanhamento #%d de %s (%s)", id, f.User.Name, f.GetFormattedDate())
+		}
+		sb.WriteString("Anexar em: " + lipgloss.NewStyle().Bold(true).Render("‹ "+alvo+" ›"))
+		ajuda = "←/→: Trocar destino • Enter: Enviar • Esc: Outro arquivo"
+
+	default:
+		sb.WriteString(fmt.Sprintf("📎 Documentos (%d)\n", len(docs)))
+		if len(docs) == 0 {
+			sb.WriteString(hint.Render("Nenhum documento anexado."))
+		}
+		for i, d := range docs {
+			line := fmt.Sprintf("%s  %s", d.Filename, hint.Render(fmt.Sprintf("%s • %s", d.User.Name, domain.FormatDate(d.Date))))
+			if i == p.cursor {
+				sb.WriteString(lipgloss.NewStyle().Bold(true).Render("› ") + line + "\n")
+			} else {
+				sb.WriteString("  " + line + "\n")
+			}
+		}
+		ajuda = "↑/↓: Escolher • Enter: Baixar para " + pasta + " • [f] Anexar arquivo • Esc: Fechar"
+	}
+
+	if p.erro != "" {
+		sb.WriteString("\n" + erroStyle.Render("⚠ "+p.erro))
+	} else if p.aviso != "" && !p.transferindo {
+		sb.WriteString("\n" + lipgloss.NewStyle().Foreground(lipgloss.Color("42")).Render("✔ "+p.aviso))
+	}
+
+	return boxStyle.Render(strings.TrimRight(sb.String(), "\n")) + "\n" + hint.Render(ajuda)
+}
+
+// iniciarTransferencia roda a transferência numa goroutine e repassa o andamento à UI por um canal.
+// Avisos de progresso são descartados se a UI ainda não consumiu o anterior, para não acumular fila.
+func iniciarTransferencia(run func(progress api.ProgressFunc) transferenciaConcluidaMsg) tea.Cmd {
+	ch := make(chan tea.Msg, 1)
+	go func() {
+		fim := run(func(done, total int64) {
+			select {
+			case ch <- transferenciaMsg{ch: ch, feito: done, total: total}:
+			default:
+			}
+		})
+		ch <- fim
+		close(ch)
+	}()
+	return esperarTransferencia(ch)
+}
+
+// esperarTransferencia aguarda o próximo aviso da transferência em andamento
+func esperarTransferencia(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
+	}
+}
+
+// downloadDocumentCmd baixa o documento para a pasta configurada
+func downloadDocumentCmd(ctx context.Context, c api.Backend, ticketID int, doc domain.TicketDocument, pasta string) tea.Cmd {
+	return iniciarTransferencia(func(progress api.ProgressFunc) transferenciaConcluidaMsg {
+		path, err := c.DownloadDocumentToDir(ctx, doc, pasta, progress)
+		return transferenciaConcluidaMsg{ticketID: ticketID, caminho: path, err: err}
+	})
+}
+
+// uploadDocumentCmd envia o arquivo local e o anexa ao chamado ou ao acompanhamento
+func uploadDocumentCmd(ctx context.Context, c api.Backend, in api.UploadInput) tea.Cmd {
+	return iniciarTransferencia(func(progress api.ProgressFunc) transferenciaConcluidaMsg {
+		err := c.UploadDocumentContext(ctx, in, progress)
+		return transferenciaConcluidaMsg{ticketID: in.TicketID, upload: true, err: err}
+	})
+}
+
+// formatarBytes mostra tamanhos em B/KB/MB/GB
+func formatarBytes(n int64) string {
+	const unidade = 1024
+	if n < unidade {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unidade), 0
+	for v := n / unidade; v >= unidade; v /= unidade {
+		div *= unidade
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGT"[exp])
+}
@@ -0,0 +1,66 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// editorFinishedMsg traz o texto salvo no editor externo
+type editorFinishedMsg struct {
+	conteudo string
+	err      error
+}
+
+// editorExterno resolve o editor do usuário ($VISUAL, depois $EDITOR), com argumentos ("code -w")
+func editorExterno() []string {
+	for _, v := range []string{"VISUAL", "EDITOR"} {
+		if args := strings.Fields(os.Getenv(v)); len(args) > 0 {
+			return args
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+// abrirEditorCmd suspende o Bubble Tea e abre o texto num arquivo .md temporário no editor do usuário.
+// Quando o editor fecha, o conteúdo volta como editorFinishedMsg e o arquivo é apagado.
+func abrirEditorCmd(inicial string) tea.Cmd {
+	f, err := os.CreateTemp("", "glpi-resposta-*.md")
+	if err != nil {
+		return func() tea.Msg {
+			return editorFinishedMsg{err: fmt.Errorf("erro ao criar rascunho: %w", err)}
+		}
+	}
+	path := f.Name()
+	_, err = f.WriteString(inicial)
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(path)
+		return func() tea.Msg {
+			return editorFinishedMsg{err: fmt.Errorf("erro ao gravar rascunho: %w", err)}
+		}
+	}
+
+	args := editorExterno()
+	cmd := exec.Command(args[0], append(args[1:], path)...)
+	return tea.ExecProcess(cmd, func(err error) tea.Msg {
+		defer os.Remove(path)
+		if err != nil {
+			return editorFinishedMsg{err: fmt.Errorf("editor %s terminou com erro: %w", args[0], err)}
+		}
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return editorFinishedMsg{err: fmt.Errorf("erro ao ler rascunho: %w", err)}
+		}
+		return editorFinishedMsg{conteudo: strings.TrimRight(string(b), "\n")}
+	})
+}
@@ -0,0 +1,358 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"glpi-tui/internal/api"
+	"glpi-tui/internal/domain"
+
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// statusAbertos são os status considerados "em aberto" nas filas de trabalho
+var statusAbertos = []int{domain.StatusNew, domain.StatusAssign, domain.StatusPlanned, domain.StatusPending}
+
+// fila é uma aba da lista principal, com consulta, cursor, paginação e contagem de novidades próprios
+type fila struct {
+	nome   string
+	escopo func(c api.Backend) (api.TicketFilter, error)
+
+	list           list.Model
+	totalChamados  int  // Total informado pelo Content-Range (-1 = desconhecido)
+	temMais        bool // Ainda há páginas no servidor
+	carregandoMais bool // Uma página extra está a caminho
+	carregada      bool // Já buscou a primeira página com a consulta atual
+	geracao        int  // Incrementa a cada nova consulta da fila
+	aviso          string
+
+	// Controle de novidades: o que já foi visto nesta fila e o que mudou desde então
+	conhecidos  map[int]string // ID -> date_mod visto
+	maisRecente string         // Maior date_mod visto na primeira carga
+	temBase     bool
+	naoLidos    map[int]bool
+	doCache     bool // Itens vindos do cache local, ainda não confirmados pelo servidor
+}
+
+// novasFilas cria as abas na ordem das teclas 1–4
+func novasFilas() []fila {
+	defs := []struct {
+		nome   string
+		escopo func(c api.Backend) (api.TicketFilter, error)
+	}{
+		{"Meus chamados", func(c api.Backend) (api.TicketFilter, error) {
+			if c.MyUserID() == 0 {
+				return api.TicketFilter{}, fmt.Errorf("perfil do usuário ainda não carregado")
+			}
+			return api.TicketFilter{AssignedTo: c.MyUserID(), Statuses: statusAbertos}, nil
+		}},
+		{"Não atribuídos", func(c api.Backend) (api.TicketFilter, error) {
+			// Em aberto e sem técnico nem grupo; na v2 o client filtra a equipe do seu lado
+			return api.TicketFilter{Unassigned: true, Statuses: statusAbertos}, nil
+		}},
+		{"Meus grupos", func(c api.Backend) (api.TicketFilter, error) {
+			if len(c.MyGroupIDs()) == 0 {
+				return api.TicketFilter{}, fmt.Errorf("você não pertence a nenhum grupo")
+			}
+			return api.TicketFilter{AssignedGroups: c.MyGroupIDs(), Statuses: statusAbertos}, nil
+		}},
+		{"Todos", func(c api.Backend) (api.TicketFilter, error) {
+			return api.TicketFilter{}, nil
+		}},
+	}
+
+	filas := make([]fila, len(defs))
+	for i, d := range defs {
+		l := list.New([]list.Item{}, novoDelegateChamados(), 0, 0)
+		l.Title = d.nome
+		l.SetShowHelp(false)
+		l.SetFilteringEnabled(false) // A busca é feita no servidor pela barra de filtro
+
+		filas[i] = fila{
+			nome:          d.nome,
+			escopo:        d.escopo,
+			list:          l,
+			totalChamados: -1,
+			conhecidos:    map[int]string{},
+			naoLidos:      map[int]bool{},
+		}
+	}
+	return filas
+}
+
+// itemChamado envolve o chamado na lista para exibir marcadores da fila
+type itemChamado struct {
+	domain.Chamado
+	novo      bool
+	pendente  bool // Há escrita na fila de envio
+	falhou    bool // Alguma escrita foi recusada pelo servidor
+	respostas int  // Acompanhamentos novos desde a última vez que o chamado foi aberto
+	naoLido   bool // Nunca aberto ou alterado desde a última visita (persistido no cache)
+}
+
+// delegateChamados é o delegate padrão da lista com destaque para chamados não lidos
+type delegateChamados struct {
+	list.DefaultDelegate
+}
+
+func novoDelegateChamados() delegateChamados {
+	return delegateChamados{DefaultDelegate: list.NewDefaultDelegate()}
+}
+
+// Render desenha o item com o título em negrito e o marcador ◆ quando não lido
+func (d delegateChamados) Render(w io.Writer, m list.Model, index int, item list.Item) {
+	ic, ok := item.(itemChamado)
+	if !ok || !ic.naoLido {
+		d.DefaultDelegate.Render(w, m, index, item)
+		return
+	}
+	dd := d.DefaultDelegate
+	dd.Styles.NormalTitle = dd.Styles.NormalTitle.Bold(true).Foreground(lipgloss.AdaptiveColor{Light: "#1a1a1a", Dark: "#FFFFFF"})
+	dd.Styles.SelectedTitle = dd.Styles.SelectedTitle.Bold(true)
+	ic.Chamado.Name = "◆ " + ic.Chamado.Name
+	dd.Render(w, m, index, ic)
+}
+
+func (i itemChamado) Title() string {
+	title := i.Chamado.Title()
+	if i.novo {
+		title = "● " + title
+	}
+	if i.respostas > 0 {
+		title += fmt.Sprintf(" ✉ %d nova(s)", i.respostas)
+	}
+	switch {
+	case i.falhou:
+		title += " ✖ envio falhou"
+	case i.pendente:
+		title += " ⏳ envio pendente"
+	}
+	return title
+}
+
+// chamadoSelecionadoNaLista devolve o chamado sob o cursor da fila
+func (f *fila) chamadoSelecionadoNaLista() (domain.Chamado, bool) {
+	i, ok := f.list.SelectedItem().(itemChamado)
+	return i.Chamado, ok
+}
+
+// consulta monta a query da fila a partir do offset informado
+func (f *fila) consulta(c api.Backend, filtro api.TicketFilter, start int) (api.TicketQuery, error) {
+	escopo, err := f.escopo(c)
+	if err != nil {
+		return api.TicketQuery{}, err
+	}
+	return api.TicketQuery{Start: start, Limit: api.DefaultPageSize, Scope: escopo, Filter: filtro}, nil
+}
+
+// recarregar descarta as páginas carregadas e busca a primeira página da consulta atual
+func (f *fila) recarregar(c api.Backend, filtro api.TicketFilter, idx int) tea.Cmd {
+	f.geracao++
+	f.carregada = true
+	f.aviso = ""
+
+	q, err := f.consulta(c, filtro, 0)
+	if err != nil {
+		f.aviso = err.Error()
+		f.carregandoMais = false
+		f.temMais = false
+		f.totalChamados = -1
+		f.atualizarTitulo(filtro)
+		return f.list.SetItems(nil)
+	}
+
+	f.carregandoMais = true
+	f.list.ResetSelected()
+	f.atualizarTitulo(filtro)
+	return fetchTicketsCmd(c, q, idx, f.geracao)
+}
+
+// carregarMaisSePreciso dispara a busca da próxima página quando faltam poucos itens abaixo do cursor
+func (f *fila) carregarMaisSePreciso(c api.Backend, filtro api.TicketFilter, idx int) tea.Cmd {
+	if f.carregandoMais || !f.temMais {
+		return nil
+	}
+	if f.list.Index() < len(f.list.Items())-loadMoreThreshold {
+		return nil
+	}
+	q, err := f.consulta(c, filtro, len(f.list.Items()))
+	if err != nil {
+		return nil
+	}
+	f.carregandoMais = true
+	f.atualizarTitulo(filtro)
+	return fetchTicketsCmd(c, q, idx, f.geracao)
+}
+
+// aplicarPagina incorpora uma página recebida do servidor, marcando o que é novidade
+func (f *fila) aplicarPagina(page api.TicketPage, filtro api.TicketFilter) tea.Cmd {
+	f.carregandoMais = false
+	f.doCache = false
+	primeiraCarga := !f.temBase
+
+	for _, t := range page.Tickets {
+		visto, conhecido := f.conhecidos[t.ID]
+		switch {
+		case primeiraCarga:
+			if t.DateMod > f.maisRecente {
+				f.maisRecente = t.DateMod
+			}
+		case conhecido && visto != t.DateMod:
+			f.naoLidos[t.ID] = true
+		case !conhecido && t.DateMod > f.maisRecente:
+			f.naoLidos[t.ID] = true
+		}
+		f.conhecidos[t.ID] = t.DateMod
+	}
+	if page.Start == 0 {
+		f.temBase = true
+	}
+
+	// A primeira página substitui a lista; as seguintes são anexadas ao final
+	var items []list.Item
+	if page.Start > 0 {
+		items = f.list.Items()
+	}
+	for _, t := range page.Tickets {
+		items = append(items, itemChamado{Chamado: t, novo: f.naoLidos[t.ID]})
+	}
+
+	f.totalChamados = page.Total
+	f.temMais = page.HasMore()
+	cmd := f.list.SetItems(items)
+	f.atualizarTitulo(filtro)
+	return cmd
+}
+
+// mesclarPagina incorpora a primeira página buscada em background sem perder as páginas já
+// carregadas nem a posição do cursor. Devolve quantos chamados passaram a ser novidade.
+func (f *fila) mesclarPagina(page api.TicketPage, filtro api.TicketFilter) (tea.Cmd, int) {
+	if !f.temBase {
+		return f.aplicarPagina(page, filtro), 0
+	}
+	f.doCache = false
+
+	novos := 0
+	naPagina := map[int]bool{}
+	for _, t := range page.Tickets {
+		visto, conhecido := f.conhecidos[t.ID]
+		if (conhecido && visto != t.DateMod) || (!conhecido && t.DateMod > f.maisRecente) {
+			if !f.naoLidos[t.ID] {
+				novos++
+			}
+			f.naoLidos[t.ID] = true
+		}
+		f.conhecidos[t.ID] = t.DateMod
+		f.maisRecente = max(f.maisRecente, t.DateMod)
+		naPagina[t.ID] = true
+	}
+
+	// A página nova fica no topo; das antigas só sobra o que estava além da primeira página.
+	// O que sumiu do topo (fechado, reatribuído...) saiu da fila.
+	selecionado, temSelecionado := f.chamadoSelecionadoNaLista()
+	items := make([]list.Item, 0, len(f.list.Items()))
+	for _, t := range page.Tickets {
+		items = append(items, itemChamado{Chamado: t, novo: f.naoLidos[t.ID]})
+	}
+	for i, it := range f.list.Items() {
+		if ic, ok := it.(itemChamado); ok && i >= api.DefaultPageSize && !naPagina[ic.ID] {
+			items = append(items, ic)
+		}
+	}
+
+	f.totalChamados = page.Total
+	if len(items) <= len(page.Tickets) {
+		f.temMais = page.HasMore()
+	}
+	cmd := f.list.SetItems(items)
+	if temSelecionado {
+		for i, it := range items {
+			if it.(itemChamado).ID == selecionado.ID {
+				f.list.Select(i)
+				break
+			}
+		}
+	}
+	f.atualizarTitulo(filtro)
+	return cmd, novos
+}
+
+// aplicarCache mostra a primeira página salva na última sessão enquanto o servidor não responde.
+// Os chamados do cache viram a base de comparação: o que mudou desde então aparece como novidade.
+func (f *fila) aplicarCache(tickets []domain.Chamado, total int) {
+	items := make([]list.Item, len(tickets))
+	for i, t := range tickets {
+		items[i] = itemChamado{Chamado: t}
+		f.conhecidos[t.ID] = t.DateMod
+		if t.DateMod > f.maisRecente {
+			f.maisRecente = t.DateMod
+		}
+	}
+	f.temBase = len(tickets) > 0
+	f.totalChamados = total
+	f.doCache = true
+	f.list.SetItems(items)
+	f.atualizarTitulo(api.TicketFilter{})
+}
+
+// marcarLido tira o destaque de novidade do chamado nesta fila
+func (f *fila) marcarLido(id int) {
+	if !f.naoLidos[id] {
+		return
+	}
+	delete(f.naoLidos, id)
+	for i, it := range f.list.Items() {
+		if ic, ok := it.(itemChamado); ok && ic.ID == id {
+			ic.novo = false
+			f.list.SetItem(i, ic)
+		}
+	}
+}
+
+// atualizarTitulo mostra quantos chamados já foram carregados do total ("20 de 1432")
+func (f *fila) atualizarTitulo(filtro api.TicketFilter) {
+	title := f.nome
+	loaded := len(f.list.Items())
+	switch {
+	case f.aviso != "":
+		title = fmt.Sprintf("%s — %s", title, f.aviso)
+	case f.totalChamados >= 0:
+		title = fmt.Sprintf("%s (%d de %d)", title, loaded, f.totalChamados)
+	case loaded > 0:
+		title = fmt.Sprintf("%s (%d)", title, loaded)
+	}
+	if !filtro.IsZero() {
+		title += " [filtrado]"
+	}
+	if f.doCache {
+		title += " 💾"
+	}
+	if f.carregandoMais {
+		title += " ⏳"
+	}
+	f.list.Title = title
+}
+
+// renderAbas desenha a barra de abas com o contador de novidades de cada fila
+func renderAbas(filas []fila, ativa int) string {
+	ativo := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#7D56F4")).Padding(0, 1)
+	inativo := lipgloss.NewStyle().Foreground(lipgloss.Color("245")).Padding(0, 1)
+	contador := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#04B575"))
+
+	abas := make([]string, len(filas))
+	for i, f := range filas {
+		label := fmt.Sprintf("%d %s", i+1, f.nome)
+		if n := len(f.naoLidos); n > 0 {
+			label += " " + contador.Render(fmt.Sprintf("(%d)", n))
+		}
+		if i == ativa {
+			abas[i] = ativo.Render(label)
+		} else {
+			abas[i] = inativo.Render(label)
+		}
+	}
+	return strings.Join(abas, "│")
+}
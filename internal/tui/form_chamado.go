@@ -0,0 +1,240 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"glpi-tui/internal/api"
+	"glpi-tui/internal/domain"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// campoForm identifica cada campo do formulário de abertura, na ordem de navegação
+type campoForm int
+
+const (
+	campoTitulo campoForm = iota
+	campoDescricao
+	campoTipo
+	campoUrgencia
+	campoCategoria
+	campoEntidade
+	campoRequerentes
+	campoObservadores
+	totalCampos
+)
+
+// formChamado é o formulário de abertura de chamado (tecla "n" na lista)
+type formChamado struct {
+	titulo       textinput.Model
+	descricao    textarea.Model
+	tipo         int // domain.TypeIncident / domain.TypeRequest
+	urgencia     int // 1 a 5
+	categoria    textinput.Model
+	entidade     textinput.Model
+	requerentes  textinput.Model
+	observadores textinput.Model
+
+	foco     campoForm
+	erro     string // Erro de validação ou da API, mostrado no rodapé do formulário
+	enviando bool
+}
+
+func novoFormChamado(largura, entidade int) formChamado {
+	novoInput := func(placeholder string) textinput.Model {
+		ti := textinput.New()
+		ti.Placeholder = placeholder
+		ti.Width = largura - 20
+		return ti
+	}
+
+	desc := textarea.New()
+	desc.Placeholder = "Descreva o problema ou a solicitação..."
+	desc.CharLimit = 0
+	desc.ShowLineNumbers = false
+	desc.SetWidth(largura - 4)
+	desc.SetHeight(6)
+
+	f := formChamado{
+		titulo:       novoInput("Resumo do chamado"),
+		descricao:    desc,
+		tipo:         domain.TypeIncident,
+		urgencia:     3,
+		categoria:    novoInput("ID da categoria (opcional)"),
+		entidade:     novoInput("ID da entidade (0 = raiz)"),
+		requerentes:  novoInput("IDs separados por vírgula (vazio = você)"),
+		observadores: novoInput("IDs separados por vírgula (opcional)"),
+	}
+	f.titulo.CharLimit = 255
+	if entidade > 0 {
+		f.entidade.SetValue(strconv.Itoa(entidade)) // Entidade padrão do perfil
+	}
+	f.focar(campoTitulo)
+	return f
+}
+
+// focar move o foco para o campo c, tirando dos demais
+func (f *formChamado) focar(c campoForm) tea.Cmd {
+	f.foco = c
+	f.titulo.Blur()
+	f.descricao.Blur()
+	for _, ti := range f.inputsTexto() {
+		ti.Blur()
+	}
+
+	switch c {
+	case campoTitulo:
+		return f.titulo.Focus()
+	case campoDescricao:
+		return f.descricao.Focus()
+	case campoCategoria, campoEntidade, campoRequerentes, campoObservadores:
+		return f.inputsTexto()[c-campoCategoria].Focus()
+	}
+	return nil
+}
+
+func (f *formChamado) inputsTexto() []*textinput.Model {
+	return []*textinput.Model{&f.categoria, &f.entidade, &f.requerentes, &f.observadores}
+}
+
+// update trata navegação entre campos e repassa o resto para o campo focado
+func (f formChamado) update(msg tea.Msg) (formChamado, tea.Cmd) {
+	if k, ok := msg.(tea.KeyMsg); ok {
+		switch k.String() {
+		case "tab":
+			return f, f.focar((f.foco + 1) % totalCampos)
+		case "shift+tab":
+			return f, f.focar((f.foco + totalCampos - 1) % totalCampos)
+		case "left", "right":
+			// Tipo e urgência são seletores: setas trocam o valor
+			delta := 1
+			if k.String() == "left" {
+				delta = -1
+			}
+			switch f.foco {
+			case campoTipo:
+				if f.tipo == domain.TypeIncident {
+					f.tipo = domain.TypeRequest
+				} else {
+					f.tipo = domain.TypeIncident
+				}
+				return f, nil
+			case campoUrgencia:
+				f.urgencia = min(5, max(1, f.urgencia+delta))
+				return f, nil
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	switch f.foco {
+	case campoTitulo:
+		f.titulo, cmd = f.titulo.Update(msg)
+	case campoDescricao:
+		f.descricao, cmd = f.descricao.Update(msg)
+	case campoCategoria, campoEntidade, campoRequerentes, campoObservadores:
+		ti := f.inputsTexto()[f.foco-campoCategoria]
+		*ti, cmd = ti.Update(msg)
+	}
+	return f, cmd
+}
+
+// input converte o formulário em api.TicketInput, já validado
+func (f formChamado) input() (api.TicketInput, error) {
+	in := api.TicketInput{
+		Name:    f.titulo.Value(),
+		Content: f.descricao.Value(),
+		Type:    f.tipo,
+		Urgency: f.urgencia,
+	}
+
+	var err error
+	if in.CategoryID, err = parseIDOpcional(f.categoria.Value(), "categoria"); err != nil {
+		return in, err
+	}
+	if in.EntityID, err = parseIDOpcional(f.entidade.Value(), "entidade"); err != nil {
+		return in, err
+	}
+	if in.RequesterIDs, err = parseListaIDs(f.requerentes.Value(), "requerentes"); err != nil {
+		return in, err
+	}
+	if in.ObserverIDs, err = parseListaIDs(f.observadores.Value(), "observadores"); err != nil {
+		return in, err
+	}
+
+	return in, in.Validate()
+}
+
+func parseIDOpcional(v, campo string) (int, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s deve ser um ID numérico", campo)
+	}
+	return id, nil
+}
+
+func parseListaIDs(v, campo string) ([]int, error) {
+	var ids []int
+	for _, p := range strings.Split(v, ",") {
+		p = strings.TrimSpace(p)
+		if p == "" {
+			continue
+		}
+		id, err := strconv.Atoi(p)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %q não é um ID numérico", campo, p)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func (f formChamado) view() string {
+	titleStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#FAFAFA")).Background(lipgloss.Color("#7D56F4")).Padding(0, 1)
+	labelStyle := lipgloss.NewStyle().Width(16).Foreground(lipgloss.Color("241"))
+	focoStyle := labelStyle.Foreground(lipgloss.Color("69")).Bold(true)
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	label := func(c campoForm, texto string) string {
+		if f.foco == c {
+			return focoStyle.Render("› " + texto)
+		}
+		return labelStyle.Render("  " + texto)
+	}
+	seletor := func(c campoForm, valor string) string {
+		if f.foco == c {
+			return "◀ " + lipgloss.NewStyle().Bold(true).Render(valor) + " ▶"
+		}
+		return valor
+	}
+
+	var sb strings.Builder
+	sb.WriteString(titleStyle.Render(" ✚ Novo chamado ") + "\n\n")
+	sb.WriteString(label(campoTitulo, "Título") + f.titulo.View() + "\n")
+	sb.WriteString(label(campoDescricao, "Descrição") + "\n" + f.descricao.View() + "\n")
+	sb.WriteString(label(campoTipo, "Tipo") + seletor(campoTipo, domain.TypeLabel(f.tipo)) + "\n")
+	sb.WriteString(label(campoUrgencia, "Urgência") + seletor(campoUrgencia, domain.UrgencyLabel(f.urgencia)) + "\n")
+	sb.WriteString(label(campoCategoria, "Categoria") + f.categoria.View() + "\n")
+	sb.WriteString(label(campoEntidade, "Entidade") + f.entidade.View() + "\n")
+	sb.WriteString(label(campoRequerentes, "Requerentes") + f.requerentes.View() + "\n")
+	sb.WriteString(label(campoObservadores, "Observadores") + f.observadores.View() + "\n\n")
+
+	switch {
+	case f.enviando:
+		sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Bold(true).Render("Enviando chamado... aguarde."))
+	case f.erro != "":
+		sb.WriteString(lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠ " + f.erro))
+	default:
+		sb.WriteString(hint.Render("Tab/Shift+Tab: Navegar • ←/→: Alterar seletor • Ctrl+S: Abrir chamado • Esc: Cancelar"))
+	}
+	return sb.String()
+}
@@ -0,0 +1,225 @@
+package tui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"glpi-tui/internal/api"
+	"glpi-tui/internal/domain"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Campos do formulário de tarefa, na ordem de navegação
+const (
+	campoTarefaDescricao = iota
+	campoTarefaTempo
+	campoTarefaEstado
+	campoTarefaTecnico
+	campoTarefaInicio
+	campoTarefaFim
+	totalCamposTarefa
+)
+
+// estadosTarefa é a ordem do seletor de estado
+var estadosTarefa = []int{domain.TaskStateDone, domain.TaskStateTodo, domain.TaskStateInformation}
+
+// formTarefa é o formulário de nova tarefa com tempo gasto (tecla "t" nos detalhes)
+type formTarefa struct {
+	descricao textarea.Model
+	tempo     textinput.Model
+	estadoIdx int
+	tecnico   textinput.Model
+	inicio    textinput.Model
+	fim       textinput.Model
+
+	foco     int
+	erro     string
+	enviando bool
+}
+
+func novoFormTarefa(largura int) formTarefa {
+	novoInput := func(placeholder string) textinput.Model {
+		ti := textinput.New()
+		ti.Placeholder = placeholder
+		ti.Width = largura - 20
+		return ti
+	}
+
+	desc := textarea.New()
+	desc.Placeholder = "O que foi feito..."
+	desc.CharLimit = 0
+	desc.ShowLineNumbers = false
+	desc.SetWidth(largura - 4)
+	desc.SetHeight(4)
+
+	f := formTarefa{
+		descricao: desc,
+		tempo:     novoInput("Ex: 1h30m, 45m ou 90 (minutos)"),
+		tecnico:   novoInput("ID do técnico (vazio = você)"),
+		inicio:    novoInput("AAAA-MM-DD HH:MM (opcional)"),
+		fim:       novoInput("AAAA-MM-DD HH:MM (opcional)"),
+	}
+	f.focar(campoTarefaDescricao)
+	return f
+}
+
+func (f *formTarefa) inputs() map[int]*textinput.Model {
+	return map[int]*textinput.Model{
+		campoTarefaTempo:   &f.tempo,
+		campoTarefaTecnico: &f.tecnico,
+		campoTarefaInicio:  &f.inicio,
+		campoTarefaFim:     &f.fim,
+	}
+}
+
+func (f *formTarefa) focar(c int) tea.Cmd {
+	f.foco = c
+	f.descricao.Blur()
+	for _, ti := range f.inputs() {
+		ti.Blur()
+	}
+	if c == campoTarefaDescricao {
+		return f.descricao.Focus()
+	}
+	if ti, ok := f.inputs()[c]; ok {
+		return ti.Focus()
+	}
+	return nil
+}
+
+func (f formTarefa) update(msg tea.Msg) (formTarefa, tea.Cmd) {
+	if k, ok := msg.(tea.KeyMsg); ok {
+		switch k.String() {
+		case "tab":
+			return f, f.focar((f.foco + 1) % totalCamposTarefa)
+		case "shift+tab":
+			return f, f.focar((f.foco + totalCamposTarefa - 1) % totalCamposTarefa)
+		case "left", "right":
+			if f.foco == campoTarefaEstado {
+				delta := 1
+				if k.String() == "left" {
+					delta = len(estadosTarefa) - 1
+				}
+				f.estadoIdx = (f.estadoIdx + delta) % len(estadosTarefa)
+				return f, nil
+			}
+		}
+	}
+
+	var cmd tea.Cmd
+	if f.foco == campoTarefaDescricao {
+		f.descricao, cmd = f.descricao.Update(msg)
+	} else if ti, ok := f.inputs()[f.foco]; ok {
+		*ti, cmd = ti.Update(msg)
+	}
+	return f, cmd
+}
+
+// input converte o formulário em api.TaskInput, já validado
+func (f formTarefa) input() (api.TaskInput, error) {
+	in := api.TaskInput{
+		Content: strings.TrimSpace(f.descricao.Value()),
+		State:   estadosTarefa[f.estadoIdx],
+	}
+
+	var err error
+	if in.ActionTime, err = parseTempoGasto(f.tempo.Value()); err != nil {
+		return in, err
+	}
+	if in.TechnicianID, err = parseIDOpcional(f.tecnico.Value(), "técnico"); err != nil {
+		return in, err
+	}
+	if in.BeginPlan, err = parseDataHora(f.inicio.Value(), "início"); err != nil {
+		return in, err
+	}
+	if in.EndPlan, err = parseDataHora(f.fim.Value(), "fim"); err != nil {
+		return in, err
+	}
+	return in, in.Validate()
+}
+
+// parseTempoGasto aceita durações Go ("1h30m"), "1:30" ou um número de minutos
+func parseTempoGasto(v string) (time.Duration, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return 0, nil
+	}
+	if min, err := strconv.Atoi(v); err == nil {
+		return time.Duration(min) * time.Minute, nil
+	}
+	if h, m, ok := strings.Cut(v, ":"); ok {
+		hh, err1 := strconv.Atoi(h)
+		mm, err2 := strconv.Atoi(m)
+		if err1 == nil && err2 == nil {
+			return time.Duration(hh)*time.Hour + time.Duration(mm)*time.Minute, nil
+		}
+	}
+	d, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("tempo gasto inválido: %q (use 1h30m, 1:30 ou minutos)", v)
+	}
+	return d, nil
+}
+
+func parseDataHora(v, campo string) (time.Time, error) {
+	v = strings.TrimSpace(v)
+	if v == "" {
+		return time.Time{}, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", "02/01/2006 15:04"} {
+		if t, err := time.ParseInLocation(layout, v, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("%s inválido: %q (use AAAA-MM-DD HH:MM)", campo, v)
+}
+
+func (f formTarefa) view() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#FFC107")).
+		Padding(0, 1).
+		MarginTop(1)
+	labelStyle := lipgloss.NewStyle().Width(16).Foreground(lipgloss.Color("241"))
+	focoStyle := labelStyle.Foreground(lipgloss.Color("69")).Bold(true)
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	label := func(c int, texto string) string {
+		if f.foco == c {
+			return focoStyle.Render("› " + texto)
+		}
+		return labelStyle.Render("  " + texto)
+	}
+	estado := domain.TaskStateLabel(estadosTarefa[f.estadoIdx])
+	if f.foco == campoTarefaEstado {
+		estado = "◀ " + estado + " ▶"
+	}
+
+	body := strings.Join([]string{
+		"⏱ Nova tarefa",
+		label(campoTarefaDescricao, "Descrição"),
+		f.descricao.View(),
+		label(campoTarefaTempo, "Tempo gasto") + f.tempo.View(),
+		label(campoTarefaEstado, "Estado") + estado,
+		label(campoTarefaTecnico, "Técnico") + f.tecnico.View(),
+		label(campoTarefaInicio, "Início plan.") + f.inicio.View(),
+		label(campoTarefaFim, "Fim plan.") + f.fim.View(),
+	}, "\n")
+
+	var rodape string
+	switch {
+	case f.enviando:
+		rodape = lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Bold(true).Render("Registrando tarefa... aguarde.")
+	case f.erro != "":
+		rodape = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠ " + f.erro)
+	default:
+		rodape = hint.Render("Tab: Navegar • ←/→: Estado • Ctrl+S: Registrar • Esc: Cancelar")
+	}
+	return boxStyle.Render(body) + "\n" + rodape
+}
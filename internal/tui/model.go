@@ -1,86 +1,273 @@
 package tui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"glpi-tui/internal/api"
+	"glpi-tui/internal/cache"
+	"glpi-tui/internal/config"
 	"glpi-tui/internal/domain"
-	"sort"
+	"glpi-tui/internal/richtext"
+	"net/http"
+	"path/filepath"
 	"strings"
+	"time"
 
-	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textarea"
+	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
 
+// loadMoreThreshold é quantos itens antes do fim da lista a próxima página começa a ser buscada
+const loadMoreThreshold = 5
+
 // --- MENSAGENS DO SISTEMA ---
 // loginSuccessMsg indica que o token foi obtido
 type loginSuccessMsg struct{}
 
-// ticketsLoadedMsg traz a lista de chamados do backend
-type ticketsLoadedMsg []domain.Chamado
+// perfilCarregadoMsg indica que o ID e os grupos do usuário já estão no client
+type perfilCarregadoMsg struct{}
+
+// ticketsLoadedMsg traz uma página de chamados do backend para a fila indicada.
+// geracao identifica a consulta que originou a página, para descartar respostas de filtros antigos.
+type ticketsLoadedMsg struct {
+	fila    int
+	page    api.TicketPage
+	geracao int
+	poll    bool // Veio da busca de novidades em background
+}
 
-// errMsg para tratar erros de forma genérica
+// errMsg para tratar erros de forma genérica: vira aviso na barra de status e entrada no log
 type errMsg error
 type ticketActorsLoadedMsg struct {
 	ticketID int
 	actors   []domain.TicketActor
+	err      error // Falha na busca (actors vem vazio)
+}
+
+// ticketTimelineLoadedMsg traz a timeline completa do chamado
+type ticketTimelineLoadedMsg struct {
+	ticketID int
+	items    []domain.TimelineItem
+	err      error // Falha na busca (items vem vazio)
+}
+
+// conexaoFalhouMsg indica falha passageira (rede, servidor fora) no login ou na busca da lista:
+// com cache o programa segue offline
+type conexaoFalhouMsg struct{ err error }
+
+// sessaoRecusadaMsg indica que o servidor recusou o login ou o perfil (senha, cliente OAuth,
+// permissão): repetir sozinho não adianta, só o Ctrl+R tenta de novo
+type sessaoRecusadaMsg struct{ err error }
+
+// segredoNaoSalvoMsg indica que a sessão renovada no meio do uso não pôde ser guardada
+// (ex.: frase-senha do arquivo cifrado não informada antes da TUI abrir)
+type segredoNaoSalvoMsg struct{ err error }
+
+// ticketsFalhouMsg indica que o servidor recusou a busca da fila (ex: filtro inválido, HTTP 400)
+type ticketsFalhouMsg struct {
+	fila    int
+	geracao int
+	err     error
+	poll    bool
+}
+
+// ticketCreatedMsg indica que o chamado foi aberto; err preenchido mantém o formulário aberto
+type ticketCreatedMsg struct {
+	id  int
+	err error
+}
+
+// solutionOptionsLoadedMsg traz os tipos e modelos de solução para o diálogo
+type solutionOptionsLoadedMsg struct {
+	tipos   []domain.SolutionType
+	modelos []domain.SolutionTemplate
+}
+
+// solutionCreatedMsg indica o resultado do envio da solução; err mantém o diálogo aberto
+type solutionCreatedMsg struct {
+	ticketID int
+	err      error
+}
+
+// solutionReviewedMsg indica que a solução pendente foi aprovada ou recusada
+type solutionReviewedMsg struct {
+	ticketID int
+	aprovada bool
 }
 
-// ticketFollowupsLoadedMsg traz a lista de acompanhamentos
-type ticketFollowupsLoadedMsg struct {
-	ticketID  int
-	followups []domain.TicketFollowup
+// taskCreatedMsg indica o resultado do registro da tarefa; err mantém o formulário aberto
+type taskCreatedMsg struct {
+	ticketID int
+	err      error
 }
-type followupCreatedMsg struct{} // Sucesso no envio
-type assignedSuccessMsg struct{} // Indica sucesso na atribuiçao
 
 // --- MODEL PRINCIPAL ---
 type model struct {
-	client   *api.Client
-	list     list.Model
+	client   api.Backend
+	filas    []fila // Abas da lista principal (Meus, Não atribuídos, Meus grupos, Todos)
+	aba      int    // Índice da fila ativa
 	viewport viewport.Model
 	spinner  spinner.Model
 
 	// Novos campos para a área de resposta
 	textarea           textarea.Model
-	responding         bool // true = mostrando a caixa de texto, false = navegando
+	responding         bool   // true = mostrando a caixa de texto, false = navegando
+	erroEditor         string // Falha ao abrir/ler o $EDITOR, mostrada junto da caixa de resposta
 	refreshing         bool
 	chamadoSelecionado *domain.Chamado
-	err                error
-	loading            bool
-	ready              bool
+	// ctxDetalhes/cancelDetalhes permitem abortar as buscas em voo do chamado aberto quando o usuário sai dele
+	ctxDetalhes    context.Context
+	cancelDetalhes context.CancelFunc
+	loading        bool
+	ready          bool
+
+	// Barra de filtro server-side (RSQL)
+	filterInput    textinput.Model
+	editandoFiltro bool             // true = barra de filtro com foco
+	filtro         api.TicketFilter // Filtro aplicado na consulta atual
+	filtroErro     string           // Erro de sintaxe mostrado na própria barra
+
+	// Menu de troca de status nos detalhes
+	mudandoStatus bool
+	seletorStatus seletorStatus
+
+	// Diálogo de solução e aprovação/recusa de solução pendente
+	solucionando     bool
+	dialogoSolucao   dialogoSolucao
+	recusandoSolucao int // ID da solução sendo recusada (motivo digitado no textarea de resposta)
+	aprovandoSolucao int // ID da solução aguardando o "s" que confirma a aprovação
+
+	// Formulário de tarefa (tempo gasto)
+	registrandoTarefa bool
+	formTarefa        formTarefa
+
+	// Formulário de abertura de chamado
+	criandoChamado bool
+	formChamado    formChamado
+
+	// Cache offline: lista e chamados já vistos aparecem antes (ou sem) resposta do servidor
+	cache       *cache.Store
+	logado      bool
+	offline     bool  // Servidor inacessível; mostrando só o que está no cache
+	erroConexao error // Motivo do modo offline
+	erroSessao  error // Login ou perfil recusados; a busca de novidades não insiste
+
+	segredoFalhou <-chan error // Falhas ao guardar a sessão renovada (veja WithSaveErrors); nil = sem avisos
+
+	// Fila de envio: escritas aguardando confirmação do servidor, na ordem em que foram feitas
+	outbox         []cache.OutboxEntry
+	outboxEnviando uint64 // ID da entrada em voo (0 = nenhuma)
+	outboxSeq      uint64 // IDs das entradas que não puderam ser gravadas no cache
+
+	// Painel de documentos (listar, baixar e anexar)
+	vendoDocumentos bool
+	painelDocs      painelDocumentos
+	pastaDownload   string
+
+	// Busca de novidades em background
+	intervaloPoll    time.Duration
+	sino             bool
+	vistos           map[int]cache.Seen // Estado de cada chamado na última vez que foi aberto (persistido)
+	vistoAnterior    *cache.Seen        // Visita anterior do chamado aberto, para o divisor de novidades
+	escritasProprias map[int]bool       // Chamados alterados pelo próprio usuário: o próximo date_mod conta como lido
+	conferidos       map[int]string     // date_mod cujas respostas já foram contadas
+	novasRespostas   map[int]int        // Chamado -> acompanhamentos novos desde a última abertura
+	tituloJanela     string
+
+	// Barra de status (avisos temporários) e log dos erros da sessão
+	avisos      []aviso
+	avisoSeq    int
+	logErros    []aviso
+	vendoLog    bool
+	logViewport viewport.Model
+
+	// Perfil do arquivo de configuração e troca de perfil (Ctrl+P)
+	perfil         string
+	entidadePadrao int // Entidade sugerida no formulário de novo chamado
+	trocandoPerfil bool
+	seletorPerfil  seletorPerfil
+	proximoPerfil  *config.Config // Perfil escolhido: a TUI encerra e o main reabre com ele
+
+	width, height int
+}
+
+// Option ajusta o model criado por InitialModel
+type Option func(*model)
+
+// WithSaveErrors mostra como aviso cada erro recebido em ch; o main liga o canal ao
+// config.Config.OnSaveError, para a sessão renovada que não puder ser guardada não passar calada
+func WithSaveErrors(ch <-chan error) Option {
+	return func(m *model) { m.segredoFalhou = ch }
 }
 
 // --- INITIAL MODEL ---
-func InitialModel(client *api.Client) model {
+func InitialModel(client api.Backend, cfg *config.Config, store *cache.Store, opts ...Option) model {
+	aplicarTema(cfg.Theme)
+
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 
-	l := list.New([]list.Item{}, list.NewDefaultDelegate(), 0, 0)
-	l.Title = "Chamados GLPI"
-	l.SetShowHelp(false)
+	fi := textinput.New()
+	fi.Prompt = "🔎 "
+	fi.Placeholder = "Filtrar no servidor (Enter aplica, Esc cancela)"
 
 	// Configuração do Textarea
 	ta := textarea.New()
 	ta.Placeholder = "Digite sua resposta aqui... (Ctrl+S para enviar, Esc para cancelar)"
-	ta.Focus()          // Fica focado quando ativado
-	ta.CharLimit = 2000 // Limite razoável para GLPI
-	ta.SetWidth(50)     // Largura inicial, será ajustada no WindowSizeMsg
-	ta.SetHeight(5)     // Altura da caixa de texto
+	ta.Focus()       // Fica focado quando ativado
+	ta.CharLimit = 0 // Sem limite: respostas longas (logs, passo a passo) são comuns
+	ta.MaxHeight = 0 // Idem para o número de linhas
+	ta.SetWidth(50)  // Largura inicial, será ajustada no WindowSizeMsg
+	ta.SetHeight(5)  // Altura da caixa de texto
 	ta.ShowLineNumbers = false
 
-	return model{
+	// Mostra as filas salvas na última sessão enquanto o login acontece
+	filas := novasFilas()
+	temCache := false
+	for i := range filas {
+		if tickets, total := store.Queue(filas[i].nome); len(tickets) > 0 {
+			filas[i].aplicarCache(tickets, total)
+			temCache = true
+		}
+	}
+
+	m := model{
 		client:     client,
-		list:       l,
+		filas:      filas,
 		spinner:    s,
 		textarea:   ta,    // <--- Injecao
 		responding: false, // Começa oculto
-		loading:    true,
+		loading:    !temCache,
+		cache:      store,
+
+		filterInput:   fi,
+		pastaDownload: cfg.DownloadDir,
+
+		// Escritas feitas offline na última sessão saem assim que o perfil carregar
+		outbox: store.Outbox(),
+
+		intervaloPoll:    cfg.PollInterval,
+		sino:             cfg.Bell,
+		vistos:           store.SeenAll(),
+		escritasProprias: map[int]bool{},
+		conferidos:       map[int]string{},
+		novasRespostas:   map[int]int{},
+
+		perfil:         cfg.Profile,
+		entidadePadrao: cfg.DefaultEntity,
+	}
+	for _, opt := range opts {
+		opt(&m)
 	}
+	m.marcarOutbox()
+	m.marcarLeitura()
+	return m
 }
 
 // Init é a primeira função que o Bubble Tea roda
@@ -89,49 +276,67 @@ func (m model) Init() tea.Cmd {
 	return tea.Batch(
 		spinner.Tick,
 		performLoginCmd(m.client),
+		agendarPoll(m.intervaloPoll),
+		esperarSegredo(m.segredoFalhou),
 	)
 }
 
+// esperarSegredo aguarda a próxima falha ao guardar a sessão renovada
+func esperarSegredo(ch <-chan error) tea.Cmd {
+	if ch == nil {
+		return nil
+	}
+	return func() tea.Msg { return segredoNaoSalvoMsg{<-ch} }
+}
+
 // --- COMANDOS ASSÍNCRONOS (API) ---
 
 // performLoginCmd realiza o login (Network I/O)
-func performLoginCmd(c *api.Client) tea.Cmd {
+func performLoginCmd(c api.Backend) tea.Cmd {
 	return func() tea.Msg {
 		if err := c.Login(); err != nil {
-			return errMsg(err)
+			if api.IsTemporary(err) {
+				return conexaoFalhouMsg{err}
+			}
+			return sessaoRecusadaMsg{err}
 		}
 		return loginSuccessMsg{}
 	}
 }
 
-// fetchTicketsCmd busca os chamados usando o token já salvo
-func fetchTicketsCmd(c *api.Client) tea.Cmd {
+// fetchTicketsCmd busca uma página de chamados conforme q usando o token já salvo
+func fetchTicketsCmd(c api.Backend, q api.TicketQuery, fila, geracao int) tea.Cmd {
 	return func() tea.Msg {
-		tickets, err := c.GetTickets()
+		page, err := c.GetTicketsPage(q)
 		if err != nil {
-			return errMsg(err)
+			if api.IsTemporary(err) {
+				return conexaoFalhouMsg{err}
+			}
+			return ticketsFalhouMsg{fila: fila, geracao: geracao, err: err}
 		}
-		return ticketsLoadedMsg(tickets)
+		return ticketsLoadedMsg{fila: fila, page: page, geracao: geracao}
 	}
 }
 
-func createFollowupCmd(c *api.Client, ticketID int, content string) tea.Cmd {
+// createTicketCmd abre o chamado; o erro volta para o formulário em vez de derrubar a tela
+func createTicketCmd(c api.Backend, in api.TicketInput) tea.Cmd {
 	return func() tea.Msg {
-		if err := c.CreateTicketFollowup(ticketID, content); err != nil {
-			return errMsg(err)
-		}
-		return followupCreatedMsg{}
+		id, err := c.CreateTicket(in)
+		return ticketCreatedMsg{id: id, err: err}
 	}
 }
 
 // fetchActorsCmd busca os atores de um ticket específico em background
-func fetchActorsCmd(c *api.Client, id int) tea.Cmd {
+func fetchActorsCmd(ctx context.Context, c api.Backend, id int) tea.Cmd {
 	return func() tea.Msg {
-		actors, err := c.GetTicketActors(id)
+		actors, err := c.GetTicketActorsContext(ctx, id)
+		if errors.Is(err, context.Canceled) {
+			return nil // Usuário já saiu do chamado: descarta silenciosamente
+		}
 		if err != nil {
 			// Em caso de erro, podemos retornar um erro genérico ou logar
 			// Por enquanto retornamos vazio para não travar a UI
-			return ticketActorsLoadedMsg{ticketID: id, actors: []domain.TicketActor{}}
+			return ticketActorsLoadedMsg{ticketID: id, actors: []domain.TicketActor{}, err: err}
 		}
 		return ticketActorsLoadedMsg{
 			ticketID: id,
@@ -140,39 +345,74 @@ func fetchActorsCmd(c *api.Client, id int) tea.Cmd {
 	}
 }
 
-// fetchFollowupsCmd busca os acompanhamentos em background
-func fetchFollowupsCmd(c *api.Client, id int) tea.Cmd {
+// fetchTimelineCmd busca a timeline completa (acompanhamentos, tarefas, soluções...) em background
+func fetchTimelineCmd(ctx context.Context, c api.Backend, id int) tea.Cmd {
 	return func() tea.Msg {
-		followups, err := c.GetTicketFollowups(id)
+		items, err := c.GetTicketTimelineContext(ctx, id)
+		if errors.Is(err, context.Canceled) {
+			return nil // Usuário já saiu do chamado: descarta silenciosamente
+		}
 		if err != nil {
 			// Retorna lista vazia em caso de erro para não travar
-			return ticketFollowupsLoadedMsg{ticketID: id, followups: []domain.TicketFollowup{}}
-		}
-		return ticketFollowupsLoadedMsg{
-			ticketID:  id,
-			followups: followups,
+			return ticketTimelineLoadedMsg{ticketID: id, items: []domain.TimelineItem{}, err: err}
 		}
+		return ticketTimelineLoadedMsg{ticketID: id, items: items}
 	}
 }
 
-// fetchMyIDCmd busca o ID do usuário em background
-func fetchMyIDCmd(c *api.Client) tea.Cmd {
+// fetchProfileCmd busca o ID e os grupos do usuário em background (necessários para as filas)
+func fetchProfileCmd(c api.Backend) tea.Cmd {
 	return func() tea.Msg {
 		if err := c.GetMyID(); err != nil {
-			return errMsg(err)
+			if api.IsTemporary(err) {
+				return conexaoFalhouMsg{err}
+			}
+			return sessaoRecusadaMsg{fmt.Errorf("erro ao carregar o perfil: %w", err)}
 		}
-		return nil // Sem mensagem específica, apenas sucesso silencioso (ou log)
+		// Sem grupos a fila "Meus grupos" apenas fica vazia; não impede o uso das demais
+		_ = c.GetMyGroups()
+		return perfilCarregadoMsg{}
 	}
 }
 
-// assignToMeCmd dispara a atribuição via UPDATE (PATCH)
-func assignToMeCmd(c *api.Client, ticketID int, entityID int) tea.Cmd {
+// fetchSolutionOptionsCmd carrega tipos e modelos de solução; falhas deixam as listas vazias
+func fetchSolutionOptionsCmd(c api.Backend) tea.Cmd {
 	return func() tea.Msg {
-		// CORREÇÃO: Chamar AssignTicketViaUpdate em vez de AssignTicketToMe
-		if err := c.AssignTicketViaUpdate(ticketID, entityID); err != nil {
+		tipos, _ := c.GetSolutionTypes()
+		modelos, _ := c.GetSolutionTemplates()
+		return solutionOptionsLoadedMsg{tipos: tipos, modelos: modelos}
+	}
+}
+
+// createSolutionCmd registra a solução no chamado
+func createSolutionCmd(c api.Backend, ch domain.Chamado, in api.SolutionInput) tea.Cmd {
+	return func() tea.Msg {
+		err := c.CreateTicketSolution(ch.ID, ch.Entity.ID, in)
+		return solutionCreatedMsg{ticketID: ch.ID, err: err}
+	}
+}
+
+// reviewSolutionCmd aprova ou recusa (com motivo opcional) a solução pendente
+func reviewSolutionCmd(c api.Backend, ch domain.Chamado, solutionID int, aprovar bool, motivo string) tea.Cmd {
+	return func() tea.Msg {
+		var err error
+		if aprovar {
+			err = c.ApproveTicketSolution(ch.ID, ch.Entity.ID, solutionID)
+		} else {
+			err = c.RefuseTicketSolution(ch.ID, ch.Entity.ID, solutionID, motivo)
+		}
+		if err != nil {
 			return errMsg(err)
 		}
-		return assignedSuccessMsg{}
+		return solutionReviewedMsg{ticketID: ch.ID, aprovada: aprovar}
+	}
+}
+
+// createTaskCmd registra a tarefa no chamado
+func createTaskCmd(c api.Backend, ch domain.Chamado, in api.TaskInput) tea.Cmd {
+	return func() tea.Msg {
+		err := c.CreateTicketTask(ch.ID, ch.Entity.ID, in)
+		return taskCreatedMsg{ticketID: ch.ID, err: err}
 	}
 }
 
@@ -192,9 +432,16 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "esc":
 				// Cancela e volta para visualização
 				m.responding = false
+				m.recusandoSolucao = 0
+				m.erroEditor = ""
 				m.textarea.Reset()
 				return m, nil
 
+			case "ctrl+e":
+				// Continua a resposta no $EDITOR (o rascunho atual vai junto)
+				m.erroEditor = ""
+				return m, abrirEditorCmd(m.textarea.Value())
+
 			case "ctrl+s":
 				// Envia o followup
 				content := m.textarea.Value()
@@ -202,16 +449,357 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, nil // Não envia vazio
 				}
 				m.responding = false
+				m.erroEditor = ""
 				m.textarea.Reset()
 
-				// Dispara comando de criação + loading visual se quisesse
-				return m, createFollowupCmd(m.client, m.chamadoSelecionado.ID, content)
+				// Motivo de recusa de solução: recusa e registra o motivo como acompanhamento
+				if m.recusandoSolucao != 0 {
+					id := m.recusandoSolucao
+					m.recusandoSolucao = 0
+					m.refreshing = true
+					return m, reviewSolutionCmd(m.client, *m.chamadoSelecionado, id, false, content)
+				}
+
+				// Vai para a fila de envio: sem rede a resposta fica guardada e sai quando a conexão voltar
+				return m, m.enfileirar(cache.OutboxEntry{
+					Kind:     cache.OutboxFollowup,
+					TicketID: m.chamadoSelecionado.ID,
+					EntityID: m.chamadoSelecionado.Entity.ID,
+					Content:  content,
+				})
+			}
+
+		case editorFinishedMsg:
+			if msg.err != nil {
+				m.erroEditor = msg.err.Error()
+				return m, nil
 			}
+			// Volta para a caixa de resposta para revisar antes de enviar com Ctrl+S
+			m.textarea.SetValue(msg.conteudo)
+			m.textarea.Focus()
+			return m, textarea.Blink
 		}
 
 		// Atualiza o componente textarea (digitação, cursor, etc)
 		m.textarea, cmd = m.textarea.Update(msg)
-		return m, cmd
+		if _, ok := msg.(tea.KeyMsg); ok {
+			return m, cmd
+		}
+		// Respostas da API e ticks seguem o fluxo normal enquanto o usuário digita
+		cmds = append(cmds, cmd)
+	}
+
+	// --- 1a. DIÁLOGO DE SOLUÇÃO ---
+	if m.solucionando {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			if m.dialogoSolucao.enviando {
+				return m, nil // Aguarda a resposta da API
+			}
+			switch msg.String() {
+			case "esc":
+				m.solucionando = false
+				return m, nil
+			case "ctrl+s":
+				in, err := m.dialogoSolucao.input()
+				if err != nil {
+					m.dialogoSolucao.erro = err.Error()
+					return m, nil
+				}
+				m.dialogoSolucao.erro = ""
+				m.dialogoSolucao.enviando = true
+				return m, createSolutionCmd(m.client, *m.chamadoSelecionado, in)
+			}
+			m.dialogoSolucao, cmd = m.dialogoSolucao.update(msg)
+			return m, cmd
+
+		case solutionOptionsLoadedMsg:
+			m.dialogoSolucao.aplicarOpcoes(msg.tipos, msg.modelos)
+			return m, nil
+
+		case solutionCreatedMsg:
+			m.dialogoSolucao.enviando = false
+			if msg.err != nil {
+				m.dialogoSolucao.erro = msg.err.Error()
+				return m, nil
+			}
+			m.solucionando = false
+			m.atualizarStatusLocal(msg.ticketID, domain.StatusSolved)
+			return m, tea.Batch(
+				fetchTimelineCmd(m.detalhesCtx(), m.client, msg.ticketID),
+				m.notificar(sevSucesso, fmt.Sprintf("Solução registrada no chamado #%d", msg.ticketID)),
+			)
+
+		default:
+			m.dialogoSolucao, cmd = m.dialogoSolucao.update(msg)
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	// --- 1b. FORMULÁRIO DE TAREFA ---
+	if m.registrandoTarefa {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			if m.formTarefa.enviando {
+				return m, nil // Aguarda a resposta da API
+			}
+			switch msg.String() {
+			case "esc":
+				m.registrandoTarefa = false
+				return m, nil
+			case "ctrl+s":
+				in, err := m.formTarefa.input()
+				if err != nil {
+					m.formTarefa.erro = err.Error()
+					return m, nil
+				}
+				m.formTarefa.erro = ""
+				m.formTarefa.enviando = true
+				return m, createTaskCmd(m.client, *m.chamadoSelecionado, in)
+			}
+			m.formTarefa, cmd = m.formTarefa.update(msg)
+			return m, cmd
+
+		case taskCreatedMsg:
+			m.formTarefa.enviando = false
+			if msg.err != nil {
+				m.formTarefa.erro = msg.err.Error()
+				return m, nil
+			}
+			m.registrandoTarefa = false
+			return m, tea.Batch(
+				fetchTimelineCmd(m.detalhesCtx(), m.client, msg.ticketID),
+				m.notificar(sevSucesso, fmt.Sprintf("Tarefa registrada no chamado #%d", msg.ticketID)),
+			)
+
+		default:
+			m.formTarefa, cmd = m.formTarefa.update(msg)
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	// --- 1c. MENU DE STATUS ---
+	// Aprovar fecha o chamado: só com "s"; qualquer outra tecla desiste
+	if m.aprovandoSolucao != 0 {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			id := m.aprovandoSolucao
+			m.aprovandoSolucao = 0
+			if (msg.String() == "s" || msg.String() == "y") && m.chamadoSelecionado != nil && !m.refreshing {
+				m.refreshing = true
+				return m, reviewSolutionCmd(m.client, *m.chamadoSelecionado, id, true, "")
+			}
+			return m, nil
+		}
+	}
+
+	if m.mudandoStatus {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			if msg.String() == "esc" {
+				m.mudandoStatus = false
+				return m, nil
+			}
+			var escolha *domain.StatusTransition
+			m.seletorStatus, escolha = m.seletorStatus.update(msg)
+			if escolha != nil && m.chamadoSelecionado != nil && escolha.To == domain.StatusSolved {
+				// Solucionar no GLPI significa registrar uma solução, não só trocar o status
+				m.mudandoStatus = false
+				return m, m.abrirDialogoSolucao()
+			}
+			if escolha != nil && m.chamadoSelecionado != nil {
+				m.mudandoStatus = false
+				c := m.chamadoSelecionado
+				return m, m.enfileirar(cache.OutboxEntry{
+					Kind:     cache.OutboxStatus,
+					TicketID: c.ID,
+					EntityID: c.Entity.ID,
+					ToStatus: escolha.To,
+				})
+			}
+			return m, nil
+		}
+	}
+
+	// --- 1d. FORMULÁRIO DE NOVO CHAMADO ---
+	if m.criandoChamado {
+		switch msg := msg.(type) {
+		case tea.KeyMsg:
+			if m.formChamado.enviando {
+				return m, nil // Aguarda a resposta da API
+			}
+			switch msg.String() {
+			case "esc":
+				m.criandoChamado = false
+				return m, nil
+
+			case "ctrl+s":
+				in, err := m.formChamado.input()
+				if err != nil {
+					m.formChamado.erro = err.Error()
+					return m, nil
+				}
+				m.formChamado.erro = ""
+				m.formChamado.enviando = true
+				return m, createTicketCmd(m.client, in)
+			}
+			m.formChamado, cmd = m.formChamado.update(msg)
+			return m, cmd
+
+		case ticketCreatedMsg:
+			m.formChamado.enviando = false
+			if msg.err != nil {
+				m.formChamado.erro = msg.err.Error()
+				return m, nil
+			}
+			m.criandoChamado = false
+			return m, tea.Batch(m.recarregarFilas(), m.notificar(sevSucesso, fmt.Sprintf("Chamado #%d aberto", msg.id)))
+
+		default:
+			// Demais mensagens (cursor piscando, páginas da lista, resize) seguem o fluxo normal
+			m.formChamado, cmd = m.formChamado.update(msg)
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	// --- 1e. BARRA DE FILTRO (Foco no campo de busca) ---
+	if m.editandoFiltro {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "esc":
+				m.editandoFiltro = false
+				m.filtroErro = ""
+				m.filterInput.Blur()
+				m.ajustarLayout()
+				return m, nil
+
+			case "enter":
+				f, err := api.ParseFilter(m.filterInput.Value(), m.client.MyUserID())
+				if err != nil {
+					m.filtroErro = err.Error()
+					return m, nil
+				}
+				m.editandoFiltro = false
+				m.filtroErro = ""
+				m.filterInput.Blur()
+				m.filtro = f
+				m.ajustarLayout()
+				return m, m.recarregarFilas()
+			}
+		}
+
+		m.filterInput, cmd = m.filterInput.Update(msg)
+		if _, ok := msg.(tea.KeyMsg); ok {
+			return m, cmd
+		}
+		cmds = append(cmds, cmd)
+	}
+
+	// --- 1f. PAINEL DE DOCUMENTOS ---
+	if m.vendoDocumentos && m.chamadoSelecionado != nil {
+		c := m.chamadoSelecionado
+		switch msg := msg.(type) {
+		case transferenciaMsg:
+			m.painelDocs.feito, m.painelDocs.total = msg.feito, msg.total
+			return m, esperarTransferencia(msg.ch)
+
+		case transferenciaConcluidaMsg:
+			m.painelDocs.transferindo = false
+			if msg.err != nil {
+				m.painelDocs.erro = msg.err.Error()
+				return m, nil
+			}
+			if msg.upload {
+				m.painelDocs.aviso = "Arquivo anexado"
+				m.painelDocs.modo = docsLista
+				if c.ID == msg.ticketID {
+					m.refreshing = true
+					return m, fetchTimelineCmd(m.detalhesCtx(), m.client, msg.ticketID)
+				}
+				return m, nil
+			}
+			m.painelDocs.aviso = "Salvo em " + msg.caminho
+			return m, nil
+
+		case tea.KeyMsg:
+			if m.painelDocs.transferindo {
+				if msg.String() == "esc" {
+					// Sai do chamado e cancela a transferência junto com as demais buscas
+					m.vendoDocumentos = false
+					m.fecharDetalhes()
+				}
+				return m, nil
+			}
+			if msg.String() == "esc" && m.painelDocs.modo == docsLista {
+				m.vendoDocumentos = false
+				return m, nil
+			}
+
+			var doc *domain.TicketDocument
+			var enviar bool
+			m.painelDocs, cmd, doc, enviar = m.painelDocs.update(msg, c.Documents, c.Followups)
+			switch {
+			case doc != nil:
+				m.painelDocs.erro, m.painelDocs.aviso = "", ""
+				m.painelDocs.transferindo = true
+				m.painelDocs.descricao = "Baixando " + doc.Filename
+				m.painelDocs.feito, m.painelDocs.total = 0, 0
+				return m, downloadDocumentCmd(m.detalhesCtx(), m.client, c.ID, *doc, m.pastaDownload)
+			case enviar:
+				m.painelDocs.erro, m.painelDocs.aviso = "", ""
+				m.painelDocs.transferindo = true
+				m.painelDocs.descricao = "Enviando " + filepath.Base(m.painelDocs.arquivo)
+				m.painelDocs.feito, m.painelDocs.total = 0, 0
+				return m, uploadDocumentCmd(m.detalhesCtx(), m.client, api.UploadInput{
+					TicketID:   c.ID,
+					EntityID:   c.Entity.ID,
+					Path:       m.painelDocs.arquivo,
+					FollowupID: m.painelDocs.followupDestino(c.Followups),
+				})
+			}
+			return m, cmd
+
+		default:
+			// Leitura de diretório do seletor de arquivos; o resto segue o fluxo normal
+			m.painelDocs, cmd, _, _ = m.painelDocs.update(msg, c.Documents, c.Followups)
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	// --- 1g. LOG DE ERROS ---
+	if m.vendoLog {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc", "L":
+				m.vendoLog = false
+			case "c":
+				m.logErros = nil
+				m.logViewport.SetContent(renderLogErros(nil, m.logViewport.Width))
+			default:
+				m.logViewport, cmd = m.logViewport.Update(msg)
+				return m, cmd
+			}
+			return m, nil
+		}
+	}
+
+	// --- 1h. SELETOR DE PERFIL ---
+	if m.trocandoPerfil {
+		if msg, ok := msg.(tea.KeyMsg); ok {
+			switch msg.String() {
+			case "ctrl+c":
+				return m, tea.Quit
+			case "esc":
+				m.trocandoPerfil = false
+				return m, nil
+			}
+			var nome string
+			m.seletorPerfil, nome = m.seletorPerfil.update(msg)
+			if nome != "" {
+				return m, m.trocarPerfil(nome)
+			}
+			return m, nil
+		}
 	}
 
 	// --- 2. MODO NORMAL (Navegação) ---
@@ -223,21 +811,121 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "ctrl+c":
 			return m, tea.Quit
 
+		// Abre o log de erros da sessão
+		case "L":
+			m.abrirLog()
+			return m, nil
+
 		case "esc":
 			if m.chamadoSelecionado != nil {
-				// Sai dos detalhes e volta pra lista
-				m.chamadoSelecionado = nil
+				// Sai dos detalhes e volta pra lista, abortando o que ainda estiver carregando
+				m.fecharDetalhes()
 				return m, nil
 			}
 		case "a":
 			if m.chamadoSelecionado != nil {
-				// Verifica se já temos o ID do usuário
-				if m.client.UserID == 0 {
-					m.err = fmt.Errorf("aguarde, carregando perfil de usuário...")
+				// Solucionados e fechados não são atribuídos: a atribuição os reabriria
+				if _, err := domain.AssignStatus(m.chamadoSelecionado.Status.ID); err != nil {
+					return m, m.notificar(sevAlerta, err.Error())
+				}
+				// Sem o perfil carregado a atribuição fica na fila até o ID do usuário chegar
+				return m, m.enfileirar(cache.OutboxEntry{
+					Kind:     cache.OutboxAssign,
+					TicketID: m.chamadoSelecionado.ID,
+					EntityID: m.chamadoSelecionado.Entity.ID,
+				})
+			}
+		// Reenvia agora o que está na fila deste chamado (inclusive o que falhou)
+		case "p":
+			if m.chamadoSelecionado != nil {
+				return m, m.reenviarChamado(m.chamadoSelecionado.ID)
+			}
+		// Descarta os envios recusados pelo servidor
+		case "P":
+			if m.chamadoSelecionado != nil {
+				m.descartarFalhas(m.chamadoSelecionado.ID)
+				return m, nil
+			}
+		// Abre o menu de troca de status
+		case "s":
+			if m.chamadoSelecionado != nil && !m.refreshing {
+				m.mudandoStatus = true
+				m.seletorStatus = novoSeletorStatus(m.chamadoSelecionado.Status.ID)
+				return m, nil
+			}
+		// Abre o diálogo de solução
+		case "S":
+			if m.chamadoSelecionado != nil && !m.refreshing {
+				return m, m.abrirDialogoSolucao()
+			}
+		// Abre o formulário de tarefa com tempo gasto
+		case "t":
+			if m.chamadoSelecionado != nil && !m.refreshing {
+				m.registrandoTarefa = true
+				m.formTarefa = novoFormTarefa(m.width)
+				return m, textarea.Blink
+			}
+		// Aprova / recusa a solução aguardando aprovação
+		case "A", "X":
+			if m.chamadoSelecionado != nil && !m.refreshing {
+				sol := m.chamadoSelecionado.PendingSolution()
+				if sol == nil {
+					return m, nil
+				}
+				if msg.String() == "A" {
+					m.aprovandoSolucao = sol.ID
 					return m, nil
 				}
-				m.refreshing = true // Feedback visual
-				return m, assignToMeCmd(m.client, m.chamadoSelecionado.ID, m.chamadoSelecionado.Entity.ID)
+				// Recusa: pede o motivo no textarea de resposta
+				m.recusandoSolucao = sol.ID
+				m.responding = true
+				m.textarea.Placeholder = "Motivo da recusa da solução (opcional)..."
+				m.textarea.Focus()
+				return m, textarea.Blink
+			}
+		// Abre a barra de filtro server-side na lista
+		case "/":
+			if m.chamadoSelecionado == nil && !m.loading {
+				m.editandoFiltro = true
+				m.ajustarLayout()
+				return m, m.filterInput.Focus()
+			}
+		// Abre o formulário de novo chamado
+		case "n":
+			if m.chamadoSelecionado == nil && !m.loading {
+				m.criandoChamado = true
+				m.formChamado = novoFormChamado(m.width, m.entidadePadrao)
+				return m, textinput.Blink
+			}
+		// Troca de fila (abas)
+		case "1", "2", "3", "4":
+			if m.chamadoSelecionado == nil && !m.loading {
+				return m, m.trocarAba(int(msg.String()[0] - '1'))
+			}
+		case "tab":
+			if m.chamadoSelecionado == nil && !m.loading {
+				return m, m.trocarAba((m.aba + 1) % len(m.filas))
+			}
+		case "shift+tab":
+			if m.chamadoSelecionado == nil && !m.loading {
+				return m, m.trocarAba((m.aba + len(m.filas) - 1) % len(m.filas))
+			}
+		// Troca de perfil (outro servidor GLPI)
+		case "ctrl+p":
+			if m.chamadoSelecionado == nil {
+				return m, m.abrirSeletorPerfil()
+			}
+		case "ctrl+r":
+			if m.chamadoSelecionado == nil && !m.loading {
+				m.erroSessao = nil
+				if !m.logado {
+					// Offline desde a abertura: tenta o login de novo
+					return m, performLoginCmd(m.client)
+				}
+				if m.client.MyUserID() == 0 {
+					return m, fetchProfileCmd(m.client)
+				}
+				return m, m.filaAtual().recarregar(m.client, m.filtro, m.aba)
 			}
 		// Abre a caixa de resposta se estiver vendo um chamado
 		case "r":
@@ -247,84 +935,225 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.textarea.Focus()
 				return m, textarea.Blink // Comando necessário para o cursor piscar
 			}
+		// Responde direto no $EDITOR
+		case "E":
+			if m.chamadoSelecionado != nil {
+				m.responding = true
+				m.textarea.Placeholder = "Escreva sua resposta para o chamado #" + fmt.Sprint(m.chamadoSelecionado.ID) + "..."
+				m.textarea.Focus()
+				return m, abrirEditorCmd("")
+			}
+		case "d", "f":
+			if m.chamadoSelecionado != nil {
+				m.vendoDocumentos = true
+				m.painelDocs = novoPainelDocumentos(m.width, m.height)
+				if msg.String() == "f" {
+					// Atalho direto para anexar um arquivo
+					m.painelDocs, cmd = m.painelDocs.abrirSeletor()
+					return m, cmd
+				}
+				return m, nil
+			}
 		case "u":
 			if m.chamadoSelecionado != nil && !m.refreshing { // Evita spam de 'u'
 				m.refreshing = true // 1. Ativa o indicador
 				// Opcional: Adiciona spinner.Tick se quiser animar o icone, mas só texto já basta
-				return m, fetchFollowupsCmd(m.client, m.chamadoSelecionado.ID)
+				return m, fetchTimelineCmd(m.detalhesCtx(), m.client, m.chamadoSelecionado.ID)
 			}
 		}
 
 	case tea.WindowSizeMsg:
-		m.list.SetWidth(msg.Width)
-		m.list.SetHeight(msg.Height)
+		m.width, m.height = msg.Width, msg.Height
+		for i := range m.filas {
+			m.filas[i].list.SetWidth(msg.Width)
+		}
+		m.ajustarLayout()
+		m.filterInput.Width = msg.Width - 6
 
 		// Ajusta viewport (deixando espaço para rodapé se precisar)
 		m.viewport = viewport.New(msg.Width, msg.Height-5)
+		// O texto do chamado é quebrado na largura da tela: redesenha com a nova largura
+		m.renderChamadoDetalhes()
 
 		// Ajusta largura da caixa de texto para caber na tela
 		m.textarea.SetWidth(msg.Width - 4)
+		if m.vendoLog {
+			m.abrirLog()
+		}
 		m.ready = true
 
 	// --- MENSAGENS DE API ---
 
 	case loginSuccessMsg:
-		// SUCESSO NO LOGIN: Dispara busca de Tickets E busca do ID do Usuário
-		cmds = append(cmds, fetchTicketsCmd(m.client))
-		cmds = append(cmds, fetchMyIDCmd(m.client))
+		// SUCESSO NO LOGIN: Busca o perfil primeiro, pois as filas dependem do ID e dos grupos
+		m.logado = true
+		cmds = append(cmds, fetchProfileCmd(m.client))
+
+	case sessaoRecusadaMsg:
+		m.erroSessao = msg.err
+		m.loading = false
+		cmds = append(cmds, m.notificar(sevErro, msg.err.Error()+" (Ctrl+R tenta de novo)"))
+
+	case segredoNaoSalvoMsg:
+		cmds = append(cmds,
+			m.notificar(sevAlerta, "A sessão renovada não foi guardada ("+msg.err.Error()+"); no próximo início será preciso entrar de novo"),
+			esperarSegredo(m.segredoFalhou))
+
+	case ticketsFalhouMsg:
+		f := &m.filas[msg.fila]
+		if msg.geracao != f.geracao {
+			break
+		}
+		m.loading = false
+		f.carregandoMais = false
+		f.atualizarTitulo(m.filtro)
+		var se *api.StatusError
+		switch {
+		case msg.poll:
+			// A carga da fila já avisou; a busca de novidades não repete o aviso a cada rodada
+		case errors.As(msg.err, &se) && se.StatusCode == http.StatusBadRequest && !m.filtro.IsZero():
+			// O servidor não aceitou o filtro: volta para a barra, com o motivo no lugar da ajuda
+			m.filtroErro = "o servidor recusou o filtro: " + msg.err.Error()
+			m.editandoFiltro = true
+			m.ajustarLayout()
+			cmds = append(cmds, m.filterInput.Focus())
+		default:
+			cmds = append(cmds, m.notificarErro(msg.err))
+		}
+
+	case conexaoFalhouMsg:
+		// Segue com o que estiver no cache; Ctrl+R (ou a próxima busca de novidades) tenta reconectar
+		if !m.offline {
+			cmds = append(cmds, m.notificarErro(msg.err))
+		}
+		m.offline = true
+		m.erroConexao = msg.err
+		m.loading = false
+		for i := range m.filas {
+			m.filas[i].carregandoMais = false
+			m.filas[i].atualizarTitulo(m.filtro)
+		}
+		m.ajustarLayout()
+
+	case perfilCarregadoMsg:
+		cmds = append(cmds, m.filaAtual().recarregar(m.client, m.filtro, m.aba))
+		cmds = append(cmds, m.retomarOutbox())
 
 	case ticketsLoadedMsg:
+		f := &m.filas[msg.fila]
+		if msg.geracao != f.geracao {
+			break // Resposta de uma consulta já substituída (ex: filtro trocado)
+		}
 		m.loading = false
-		items := make([]list.Item, len(msg))
-		for i, t := range msg {
-			items[i] = t
+		if m.offline {
+			// Conexão voltou: o que estava esperando na fila de envio sai agora
+			m.offline = false
+			m.ajustarLayout()
+			cmds = append(cmds, m.retomarOutbox())
+		}
+		if msg.poll {
+			cmd, novos := f.mesclarPagina(msg.page, m.filtro)
+			cmds = append(cmds, cmd)
+			if novos > 0 {
+				cmds = append(cmds, m.tocarSino())
+				cmds = append(cmds, m.notificar(sevInfo, fmt.Sprintf("%d chamado(s) novo(s) ou alterado(s) em %s", novos, f.nome)))
+			}
+		} else {
+			cmds = append(cmds, f.aplicarPagina(msg.page, m.filtro))
+		}
+		cmds = append(cmds, salvarPaginaCmd(m.cache, f.nome, msg.page, m.filtro.IsZero()))
+		cmds = append(cmds, m.reconciliarVistos(msg.page.Tickets))
+		cmds = append(cmds, m.conferirRespostas(msg.page.Tickets))
+		m.marcarOutbox()
+		m.marcarLeitura()
+		cmds = append(cmds, m.atualizarTituloJanela())
+
+	case pollTickMsg:
+		cmds = append(cmds, m.poll())
+
+	case novasRespostasMsg:
+		if v, ok := m.vistos[msg.ticketID]; !ok || v.DateMod == msg.dateMod {
+			break // Chamado aberto enquanto a contagem estava a caminho
+		}
+		if msg.novas > m.novasRespostas[msg.ticketID] {
+			cmds = append(cmds, m.tocarSino())
+			cmds = append(cmds, m.notificar(sevInfo, fmt.Sprintf("Nova resposta no chamado #%d", msg.ticketID)))
+		}
+		if msg.novas > 0 {
+			m.novasRespostas[msg.ticketID] = msg.novas
+			m.marcarLeitura()
+			cmds = append(cmds, m.atualizarTituloJanela())
 		}
-		m.list.SetItems(items)
 
 	case ticketActorsLoadedMsg:
 		if m.chamadoSelecionado != nil && m.chamadoSelecionado.ID == msg.ticketID {
+			if msg.err != nil {
+				cmds = append(cmds, m.notificar(sevAlerta, "Não foi possível carregar os atores: "+msg.err.Error()))
+				if m.chamadoSelecionado.Actors != nil {
+					break // Sem rede: mantém os atores do cache
+				}
+			}
 			m.chamadoSelecionado.Actors = msg.actors
 			m.renderChamadoDetalhes()
+			if msg.err == nil {
+				cmds = append(cmds, salvarAtoresCmd(m.cache, *m.chamadoSelecionado))
+			}
 		}
 
-	case ticketFollowupsLoadedMsg:
+	case ticketTimelineLoadedMsg:
 		if m.chamadoSelecionado != nil && m.chamadoSelecionado.ID == msg.ticketID {
 			m.refreshing = false // 2. Desativa o indicador quando chega
-
-			// Lógica de ordenação (mantém a que fizemos antes)
-			var lista []domain.TicketFollowup
-			if msg.followups == nil {
-				lista = []domain.TicketFollowup{}
-			} else {
-				lista = msg.followups
+			if msg.err != nil {
+				cmds = append(cmds, m.notificar(sevAlerta, "Não foi possível carregar o histórico: "+msg.err.Error()))
+				if m.chamadoSelecionado.Timeline != nil {
+					break // Sem rede: mantém a timeline do cache
+				}
 			}
-
-			sort.Slice(lista, func(i, j int) bool {
-				return lista[i].ID > lista[j].ID
-			})
-
-			m.chamadoSelecionado.Followups = lista
+			m.chamadoSelecionado.SetTimeline(msg.items)
 			m.renderChamadoDetalhes()
+			if msg.err == nil {
+				cmds = append(cmds, salvarTimelineCmd(m.cache, *m.chamadoSelecionado))
+				cmds = append(cmds, m.registrarVisto(*m.chamadoSelecionado))
+				cmds = append(cmds, m.atualizarTituloJanela())
+			}
 		}
 
-	case followupCreatedMsg:
-		if m.chamadoSelecionado != nil {
-			// Adiciona um feedback visual temporário se quiser, ou só recarrega
-			cmds = append(cmds, fetchFollowupsCmd(m.client, m.chamadoSelecionado.ID))
-		}
+	case outboxEnviadoMsg:
+		cmds = append(cmds, m.concluirEnvio(msg))
 
-	case assignedSuccessMsg:
-		// SUCESSO NA ATRIBUIÇÃO
+	case outboxRetryMsg:
+		cmds = append(cmds, m.enviarProximo())
+
+	case solutionReviewedMsg:
 		m.refreshing = false
-		if m.chamadoSelecionado != nil {
-			// Recarrega os Atores para mostrar o nome do técnico na tela imediatamente
-			cmds = append(cmds, fetchActorsCmd(m.client, m.chamadoSelecionado.ID))
+		if msg.aprovada {
+			cmds = append(cmds, m.notificar(sevSucesso, fmt.Sprintf("Solução aprovada: chamado #%d fechado", msg.ticketID)))
+		} else {
+			cmds = append(cmds, m.notificar(sevInfo, fmt.Sprintf("Solução recusada: chamado #%d reaberto", msg.ticketID)))
+		}
+		status := domain.StatusAssign // Recusa reabre o chamado
+		if msg.aprovada {
+			status = domain.StatusClosed
+		}
+		m.atualizarStatusLocal(msg.ticketID, status)
+		if m.chamadoSelecionado != nil && m.chamadoSelecionado.ID == msg.ticketID {
+			cmds = append(cmds, fetchTimelineCmd(m.detalhesCtx(), m.client, msg.ticketID))
 		}
 
+	case transferenciaMsg:
+		// Painel fechado no meio da transferência: continua drenando o canal até ela terminar
+		cmds = append(cmds, esperarTransferencia(msg.ch))
+
 	case errMsg:
-		m.err = msg
 		m.loading = false
-		return m, nil
+		m.refreshing = false
+		cmds = append(cmds, m.notificarErro(msg))
+
+	case avisoExpiradoMsg:
+		m.expirarAviso(msg.id)
+
+	case trocaPerfilMsg:
+		cmds = append(cmds, m.concluirTrocaPerfil(msg))
 
 	case spinner.TickMsg:
 		if m.loading {
@@ -336,21 +1165,49 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	// Lógica Padrão (Lista ou Viewport)
 	if m.chamadoSelecionado == nil {
+		f := m.filaAtual()
 		if !m.loading {
-			m.list, cmd = m.list.Update(msg)
+			f.list, cmd = f.list.Update(msg)
 			cmds = append(cmds, cmd)
+
+			// Scroll infinito: busca a próxima página quando o cursor chega perto do fim
+			if cmd := f.carregarMaisSePreciso(m.client, m.filtro, m.aba); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
 		}
 		// Enter para selecionar
 		if msg, ok := msg.(tea.KeyMsg); ok && msg.String() == "enter" && !m.loading {
-			if i, ok := m.list.SelectedItem().(domain.Chamado); ok {
+			if i, ok := f.chamadoSelecionadoNaLista(); ok {
+				for j := range m.filas {
+					m.filas[j].marcarLido(i.ID)
+				}
+				cmds = append(cmds, m.atualizarTituloJanela())
 				m.chamadoSelecionado = &i
+				m.vistoAnterior = nil
+				if v, ok := m.vistos[i.ID]; ok {
+					m.vistoAnterior = &v
+				}
 				// Limpa cache visual
 				m.chamadoSelecionado.Actors = nil
 				m.chamadoSelecionado.Followups = nil
+				m.chamadoSelecionado.Solutions = nil
+				m.chamadoSelecionado.Tasks = nil
+				m.chamadoSelecionado.Documents = nil
+				m.chamadoSelecionado.Timeline = nil
+
+				// Mostra na hora o que estiver no cache; a busca abaixo atualiza em seguida
+				if d, ok := m.cache.Details(i.ID); ok {
+					m.chamadoSelecionado.Actors = d.Actors
+					if d.Timeline != nil {
+						m.chamadoSelecionado.SetTimeline(d.Timeline)
+						m.refreshing = true
+					}
+				}
 				m.renderChamadoDetalhes()
 
-				cmds = append(cmds, fetchActorsCmd(m.client, i.ID))
-				cmds = append(cmds, fetchFollowupsCmd(m.client, i.ID))
+				m.ctxDetalhes, m.cancelDetalhes = context.WithCancel(context.Background())
+				cmds = append(cmds, fetchActorsCmd(m.ctxDetalhes, m.client, i.ID))
+				cmds = append(cmds, fetchTimelineCmd(m.ctxDetalhes, m.client, i.ID))
 			}
 		}
 	} else {
@@ -362,6 +1219,145 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// filaAtual devolve a aba ativa da lista principal
+func (m *model) filaAtual() *fila {
+	return &m.filas[m.aba]
+}
+
+// trocarAba ativa a fila i, buscando a primeira página se ela ainda não foi carregada
+func (m *model) trocarAba(i int) tea.Cmd {
+	if i < 0 || i >= len(m.filas) || i == m.aba {
+		return nil
+	}
+	m.aba = i
+	if f := m.filaAtual(); !f.carregada {
+		return f.recarregar(m.client, m.filtro, m.aba)
+	}
+	return nil
+}
+
+// recarregarFilas aplica o filtro atual: recarrega a aba ativa e invalida as demais
+func (m *model) recarregarFilas() tea.Cmd {
+	for i := range m.filas {
+		m.filas[i].carregada = false
+	}
+	return m.filaAtual().recarregar(m.client, m.filtro, m.aba)
+}
+
+// ajustarLayout reserva espaço para a barra de abas acima da lista e para as barras de filtro e de status abaixo dela
+func (m *model) ajustarLayout() {
+	h := m.height - 2 // Abas e barra de status
+	if m.barraFiltroVisivel() {
+		h -= 2
+	}
+	if m.offline {
+		h--
+	}
+	for i := range m.filas {
+		m.filas[i].list.SetHeight(h)
+	}
+}
+
+func (m model) barraFiltroVisivel() bool {
+	return m.editandoFiltro || !m.filtro.IsZero()
+}
+
+// renderBarraFiltro desenha a barra de filtro (edição ou resumo do filtro ativo)
+func (m model) renderBarraFiltro() string {
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	if m.editandoFiltro {
+		linha2 := hint.Render(api.FilterHelp)
+		if m.filtroErro != "" {
+			linha2 = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠ " + m.filtroErro)
+		}
+		return m.filterInput.View() + "\n" + linha2
+	}
+	return hint.Render("🔎 "+m.filterInput.Value()) + "\n" + hint.Render("[/] Editar filtro")
+}
+
+// temChamadosEmCache diz se alguma fila está mostrando chamados (do cache ou do servidor)
+func (m model) temChamadosEmCache() bool {
+	for _, f := range m.filas {
+		if len(f.list.Items()) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// renderAvisoOffline explica por que a lista pode estar desatualizada
+func (m model) renderAvisoOffline() string {
+	msg := "⚠ Offline — mostrando o cache local. [Ctrl+R] Tentar de novo"
+	if !m.temChamadosEmCache() {
+		msg = "⚠ Sem conexão com o GLPI. [Ctrl+R] Tentar de novo"
+	}
+	if m.erroConexao != nil {
+		msg += " • " + m.erroConexao.Error()
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("208")).MaxWidth(max(m.width, 20)).Render(msg)
+}
+
+// abrirDialogoSolucao mostra o diálogo de solução e carrega tipos/modelos em background
+func (m *model) abrirDialogoSolucao() tea.Cmd {
+	m.solucionando = true
+	m.dialogoSolucao = novoDialogoSolucao(m.width)
+	return tea.Batch(textarea.Blink, fetchSolutionOptionsCmd(m.client))
+}
+
+// statusLocal devolve o status do chamado como está na tela (ok = false se não estiver)
+func (m *model) statusLocal(ticketID int) (int, bool) {
+	if m.chamadoSelecionado != nil && m.chamadoSelecionado.ID == ticketID {
+		return m.chamadoSelecionado.Status.ID, true
+	}
+	for i := range m.filas {
+		for _, it := range m.filas[i].list.Items() {
+			if ic, ok := it.(itemChamado); ok && ic.ID == ticketID {
+				return ic.Status.ID, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// atualizarStatusLocal reflete a troca de status no chamado aberto e nas filas sem recarregar tudo
+func (m *model) atualizarStatusLocal(ticketID, status int) {
+	novo := domain.TicketStatus{ID: status, Name: domain.StatusLabel(status)}
+	if m.chamadoSelecionado != nil && m.chamadoSelecionado.ID == ticketID {
+		m.chamadoSelecionado.Status = novo
+		m.renderChamadoDetalhes()
+	}
+	for i := range m.filas {
+		for j, it := range m.filas[i].list.Items() {
+			if ic, ok := it.(itemChamado); ok && ic.ID == ticketID {
+				ic.Status = novo
+				m.filas[i].list.SetItem(j, ic)
+			}
+		}
+	}
+}
+
+// fecharDetalhes volta para a lista e cancela as requisições do chamado aberto
+func (m *model) fecharDetalhes() {
+	if m.cancelDetalhes != nil {
+		m.cancelDetalhes()
+		m.cancelDetalhes = nil
+	}
+	m.ctxDetalhes = nil
+	m.chamadoSelecionado = nil
+	m.vistoAnterior = nil
+	m.vendoDocumentos = false
+	m.aprovandoSolucao = 0
+	m.refreshing = false
+}
+
+// detalhesCtx devolve o contexto das requisições do chamado aberto
+func (m model) detalhesCtx() context.Context {
+	if m.ctxDetalhes == nil {
+		return context.Background()
+	}
+	return m.ctxDetalhes
+}
+
 // Helper para renderizar o conteúdo bonito no viewport
 func (m *model) renderChamadoDetalhes() {
 	if m.chamadoSelecionado == nil {
@@ -378,7 +1374,8 @@ func (m *model) renderChamadoDetalhes() {
 	// Cabeçalho
 	header := fmt.Sprintf("%s\n%s",
 		titleStyle.Render(fmt.Sprintf("#%d %s", c.ID, c.Name)),
-		infoStyle.Render(fmt.Sprintf("Aberto em: %s", c.GetFormattedDate())),
+		infoStyle.Render(fmt.Sprintf("Aberto em: %s • Status: ", c.GetFormattedDate()))+
+			lipgloss.NewStyle().Foreground(domain.StatusColor(c.Status.ID)).Render(domain.StatusLabel(c.Status.ID)),
 	)
 
 	// Atores
@@ -397,29 +1394,29 @@ func (m *model) renderChamadoDetalhes() {
 	// Conteúdo Principal (Descrição)
 	descriptionSection := fmt.Sprintf("%s\n%s",
 		dividerStyle.Render(strings.Repeat("─", m.viewport.Width)),
-		c.GetCleanContent(),
+		richtext.Render(c.Content, m.viewport.Width),
 	)
 
-	// Seção de Followups (Acompanhamentos)
-	var followupsSection string
-
-	if c.Followups == nil {
-		followupsSection = "\n\n" + infoStyle.Render("Carregando histórico...")
-	} else if len(c.Followups) > 0 {
-		var sb strings.Builder
-		sb.WriteString("\n\n" + titleStyle.Background(lipgloss.Color("#444")).Render(" 💬 Acompanhamentos ") + "\n")
+	// Linha do tempo unificada (acompanhamentos, tarefas, soluções, documentos, validações)
+	var timelineSection string
 
-		for _, f := range c.Followups {
-			// Estilo do cabeçalho do followup (Quem e Quando)
-			fHeader := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("#00D7D7")).Render(f.User.Name)
-			fDate := infoStyle.Render(f.GetFormattedDate())
-
-			sb.WriteString(fmt.Sprintf("\n%s em %s\n%s\n", fHeader, fDate, dividerStyle.Render(strings.Repeat("-", 20))))
-			sb.WriteString(fmt.Sprintf("%s\n", f.GetCleanContent()))
+	if c.Timeline == nil {
+		timelineSection = "\n\n" + infoStyle.Render("Carregando histórico...")
+	} else if len(c.Timeline) > 0 {
+		titulo := " 🕑 Linha do tempo "
+		if len(c.Tasks) > 0 {
+			titulo += "• Tempo total " + domain.FormatDuration(domain.TotalActionTime(c.Tasks)) + " "
 		}
-		followupsSection = sb.String()
+		timelineSection = "\n\n" + titleStyle.Background(lipgloss.Color("#444")).Render(titulo) + "\n" +
+			renderTimeline(c.Timeline, m.viewport.Width, m.limiteNovidades())
 	} else {
-		followupsSection = "\n\n" + infoStyle.Render("Nenhum acompanhamento registrado.")
+		timelineSection = "\n\n" + infoStyle.Render("Nenhum acompanhamento registrado.")
+	}
+
+	// Escritas deste chamado que ainda não chegaram ao servidor
+	if envios := m.envios(c.ID); len(envios) > 0 {
+		timelineSection += "\n\n" + titleStyle.Background(lipgloss.Color("208")).Render(" 📤 Fila de envio ") +
+			renderOutbox(envios, m.outboxEnviando, m.viewport.Width)
 	}
 
 	// Montagem Final
@@ -427,7 +1424,7 @@ func (m *model) renderChamadoDetalhes() {
 		header,
 		actorsInfo,
 		descriptionSection,
-		followupsSection,
+		timelineSection,
 	)
 
 	m.viewport.SetContent(content)
@@ -436,20 +1433,50 @@ func (m *model) renderChamadoDetalhes() {
 // --- VIEW (Renderização) ---
 
 func (m model) View() string {
-	if m.err != nil {
-		return fmt.Sprintf("\n  ❌ Erro: %v\n\n  Pressione Ctrl+C para sair.", m.err)
+	if m.vendoLog {
+		return m.viewLog()
 	}
+	// A barra de status fica sempre na última linha, qualquer que seja a tela
+	return m.viewTela() + "\n" + m.renderBarraStatus()
+}
 
+func (m model) viewTela() string {
 	if m.loading {
 		return fmt.Sprintf("\n %s Conectando ao GLPI...\n", m.spinner.View())
 	}
 
+	if m.criandoChamado {
+		return m.formChamado.view()
+	}
+
 	// Se estiver vendo detalhes
 	if m.chamadoSelecionado != nil {
 
 		// 1. Renderiza o conteúdo do chamado (Viewport)
 		viewContent := m.viewport.View()
 
+		if m.registrandoTarefa {
+			return fmt.Sprintf("%s\n%s", viewContent, m.formTarefa.view())
+		}
+
+		if m.solucionando {
+			return fmt.Sprintf("%s\n%s", viewContent, m.dialogoSolucao.view())
+		}
+
+		if m.mudandoStatus {
+			return fmt.Sprintf("%s\n%s", viewContent, m.seletorStatus.view())
+		}
+
+		if m.aprovandoSolucao != 0 {
+			pergunta := fmt.Sprintf("\nAprovar a solução e fechar o chamado #%d? [s] Sim • qualquer outra tecla cancela", m.chamadoSelecionado.ID)
+			return fmt.Sprintf("%s\n%s", viewContent, lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Bold(true).Render(pergunta))
+		}
+
+		if m.vendoDocumentos {
+			c := m.chamadoSelecionado
+			return fmt.Sprintf("%s\n%s", viewContent, m.painelDocs.view(c.Documents, c.Followups, m.pastaDownload))
+		}
+
 		// 2. Se estiver respondendo, desenha a caixa de texto embaixo
 		if m.responding {
 			borderColor := lipgloss.Color("205") // Rosa choque para destaque
@@ -466,7 +1493,10 @@ func (m model) View() string {
 			textareaView := boxStyle.Render(m.textarea.View())
 
 			// Dica de rodapé
-			help := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Ctrl+S: Enviar • Esc: Cancelar")
+			help := lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render("Ctrl+S: Enviar • Ctrl+E: Abrir no editor • Esc: Cancelar • Markdown suportado")
+			if m.erroEditor != "" {
+				help = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠ "+m.erroEditor) + "\n" + help
+			}
 
 			// Junta o viewport + caixa de texto + ajuda
 			return fmt.Sprintf("%s\n%s\n%s", viewContent, textareaView, help)
@@ -483,14 +1513,33 @@ func (m model) View() string {
 				Render("\nAtualizando histórico... aguarde.")
 		} else {
 			// Mostra os comandos normais
+			atribuir := ""
+			if _, err := domain.AssignStatus(m.chamadoSelecionado.Status.ID); err == nil {
+				atribuir = "[a] Atribuir a Mim • "
+			}
+			comandos := "[r] Responder • [E] Responder no editor • [u] Atualizar • " + atribuir + "[t] Tarefa • [s] Status • [S] Solucionar • [d] Documentos • [f] Anexar • [Esc] Voltar"
+			if len(m.envios(m.chamadoSelecionado.ID)) > 0 {
+				comandos += " • [p] Reenviar • [P] Descartar falhas"
+			}
 			footer = lipgloss.NewStyle().
 				Foreground(lipgloss.Color("240")).
-				Render("\n[r] Responder • [u] Atualizar • [a] Atribuir a Mim • [Esc] Voltar")
+				Render("\n" + comandos)
 		}
 
 		return fmt.Sprintf("%s\n%s", viewContent, footer)
 	}
 
 	// Tela de Lista Principal
-	return m.list.View()
+	abas := renderAbas(m.filas, m.aba) + m.renderPerfil()
+	if m.trocandoPerfil {
+		return abas + "\n" + m.seletorPerfil.view()
+	}
+	view := abas + "\n" + m.filas[m.aba].list.View()
+	if m.offline {
+		view += "\n" + m.renderAvisoOffline()
+	}
+	if m.barraFiltroVisivel() {
+		view += "\n" + m.renderBarraFiltro()
+	}
+	return view
 }
@@ -0,0 +1,406 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"glpi-tui/internal/api"
+	"glpi-tui/internal/cache"
+	"glpi-tui/internal/config"
+	"glpi-tui/internal/domain"
+	"glpi-tui/internal/fake"
+	"glpi-tui/internal/secrets"
+
+	"github.com/charmbracelet/bubbles/cursor"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+const (
+	// limiteCmd é quanto um comando pode demorar antes de o teste falhar: os do fake respondem
+	// na hora, mas o limite folgado cobre CI lento e -race sem perder resposta do backend
+	limiteCmd = 5 * time.Second
+	// esperaOuvinte é quanto se espera de um comando que só ouve um canal (esperarSegredo):
+	// ficar parado é o normal dele, então o silêncio não é erro
+	esperaOuvinte = 50 * time.Millisecond
+)
+
+// Comandos que só esperam o relógio: tea.Tick (spinner, expiração de avisos, reenvio, poll) e
+// o piscar do cursor. Todos os comandos de um tipo compartilham o código da closure, então o
+// ponteiro da função os identifica sem executá-los.
+var (
+	cmdTick   = ponteiro(tea.Tick(0, nil))
+	cmdPiscar = func() uintptr { c := cursor.New(); return ponteiro(c.Focus()) }()
+	cmdOuvir  = ponteiro(esperarSegredo(make(chan error)))
+)
+
+func ponteiro(cmd tea.Cmd) uintptr { return reflect.ValueOf(cmd).Pointer() }
+
+// novoBackend cria um GLPI em memória com dois chamados atribuídos ao usuário e um só do grupo
+func novoBackend() *fake.Backend {
+	b := fake.New(fake.Eu, []int{fake.Suporte.ID})
+	eu := domain.TicketActor{ID: fake.Eu.ID, Name: fake.Eu.Name, Type: "User", Role: "assigned"}
+	suporte := domain.TicketActor{ID: fake.Suporte.ID, Name: fake.Suporte.Name, Type: "Group", Role: "assigned"}
+	b.AddTicket(domain.Chamado{ID: 101, Name: "Impressora travando", Status: domain.TicketStatus{ID: domain.StatusAssign}, Priority: 3},
+		[]domain.TicketActor{eu}, nil)
+	b.AddTicket(domain.Chamado{ID: 102, Name: "Notebook novo", Status: domain.TicketStatus{ID: domain.StatusPlanned}, Priority: 2},
+		[]domain.TicketActor{eu}, nil)
+	b.AddTicket(domain.Chamado{ID: 103, Name: "Sem acesso à VPN", Status: domain.TicketStatus{ID: domain.StatusNew}, Priority: 4},
+		[]domain.TicketActor{suporte}, nil)
+	return b
+}
+
+func novoModel(b *fake.Backend) model {
+	return InitialModel(b, &config.Config{Profile: "teste", Theme: "dark"}, nil)
+}
+
+// executar roda o comando e devolve as mensagens que ele produziu, abrindo os tea.Batch.
+// Os de relógio são pulados; qualquer outro que passe de limiteCmd derruba o teste.
+func executar(t *testing.T, cmd tea.Cmd) []tea.Msg {
+	t.Helper()
+	if cmd == nil {
+		return nil
+	}
+	limite, ouvinte := limiteCmd, false
+	switch ponteiro(cmd) {
+	case cmdTick, cmdPiscar:
+		return nil
+	case cmdOuvir:
+		limite, ouvinte = esperaOuvinte, true
+	}
+	ch := make(chan tea.Msg, 1)
+	go func() { ch <- cmd() }()
+	select {
+	case msg := <-ch:
+		if batch, ok := msg.(tea.BatchMsg); ok {
+			var msgs []tea.Msg
+			for _, c := range batch {
+				msgs = append(msgs, executar(t, c)...)
+			}
+			return msgs
+		}
+		if msg == nil {
+			return nil
+		}
+		return []tea.Msg{msg}
+	case <-time.After(limite):
+		if !ouvinte {
+			t.Fatalf("comando não respondeu em %s", limiteCmd)
+		}
+		return nil
+	}
+}
+
+// processar entrega as mensagens ao Update, e as que os comandos devolverem, até a fila esvaziar
+func processar(t *testing.T, m model, cmd tea.Cmd) model {
+	t.Helper()
+	fila := executar(t, cmd)
+	for i := 0; len(fila) > 0; i++ {
+		if i > 200 {
+			t.Fatalf("Update não parou de gerar mensagens; última: %T", fila[0])
+		}
+		msg := fila[0]
+		fila = fila[1:]
+		if _, ok := msg.(tea.QuitMsg); ok {
+			t.Fatalf("o programa encerrou")
+		}
+		novo, c := m.Update(msg)
+		m = novo.(model)
+		fila = append(fila, executar(t, c)...)
+	}
+	return m
+}
+
+// logar faz o login e carrega o perfil e a primeira fila, como no Init
+func logar(t *testing.T, m model) model {
+	t.Helper()
+	return processar(t, m, performLoginCmd(m.client))
+}
+
+func idsDaFila(f *fila) []int {
+	var ids []int
+	for _, it := range f.list.Items() {
+		ids = append(ids, it.(itemChamado).ID)
+	}
+	return ids
+}
+
+func TestLoginCarregaPrimeiraFila(t *testing.T) {
+	m := logar(t, novoModel(novoBackend()))
+
+	if !m.logado || m.offline {
+		t.Fatalf("logado=%v offline=%v, esperado logado e online", m.logado, m.offline)
+	}
+	f := &m.filas[0]
+	if !f.carregada {
+		t.Fatalf("fila %q não foi carregada", f.nome)
+	}
+	ids := idsDaFila(f)
+	if len(ids) != 2 || !slices.Contains(ids, 101) || !slices.Contains(ids, 102) {
+		t.Errorf("fila %q = %v, esperado os chamados 101 e 102", f.nome, ids)
+	}
+	if f.totalChamados != 2 {
+		t.Errorf("totalChamados = %d, esperado 2", f.totalChamados)
+	}
+}
+
+func TestFalhaMostraAvisoESegueRodando(t *testing.T) {
+	b := novoBackend()
+	b.FailOn("GetTicketsPage", &api.StatusError{What: "listar chamados", StatusCode: 503})
+	m := logar(t, novoModel(b))
+
+	if !m.offline {
+		t.Errorf("esperado modo offline depois da falha")
+	}
+	if len(m.avisos) == 0 {
+		t.Fatalf("nenhum aviso depois da falha")
+	}
+	if a := m.avisos[len(m.avisos)-1]; a.sev != sevErro || !strings.Contains(a.texto, "503") {
+		t.Errorf("aviso = %+v, esperado erro com o status 503", a)
+	}
+
+	// O servidor volta: Ctrl+R recarrega a fila e sai do modo offline
+	b.FailOn("GetTicketsPage", nil)
+	novo, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = processar(t, novo.(model), cmd)
+	if m.offline {
+		t.Errorf("continuou offline depois de recarregar")
+	}
+	if ids := idsDaFila(&m.filas[0]); len(ids) != 2 {
+		t.Errorf("fila depois de recarregar = %v, esperado 2 chamados", ids)
+	}
+}
+
+func TestOutboxEnviaERemove(t *testing.T) {
+	b := novoBackend()
+	m := logar(t, novoModel(b))
+
+	m = processar(t, m, m.enfileirar(cache.OutboxEntry{Kind: cache.OutboxFollowup, TicketID: 101, Content: "Troquei o toner."}))
+
+	if len(m.outbox) != 0 {
+		t.Fatalf("outbox = %+v, esperado vazia depois do envio", m.outbox)
+	}
+	if m.outboxEnviando != 0 {
+		t.Errorf("outboxEnviando = %d, esperado 0", m.outboxEnviando)
+	}
+	timeline, err := b.GetTicketTimelineContext(t.Context(), 101)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timeline) != 1 || timeline[0].Followup == nil || !strings.Contains(timeline[0].Followup.Content, "Troquei o toner.") {
+		t.Errorf("timeline = %+v, esperado o acompanhamento enviado", timeline)
+	}
+	if a := m.avisos[len(m.avisos)-1]; a.sev != sevSucesso {
+		t.Errorf("último aviso = %+v, esperado a confirmação do envio", a)
+	}
+}
+
+func TestOutboxConfereEnvioIncertoAntesDeReenviar(t *testing.T) {
+	b := novoBackend()
+	m := logar(t, novoModel(b))
+
+	// O prazo estoura sem resposta: não dá para saber se o acompanhamento foi gravado
+	b.FailOn("CreateTicketFollowup", fmt.Errorf("erro de conexão ao criar followup: %w", context.DeadlineExceeded))
+	m = processar(t, m, m.enfileirar(cache.OutboxEntry{Kind: cache.OutboxFollowup, TicketID: 101, Content: "Troquei o **toner**."}))
+	if len(m.outbox) != 1 || !m.outbox[0].Uncertain {
+		t.Fatalf("outbox = %+v, esperado uma entrada incerta", m.outbox)
+	}
+
+	// O servidor tinha gravado: a nova tentativa encontra o acompanhamento e não repete o envio
+	b.FailOn("CreateTicketFollowup", nil)
+	if err := b.CreateTicketFollowup(101, "Troquei o **toner**."); err != nil {
+		t.Fatal(err)
+	}
+	m = processar(t, m, m.retomarOutbox())
+	if len(m.outbox) != 0 {
+		t.Fatalf("outbox = %+v, esperado vazia depois da conferência", m.outbox)
+	}
+	timeline, err := b.GetTicketTimelineContext(t.Context(), 101)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(timeline) != 1 {
+		t.Errorf("%d itens na timeline, esperado 1 (sem duplicar)", len(timeline))
+	}
+}
+
+func TestOutboxFalhaSeguraOMesmoChamado(t *testing.T) {
+	b := novoBackend()
+	m := logar(t, novoModel(b))
+
+	b.FailOn("CreateTicketFollowup", &api.StatusError{What: "criar followup", StatusCode: 403})
+	m = processar(t, m, m.enfileirar(cache.OutboxEntry{Kind: cache.OutboxFollowup, TicketID: 101, Content: "Primeiro"}))
+	b.FailOn("CreateTicketFollowup", nil)
+	m = processar(t, m, m.enfileirar(cache.OutboxEntry{Kind: cache.OutboxStatus, TicketID: 101, ToStatus: domain.StatusPending}))
+	m = processar(t, m, m.enfileirar(cache.OutboxEntry{Kind: cache.OutboxFollowup, TicketID: 102, Content: "Outro chamado"}))
+
+	if len(m.outbox) != 2 || !m.outbox[0].Failed || m.outbox[1].Kind != cache.OutboxStatus || m.outbox[1].Attempts != 0 {
+		t.Fatalf("outbox = %+v, esperado a resposta recusada e a troca de status parada atrás dela", m.outbox)
+	}
+	if ch, _ := b.GetTicketContext(t.Context(), 101); ch.Status.ID != domain.StatusAssign {
+		t.Errorf("status do 101 = %d, a troca passou na frente da resposta recusada", ch.Status.ID)
+	}
+	if timeline, _ := b.GetTicketTimelineContext(t.Context(), 102); len(timeline) != 1 {
+		t.Errorf("o envio de outro chamado ficou parado: timeline do 102 = %+v", timeline)
+	}
+
+	// Reenviar o chamado libera a fila na ordem original
+	m = processar(t, m, m.reenviarChamado(101))
+	if len(m.outbox) != 0 {
+		t.Fatalf("outbox = %+v, esperado vazia depois do reenvio", m.outbox)
+	}
+	if ch, _ := b.GetTicketContext(t.Context(), 101); ch.Status.ID != domain.StatusPending {
+		t.Errorf("status do 101 = %d, esperado pendente", ch.Status.ID)
+	}
+}
+
+func TestOutboxLeStatusDeOrigemNoEnvio(t *testing.T) {
+	b := novoBackend()
+	m := logar(t, novoModel(b))
+
+	// Duas trocas seguidas pedidas com a tela ainda mostrando "em atendimento": com o status de
+	// origem da tela, a segunda (em atendimento → em atendimento) seria recusada
+	m.outboxEnviando = 1 // Segura a fila até as duas estarem gravadas
+	m.enfileirar(cache.OutboxEntry{Kind: cache.OutboxStatus, TicketID: 101, ToStatus: domain.StatusPending})
+	m.enfileirar(cache.OutboxEntry{Kind: cache.OutboxStatus, TicketID: 101, ToStatus: domain.StatusAssign})
+	m.outboxEnviando = 0
+	m = processar(t, m, m.enviarProximo())
+
+	if len(m.outbox) != 0 {
+		t.Fatalf("outbox = %+v, esperado vazia", m.outbox)
+	}
+	if ch, _ := b.GetTicketContext(t.Context(), 101); ch.Status.ID != domain.StatusAssign {
+		t.Errorf("status do 101 = %d, esperado em atendimento", ch.Status.ID)
+	}
+}
+
+func TestFiltroRecusadoVoltaParaABarra(t *testing.T) {
+	b := novoBackend()
+	m := logar(t, novoModel(b))
+
+	b.FailOn("GetTicketsPage", &api.StatusError{What: "listar chamados", StatusCode: 400, Body: "ERROR_RSQL"})
+	m.filtro = api.TicketFilter{Text: "impressora"}
+	m = processar(t, m, m.recarregarFilas())
+
+	if m.offline {
+		t.Errorf("filtro recusado não é falha de conexão")
+	}
+	if !m.editandoFiltro || !strings.Contains(m.filtroErro, "ERROR_RSQL") {
+		t.Errorf("editandoFiltro=%v filtroErro=%q, esperado a barra aberta com o erro", m.editandoFiltro, m.filtroErro)
+	}
+	if m.filas[0].carregandoMais {
+		t.Errorf("a fila ficou carregando")
+	}
+}
+
+func TestLoginRecusadoNaoFicaOffline(t *testing.T) {
+	b := novoBackend()
+	b.FailOn("Login", &api.StatusError{What: "token", StatusCode: 400, Body: `{"error":"invalid_grant"}`})
+	m := logar(t, novoModel(b))
+
+	if m.offline || m.logado {
+		t.Errorf("offline=%v logado=%v, esperado nem offline nem logado", m.offline, m.logado)
+	}
+	if m.erroSessao == nil || len(m.avisos) != 1 || m.avisos[0].sev != sevErro {
+		t.Fatalf("erroSessao=%v avisos=%+v, esperado um aviso de erro", m.erroSessao, m.avisos)
+	}
+
+	// A busca de novidades não tenta o login de novo; o Ctrl+R tenta
+	m = processar(t, m, m.poll())
+	if len(m.avisos) != 1 {
+		t.Errorf("avisos = %+v, a busca de novidades repetiu o login", m.avisos)
+	}
+	b.FailOn("Login", nil)
+	novo, cmd := m.Update(tea.KeyMsg{Type: tea.KeyCtrlR})
+	m = processar(t, novo.(model), cmd)
+	if !m.logado || m.erroSessao != nil || len(idsDaFila(&m.filas[0])) != 2 {
+		t.Errorf("logado=%v erroSessao=%v, esperado logado e a fila carregada depois do Ctrl+R", m.logado, m.erroSessao)
+	}
+}
+
+func TestSessaoNaoGuardadaViraAviso(t *testing.T) {
+	falhas := make(chan error, 1)
+	m := InitialModel(novoBackend(), &config.Config{Profile: "teste", Theme: "dark"}, nil, WithSaveErrors(falhas))
+
+	falhas <- secrets.ErrPromptDisabled
+	m = processar(t, m, esperarSegredo(m.segredoFalhou))
+
+	if len(m.avisos) != 1 || m.avisos[0].sev != sevAlerta || !strings.Contains(m.avisos[0].texto, "não foi guardada") {
+		t.Fatalf("avisos = %+v, esperado um alerta de sessão não guardada", m.avisos)
+	}
+}
+
+func TestAtribuirNaoReabreChamado(t *testing.T) {
+	b := novoBackend()
+	m := logar(t, novoModel(b))
+
+	// Na tela: solucionado não aceita o [a]
+	m.chamadoSelecionado = &domain.Chamado{ID: 101, Status: domain.TicketStatus{ID: domain.StatusSolved}}
+	novo, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("a")})
+	m = processar(t, novo.(model), cmd)
+	if len(m.outbox) != 0 || len(m.avisos) == 0 || m.avisos[len(m.avisos)-1].sev != sevAlerta {
+		t.Fatalf("outbox=%+v avisos=%+v, esperado só um alerta", m.outbox, m.avisos)
+	}
+	m.fecharDetalhes()
+
+	// No servidor: fechado enquanto a atribuição esperava na fila
+	if err := b.ChangeTicketStatus(101, 0, domain.StatusAssign, domain.StatusClosed); err != nil {
+		t.Fatal(err)
+	}
+	m = processar(t, m, m.enfileirar(cache.OutboxEntry{Kind: cache.OutboxAssign, TicketID: 101}))
+	if ch, _ := b.GetTicketContext(t.Context(), 101); ch.Status.ID != domain.StatusClosed {
+		t.Errorf("status do 101 = %d, a atribuição reabriu o chamado", ch.Status.ID)
+	}
+	if len(m.outbox) != 1 || !m.outbox[0].Failed {
+		t.Errorf("outbox = %+v, esperado a atribuição recusada", m.outbox)
+	}
+
+	// Um chamado novo passa a atribuído
+	m = processar(t, m, m.enfileirar(cache.OutboxEntry{Kind: cache.OutboxAssign, TicketID: 103}))
+	if ch, _ := b.GetTicketContext(t.Context(), 103); ch.Status.ID != domain.StatusAssign {
+		t.Errorf("status do 103 = %d, esperado atribuído", ch.Status.ID)
+	}
+}
+
+func TestAprovarSolucaoPedeConfirmacao(t *testing.T) {
+	b := novoBackend()
+	m := logar(t, novoModel(b))
+	if err := b.CreateTicketSolution(101, 0, api.SolutionInput{Content: "Toner trocado."}); err != nil {
+		t.Fatal(err)
+	}
+	ch, _ := b.GetTicketContext(t.Context(), 101)
+	timeline, _ := b.GetTicketTimelineContext(t.Context(), 101)
+	ch.SetTimeline(timeline)
+	m.chamadoSelecionado = &ch
+
+	tecla := func(m model, s string) model {
+		novo, cmd := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+		return processar(t, novo.(model), cmd)
+	}
+	statusNoServidor := func() int {
+		ch, _ := b.GetTicketContext(t.Context(), 101)
+		return ch.Status.ID
+	}
+
+	// "A" só pergunta; "n" desiste
+	m = tecla(m, "A")
+	if m.aprovandoSolucao == 0 || statusNoServidor() != domain.StatusSolved {
+		t.Fatalf("aprovandoSolucao=%d status=%d, esperado a pergunta sem aprovar", m.aprovandoSolucao, statusNoServidor())
+	}
+	if !strings.Contains(m.View(), "Aprovar a solução") {
+		t.Errorf("a pergunta não aparece na tela")
+	}
+	m = tecla(m, "n")
+	if m.aprovandoSolucao != 0 || statusNoServidor() != domain.StatusSolved {
+		t.Fatalf("aprovandoSolucao=%d status=%d, esperado desistir sem aprovar", m.aprovandoSolucao, statusNoServidor())
+	}
+
+	m = tecla(tecla(m, "A"), "s")
+	if statusNoServidor() != domain.StatusClosed {
+		t.Errorf("status = %d, esperado fechado depois de confirmar", statusNoServidor())
+	}
+}
@@ -0,0 +1,63 @@
+package tui
+
+import (
+	"glpi-tui/internal/api"
+	"glpi-tui/internal/cache"
+	"glpi-tui/internal/domain"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Gravação no cache local em background. Falhas são ignoradas: o cache é só um atalho,
+// o servidor continua sendo a fonte da verdade.
+
+// salvarPaginaCmd guarda os chamados da página e, na primeira página sem filtro, a ordem da fila
+func salvarPaginaCmd(store *cache.Store, fila string, page api.TicketPage, semFiltro bool) tea.Cmd {
+	if store == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		_ = store.SaveTickets(page.Tickets)
+		if page.Start == 0 && semFiltro {
+			ids := make([]int, len(page.Tickets))
+			for i, t := range page.Tickets {
+				ids[i] = t.ID
+			}
+			_ = store.SaveQueue(fila, ids, page.Total)
+		}
+		return nil
+	}
+}
+
+// salvarAtoresCmd guarda os atores do chamado aberto
+func salvarAtoresCmd(store *cache.Store, c domain.Chamado) tea.Cmd {
+	if store == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		_ = store.SaveActors(c.ID, c.DateMod, c.Actors)
+		return nil
+	}
+}
+
+// salvarTimelineCmd guarda a timeline do chamado aberto
+func salvarTimelineCmd(store *cache.Store, c domain.Chamado) tea.Cmd {
+	if store == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		_ = store.SaveTimeline(c.ID, c.DateMod, c.Timeline)
+		return nil
+	}
+}
+
+// salvarVistoCmd guarda o que o usuário já leu do chamado
+func salvarVistoCmd(store *cache.Store, ticketID int, seen cache.Seen) tea.Cmd {
+	if store == nil {
+		return nil
+	}
+	return func() tea.Msg {
+		_ = store.MarkSeen(ticketID, seen)
+		return nil
+	}
+}
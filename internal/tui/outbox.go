@@ -0,0 +1,359 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"glpi-tui/internal/api"
+	"glpi-tui/internal/cache"
+	"glpi-tui/internal/domain"
+	"glpi-tui/internal/markdown"
+	"glpi-tui/internal/richtext"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Fila de envio (outbox): respostas, atribuições e trocas de status são gravadas no cache
+// antes de irem para o servidor. Falhas de rede ficam pendentes e são reenviadas com espera
+// crescente; recusas do servidor ficam marcadas como falha até o usuário reenviar ou descartar.
+// Os envios saem um de cada vez, na ordem em que foram feitos. Um prazo estourado depois de a
+// requisição sair não diz se o servidor a aplicou: respostas e trocas de status nessa situação
+// ficam marcadas como incertas e são conferidas no servidor antes de qualquer reenvio.
+
+const (
+	reenvioInicial = 5 * time.Second
+	reenvioMaximo  = 5 * time.Minute
+)
+
+// outboxEnviadoMsg traz o resultado do envio de uma entrada da fila
+type outboxEnviadoMsg struct {
+	id  uint64
+	err error
+}
+
+// outboxRetryMsg acorda a fila quando chega a hora de tentar de novo
+type outboxRetryMsg struct{}
+
+// enviarOutboxCmd executa a escrita guardada na entrada. Se um envio anterior ficou incerto,
+// confere primeiro se ele já foi aplicado, para não duplicar a resposta.
+func enviarOutboxCmd(c api.Backend, e cache.OutboxEntry) tea.Cmd {
+	return func() tea.Msg {
+		if e.Uncertain {
+			aplicado, err := jaAplicado(c, e)
+			if err != nil {
+				return outboxEnviadoMsg{id: e.ID, err: fmt.Errorf("conferindo envio anterior: %w", err)}
+			}
+			if aplicado {
+				return outboxEnviadoMsg{id: e.ID}
+			}
+		}
+
+		var err error
+		switch e.Kind {
+		case cache.OutboxFollowup:
+			err = c.CreateTicketFollowup(e.TicketID, e.Content)
+		case cache.OutboxAssign:
+			err = c.AssignTicketViaUpdate(e.TicketID, e.EntityID)
+		case cache.OutboxStatus:
+			err = mudarStatus(c, e)
+		default:
+			err = fmt.Errorf("tipo de envio desconhecido: %q", e.Kind)
+		}
+		return outboxEnviadoMsg{id: e.ID, err: err}
+	}
+}
+
+// mudarStatus lê o status atual na hora do envio: entradas anteriores da fila, ou outra pessoa,
+// podem tê-lo mudado desde que a troca foi pedida. Se o chamado já estiver no status pedido
+// (inclusive por um envio anterior que ficou sem resposta), não há o que enviar.
+func mudarStatus(c api.Backend, e cache.OutboxEntry) error {
+	t, err := c.GetTicketContext(context.Background(), e.TicketID)
+	if err != nil {
+		return err
+	}
+	if t.Status.ID == e.ToStatus {
+		return nil
+	}
+	return c.ChangeTicketStatus(e.TicketID, e.EntityID, t.Status.ID, e.ToStatus)
+}
+
+// jaAplicado procura no servidor o efeito de um envio incerto. Só o acompanhamento precisa
+// disso: a troca de status já confere o status atual antes de enviar (veja mudarStatus).
+func jaAplicado(c api.Backend, e cache.OutboxEntry) (bool, error) {
+	if e.Kind != cache.OutboxFollowup {
+		return false, nil
+	}
+	followups, err := c.GetTicketFollowupsContext(context.Background(), e.TicketID)
+	if err != nil {
+		return false, err
+	}
+	texto := textoNormalizado(markdown.ToHTML(e.Content))
+	for _, f := range followups {
+		if f.User.ID == c.MyUserID() && textoNormalizado(f.Content) == texto {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// textoNormalizado compara HTML pelo texto: o servidor pode reescrever as tags ao sanitizar
+func textoNormalizado(html string) string {
+	return strings.Join(strings.Fields(richtext.PlainText(html)), " ")
+}
+
+// incertoSeAmbiguo diz se a falha deixa em dúvida se a escrita foi aplicada. Só importa para o
+// que duplicaria ao repetir: a atribuição ao próprio usuário pode ser repetida sem efeito.
+func incertoSeAmbiguo(e cache.OutboxEntry, err error) bool {
+	return (e.Kind == cache.OutboxFollowup || e.Kind == cache.OutboxStatus) && api.IsAmbiguous(err)
+}
+
+// atrasoReenvio dobra a espera a cada tentativa: 5s, 10s, 20s... até 5 minutos
+func atrasoReenvio(tentativas int) time.Duration {
+	d := reenvioInicial
+	for i := 1; i < tentativas && d < reenvioMaximo; i++ {
+		d *= 2
+	}
+	return min(d, reenvioMaximo)
+}
+
+// enfileirar grava a escrita na fila e dispara o envio se nada estiver em andamento.
+// Sem cache a entrada fica só na memória: ainda é reenviada, mas se perde ao fechar o programa.
+func (m *model) enfileirar(e cache.OutboxEntry) tea.Cmd {
+	e.Created = time.Now()
+	salva, err := m.cache.Enqueue(e)
+	if err != nil {
+		m.outboxSeq++
+		salva = e
+		salva.ID = 1<<32 + m.outboxSeq // Fora da faixa das sequências do bbolt
+	}
+	m.outbox = append(m.outbox, salva)
+	m.marcarOutbox()
+	m.renderChamadoDetalhes()
+	return m.enviarProximo()
+}
+
+// enviarProximo dispara a entrada mais antiga que já pode ser tentada. Uma entrada recusada
+// ou esperando nova tentativa segura as seguintes do mesmo chamado, para não inverter a ordem;
+// as de outros chamados seguem. Só envia com o perfil carregado: a atribuição depende do ID do usuário.
+func (m *model) enviarProximo() tea.Cmd {
+	if m.outboxEnviando != 0 || m.client.MyUserID() == 0 {
+		return nil
+	}
+	agora := time.Now()
+	bloqueados := map[int]bool{}
+	for _, e := range m.outbox {
+		if bloqueados[e.TicketID] {
+			continue
+		}
+		if e.Failed || e.NextTry.After(agora) {
+			bloqueados[e.TicketID] = true
+			continue
+		}
+		m.outboxEnviando = e.ID
+		return enviarOutboxCmd(m.client, e)
+	}
+	return nil
+}
+
+// retomarOutbox reenvia na hora tudo o que esperava a conexão voltar
+func (m *model) retomarOutbox() tea.Cmd {
+	for i := range m.outbox {
+		m.outbox[i].NextTry = time.Time{}
+	}
+	return m.enviarProximo()
+}
+
+// concluirEnvio trata o resultado de um envio e segue para o próximo da fila
+func (m *model) concluirEnvio(msg outboxEnviadoMsg) tea.Cmd {
+	m.outboxEnviando = 0
+	i := m.indiceOutbox(msg.id)
+	if i < 0 {
+		return m.enviarProximo()
+	}
+
+	var cmds []tea.Cmd
+	e := &m.outbox[i]
+	switch {
+	case msg.err == nil:
+		_ = m.cache.RemoveOutbox(e.ID)
+		feito := *e
+		m.outbox = slices.Delete(m.outbox, i, i+1)
+		cmds = append(cmds, m.aplicarEnvio(feito))
+		cmds = append(cmds, m.notificar(sevSucesso, fmt.Sprintf("Envio confirmado no chamado #%d: %s", feito.TicketID, descreverEnvio(feito))))
+
+	case api.IsTemporary(msg.err):
+		e.Attempts++
+		e.LastError = msg.err.Error()
+		e.NextTry = time.Now().Add(atrasoReenvio(e.Attempts))
+		e.Uncertain = e.Uncertain || incertoSeAmbiguo(*e, msg.err)
+		_ = m.cache.UpdateOutbox(*e)
+		cmds = append(cmds, tea.Tick(time.Until(e.NextTry), func(time.Time) tea.Msg {
+			return outboxRetryMsg{}
+		}))
+		texto := fmt.Sprintf("Envio adiado no chamado #%d (%s), nova tentativa às %s: %v",
+			e.TicketID, descreverEnvio(*e), e.NextTry.Format("15:04:05"), msg.err)
+		if e.Uncertain {
+			texto = fmt.Sprintf("Sem confirmação do envio no chamado #%d (%s): às %s o servidor é consultado antes de reenviar: %v",
+				e.TicketID, descreverEnvio(*e), e.NextTry.Format("15:04:05"), msg.err)
+		}
+		cmds = append(cmds, m.notificar(sevAlerta, texto))
+
+	default:
+		// O servidor recusou (permissão, transição inválida...): repetir não adianta
+		e.Attempts++
+		e.LastError = msg.err.Error()
+		e.Failed = true
+		_ = m.cache.UpdateOutbox(*e)
+		cmds = append(cmds, m.notificar(sevErro, fmt.Sprintf("O servidor recusou o envio no chamado #%d (%s): %v",
+			e.TicketID, descreverEnvio(*e), msg.err)))
+	}
+
+	m.marcarOutbox()
+	m.renderChamadoDetalhes()
+	cmds = append(cmds, m.enviarProximo())
+	return tea.Batch(cmds...)
+}
+
+// aplicarEnvio reflete na tela uma escrita confirmada pelo servidor
+func (m *model) aplicarEnvio(e cache.OutboxEntry) tea.Cmd {
+	m.escritasProprias[e.TicketID] = true
+	aberto := m.chamadoSelecionado != nil && m.chamadoSelecionado.ID == e.TicketID
+	switch e.Kind {
+	case cache.OutboxFollowup:
+		if aberto {
+			return fetchTimelineCmd(m.detalhesCtx(), m.client, e.TicketID)
+		}
+	case cache.OutboxAssign:
+		if from, ok := m.statusLocal(e.TicketID); ok {
+			if to, err := domain.AssignStatus(from); err == nil {
+				m.atualizarStatusLocal(e.TicketID, to)
+			}
+		}
+		if aberto {
+			// Recarrega os atores para mostrar o nome do técnico na tela
+			return fetchActorsCmd(m.detalhesCtx(), m.client, e.TicketID)
+		}
+	case cache.OutboxStatus:
+		m.atualizarStatusLocal(e.TicketID, e.ToStatus)
+	}
+	return nil
+}
+
+// reenviarChamado libera na hora as entradas do chamado, inclusive as que falharam
+func (m *model) reenviarChamado(ticketID int) tea.Cmd {
+	for i := range m.outbox {
+		e := &m.outbox[i]
+		if e.TicketID != ticketID || e.ID == m.outboxEnviando {
+			continue
+		}
+		e.Failed = false
+		e.NextTry = time.Time{}
+		_ = m.cache.UpdateOutbox(*e)
+	}
+	m.marcarOutbox()
+	m.renderChamadoDetalhes()
+	return m.enviarProximo()
+}
+
+// descartarFalhas apaga as entradas do chamado que o servidor recusou
+func (m *model) descartarFalhas(ticketID int) {
+	m.outbox = slices.DeleteFunc(m.outbox, func(e cache.OutboxEntry) bool {
+		if e.TicketID != ticketID || !e.Failed {
+			return false
+		}
+		_ = m.cache.RemoveOutbox(e.ID)
+		return true
+	})
+	m.marcarOutbox()
+	m.renderChamadoDetalhes()
+}
+
+func (m model) indiceOutbox(id uint64) int {
+	return slices.IndexFunc(m.outbox, func(e cache.OutboxEntry) bool { return e.ID == id })
+}
+
+// envios devolve as entradas da fila de um chamado
+func (m model) envios(ticketID int) []cache.OutboxEntry {
+	var out []cache.OutboxEntry
+	for _, e := range m.outbox {
+		if e.TicketID == ticketID {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// marcarOutbox atualiza os indicadores de envio pendente/falho nos itens de todas as filas
+func (m *model) marcarOutbox() {
+	pendente := map[int]bool{}
+	falhou := map[int]bool{}
+	for _, e := range m.outbox {
+		if e.Failed {
+			falhou[e.TicketID] = true
+		} else {
+			pendente[e.TicketID] = true
+		}
+	}
+	for i := range m.filas {
+		for j, it := range m.filas[i].list.Items() {
+			ic, ok := it.(itemChamado)
+			if !ok || (ic.pendente == pendente[ic.ID] && ic.falhou == falhou[ic.ID]) {
+				continue
+			}
+			ic.pendente, ic.falhou = pendente[ic.ID], falhou[ic.ID]
+			m.filas[i].list.SetItem(j, ic)
+		}
+	}
+}
+
+// descreverEnvio resume a escrita guardada na entrada
+func descreverEnvio(e cache.OutboxEntry) string {
+	switch e.Kind {
+	case cache.OutboxFollowup:
+		return "Resposta"
+	case cache.OutboxAssign:
+		return "Atribuição a mim"
+	case cache.OutboxStatus:
+		return "Status → " + domain.StatusLabel(e.ToStatus)
+	}
+	return e.Kind
+}
+
+// renderOutbox lista nos detalhes o que ainda não chegou ao servidor
+func renderOutbox(envios []cache.OutboxEntry, enviando uint64, width int) string {
+	pendenteStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("208"))
+	falhaStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("196"))
+	infoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	corpoStyle := lipgloss.NewStyle().Width(max(20, width-4)).PaddingLeft(2)
+
+	var sb strings.Builder
+	for _, e := range envios {
+		var linha string
+		switch {
+		case e.Failed:
+			linha = falhaStyle.Render("✖ " + descreverEnvio(e) + " — recusado pelo servidor")
+		case e.ID == enviando:
+			linha = pendenteStyle.Render("⏳ " + descreverEnvio(e) + " — enviando...")
+		case e.Uncertain:
+			linha = pendenteStyle.Render(fmt.Sprintf("❔ %s — sem confirmação, conferindo no servidor às %s",
+				descreverEnvio(e), e.NextTry.Format("15:04:05")))
+		case e.Attempts > 0:
+			linha = pendenteStyle.Render(fmt.Sprintf("⏳ %s — %d tentativa(s), próxima às %s",
+				descreverEnvio(e), e.Attempts, e.NextTry.Format("15:04:05")))
+		default:
+			linha = pendenteStyle.Render("⏳ " + descreverEnvio(e) + " — aguardando envio")
+		}
+		sb.WriteString("\n" + linha)
+		if e.LastError != "" {
+			sb.WriteString("\n" + corpoStyle.Inherit(infoStyle).Render(e.LastError))
+		}
+		if e.Content != "" {
+			sb.WriteString("\n" + corpoStyle.Render(e.Content))
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,177 @@
+package tui
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"glpi-tui/internal/config"
+	"glpi-tui/internal/secrets"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Troca de perfil (Ctrl+P na lista): a configuração do perfil escolhido é carregada e validada
+// aqui; se estiver tudo certo a TUI encerra devolvendo a configuração nova e o main abre de
+// novo com outro client e o cache daquele servidor, sem sair do programa.
+
+// trocaPerfilMsg traz a configuração do perfil escolhido, já com os segredos resolvidos
+type trocaPerfilMsg struct {
+	cfg *config.Config
+	err error
+}
+
+// carregarPerfil roda com o terminal liberado (tea.Exec): o arquivo cifrado de segredos pode
+// precisar pedir a frase-senha
+type carregarPerfil struct {
+	nome string
+	cfg  *config.Config
+}
+
+func (c *carregarPerfil) Run() (err error) {
+	// Fora da tela alternativa o terminal é nosso: a frase-senha do arquivo cifrado pode ser pedida
+	secrets.AllowPrompt(true)
+	defer secrets.AllowPrompt(false)
+	c.cfg, err = config.LoadProfile(c.nome)
+	return err
+}
+
+func (*carregarPerfil) SetStdin(io.Reader)  {}
+func (*carregarPerfil) SetStdout(io.Writer) {}
+func (*carregarPerfil) SetStderr(io.Writer) {}
+
+// seletorPerfil é o menu com os perfis do arquivo de configuração
+type seletorPerfil struct {
+	atual  string
+	nomes  []string
+	urls   map[string]string
+	cursor int
+}
+
+func novoSeletorPerfil(arq *config.File, atual string) seletorPerfil {
+	s := seletorPerfil{atual: atual, nomes: arq.Names(), urls: map[string]string{}}
+	for i, nome := range s.nomes {
+		s.urls[nome] = arq.Profiles[nome].URL
+		if nome == atual {
+			s.cursor = i
+		}
+	}
+	return s
+}
+
+// update move o cursor; devolve o perfil escolhido quando o usuário confirma com Enter
+func (s seletorPerfil) update(msg tea.KeyMsg) (seletorPerfil, string) {
+	switch msg.String() {
+	case "up", "k":
+		if s.cursor > 0 {
+			s.cursor--
+		}
+	case "down", "j":
+		if s.cursor < len(s.nomes)-1 {
+			s.cursor++
+		}
+	case "enter":
+		if len(s.nomes) > 0 {
+			return s, s.nomes[s.cursor]
+		}
+	}
+	return s, ""
+}
+
+func (s seletorPerfil) view() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("69")).
+		Padding(0, 1).
+		MarginTop(1)
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	var sb strings.Builder
+	sb.WriteString("Trocar de perfil\n")
+	for i, nome := range s.nomes {
+		linha := nome
+		if nome == s.atual {
+			linha += " (atual)"
+		}
+		linha += "  " + hint.Render(s.urls[nome])
+		if i == s.cursor {
+			sb.WriteString(lipgloss.NewStyle().Bold(true).Render("› ") + linha + "\n")
+		} else {
+			sb.WriteString("  " + linha + "\n")
+		}
+	}
+
+	return boxStyle.Render(strings.TrimRight(sb.String(), "\n")) + "\n" + hint.Render("↑/↓: Escolher • Enter: Conectar • Esc: Cancelar")
+}
+
+// abrirSeletorPerfil lê o arquivo de configuração na hora, para pegar perfis recém-adicionados
+func (m *model) abrirSeletorPerfil() tea.Cmd {
+	arq, err := config.ReadFile()
+	if err != nil {
+		return m.notificarErro(err)
+	}
+	if len(arq.Names()) == 0 {
+		return m.notificar(sevAlerta, "Nenhum perfil configurado em "+config.FilePath())
+	}
+	m.trocandoPerfil = true
+	m.seletorPerfil = novoSeletorPerfil(arq, m.perfil)
+	return nil
+}
+
+// trocarPerfil carrega o perfil escolhido; a troca acontece em concluirTrocaPerfil
+func (m *model) trocarPerfil(nome string) tea.Cmd {
+	m.trocandoPerfil = false
+	if nome == m.perfil {
+		return nil
+	}
+	c := &carregarPerfil{nome: nome}
+	return tea.Exec(c, func(err error) tea.Msg { return trocaPerfilMsg{cfg: c.cfg, err: err} })
+}
+
+// concluirTrocaPerfil encerra a TUI para o main reabrir com o perfil novo
+func (m *model) concluirTrocaPerfil(msg trocaPerfilMsg) tea.Cmd {
+	if msg.err != nil {
+		return m.notificarErro(msg.err)
+	}
+	if m.outboxEnviando != 0 {
+		// Trocar agora deixaria a entrada no cache deste perfil mesmo se o servidor confirmar: reenviaria depois
+		return m.notificar(sevAlerta, "Aguarde o envio em andamento terminar para trocar de perfil")
+	}
+	m.proximoPerfil = msg.cfg
+	m.fecharDetalhes()
+	return tea.Quit
+}
+
+// NextProfile devolve a configuração do perfil escolhido na TUI encerrada,
+// ou nil se o usuário saiu do programa
+func NextProfile(final tea.Model) *config.Config {
+	if m, ok := final.(model); ok {
+		return m.proximoPerfil
+	}
+	return nil
+}
+
+// fundoDetectado guarda o que o terminal informou antes de algum perfil forçar o tema
+var fundoDetectado = sync.OnceValue(lipgloss.HasDarkBackground)
+
+// aplicarTema ajusta as cores adaptativas ao fundo do terminal configurado no perfil
+func aplicarTema(tema string) {
+	escuro := fundoDetectado()
+	switch tema {
+	case "dark":
+		escuro = true
+	case "light":
+		escuro = false
+	}
+	lipgloss.SetHasDarkBackground(escuro)
+}
+
+// renderPerfil identifica o servidor em uso ao lado das abas
+func (m model) renderPerfil() string {
+	if m.perfil == "" {
+		return ""
+	}
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("241")).Render(fmt.Sprintf("  ⚙ %s [Ctrl+P]", m.perfil))
+}
@@ -0,0 +1,243 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"glpi-tui/internal/api"
+	"glpi-tui/internal/cache"
+	"glpi-tui/internal/domain"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Busca de novidades em background: a cada intervalo a primeira página das filas já
+// carregadas é buscada de novo e comparada com o que já foi visto. Chamados que o usuário
+// já abriu e mudaram desde então têm os acompanhamentos conferidos para marcar respostas novas.
+
+// pollTickMsg dispara uma rodada da busca de novidades
+type pollTickMsg struct{}
+
+// novasRespostasMsg traz quantos acompanhamentos chegaram depois do último visto
+type novasRespostasMsg struct {
+	ticketID int
+	dateMod  string // date_mod que motivou a conferência
+	novas    int
+}
+
+// agendarPoll marca a próxima rodada; intervalo 0 desliga a busca
+func agendarPoll(intervalo time.Duration) tea.Cmd {
+	if intervalo <= 0 {
+		return nil
+	}
+	return tea.Tick(intervalo, func(time.Time) tea.Msg { return pollTickMsg{} })
+}
+
+// fetchPollCmd busca de novo a primeira página da fila, sem mexer na paginação já carregada
+func fetchPollCmd(c api.Backend, q api.TicketQuery, fila, geracao int) tea.Cmd {
+	return func() tea.Msg {
+		page, err := c.GetTicketsPage(q)
+		if err != nil {
+			if api.IsTemporary(err) {
+				return conexaoFalhouMsg{err}
+			}
+			return ticketsFalhouMsg{fila: fila, geracao: geracao, err: err, poll: true}
+		}
+		return ticketsLoadedMsg{fila: fila, page: page, geracao: geracao, poll: true}
+	}
+}
+
+// fetchNovasRespostasCmd conta os acompanhamentos de outras pessoas depois do último visto
+func fetchNovasRespostasCmd(c api.Backend, t domain.Chamado, v cache.Seen) tea.Cmd {
+	userID := c.MyUserID()
+	return func() tea.Msg {
+		fs, err := c.GetTicketFollowupsContext(context.Background(), t.ID)
+		if err != nil {
+			return nil // Fica para a próxima rodada
+		}
+		n, _ := contarNovos(fs, v.LastFollowup, userID)
+		return novasRespostasMsg{ticketID: t.ID, dateMod: t.DateMod, novas: n}
+	}
+}
+
+// contarNovos conta os acompanhamentos com ID acima de depois que não são do próprio usuário
+// e devolve também o maior ID encontrado
+func contarNovos(fs []domain.TicketFollowup, depois, userID int) (int, int) {
+	n, maior := 0, depois
+	for _, f := range fs {
+		if f.ID > depois && f.User.ID != userID {
+			n++
+		}
+		maior = max(maior, f.ID)
+	}
+	return n, maior
+}
+
+// poll executa uma rodada: reconecta se preciso, atualiza as filas carregadas e o chamado aberto
+func (m *model) poll() tea.Cmd {
+	cmds := []tea.Cmd{agendarPoll(m.intervaloPoll)}
+	switch {
+	case m.erroSessao != nil:
+		// Senha ou permissão recusadas: insistir a cada rodada só repetiria o erro
+		return tea.Batch(cmds...)
+	case !m.logado:
+		// Sem conexão desde a abertura: a própria busca de novidades tenta o login de novo
+		return tea.Batch(append(cmds, performLoginCmd(m.client))...)
+	case m.client.MyUserID() == 0:
+		return tea.Batch(append(cmds, fetchProfileCmd(m.client))...)
+	}
+
+	for i := range m.filas {
+		f := &m.filas[i]
+		if !f.carregada || f.carregandoMais {
+			continue
+		}
+		q, err := f.consulta(m.client, m.filtro, 0)
+		if err != nil {
+			continue
+		}
+		cmds = append(cmds, fetchPollCmd(m.client, q, i, f.geracao))
+	}
+
+	// O chamado aberto recebe os acompanhamentos novos sem precisar do [u]
+	if m.chamadoSelecionado != nil && !m.refreshing {
+		cmds = append(cmds, fetchTimelineCmd(m.detalhesCtx(), m.client, m.chamadoSelecionado.ID))
+	}
+	return tea.Batch(cmds...)
+}
+
+// conferirRespostas agenda a contagem de respostas novas dos chamados já abertos que mudaram
+func (m *model) conferirRespostas(tickets []domain.Chamado) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, t := range tickets {
+		v, ok := m.vistos[t.ID]
+		if !ok || v.DateMod == t.DateMod || m.conferidos[t.ID] == t.DateMod {
+			continue
+		}
+		if m.chamadoSelecionado != nil && m.chamadoSelecionado.ID == t.ID {
+			continue // Está na tela: a timeline já é atualizada direto
+		}
+		m.conferidos[t.ID] = t.DateMod
+		cmds = append(cmds, fetchNovasRespostasCmd(m.client, t, v))
+	}
+	return tea.Batch(cmds...)
+}
+
+// registrarVisto guarda (também no cache) o estado do chamado aberto e limpa os marcadores de leitura
+func (m *model) registrarVisto(c domain.Chamado) tea.Cmd {
+	_, ultimo := contarNovos(c.Followups, m.vistos[c.ID].LastFollowup, 0)
+	seen := cache.Seen{DateMod: c.DateMod, LastFollowup: ultimo, At: time.Now()}
+	m.vistos[c.ID] = seen
+	delete(m.conferidos, c.ID)
+	delete(m.novasRespostas, c.ID)
+	m.marcarLeitura()
+	return salvarVistoCmd(m.cache, c.ID, seen)
+}
+
+// reconciliarVistos aceita como lido o date_mod novo de chamados que mudaram por ação do próprio
+// usuário (escrita confirmada) ou que estão abertos na tela
+func (m *model) reconciliarVistos(tickets []domain.Chamado) tea.Cmd {
+	var cmds []tea.Cmd
+	for _, t := range tickets {
+		v, ok := m.vistos[t.ID]
+		if !ok || v.DateMod == t.DateMod {
+			continue
+		}
+		aberto := m.chamadoSelecionado != nil && m.chamadoSelecionado.ID == t.ID
+		if !aberto && !m.escritasProprias[t.ID] {
+			continue
+		}
+		delete(m.escritasProprias, t.ID)
+		if aberto {
+			m.chamadoSelecionado.DateMod = t.DateMod
+		}
+		v.DateMod = t.DateMod
+		m.vistos[t.ID] = v
+		cmds = append(cmds, salvarVistoCmd(m.cache, t.ID, v))
+	}
+	return tea.Batch(cmds...)
+}
+
+// naoLido diz se o chamado nunca foi aberto ou mudou desde a última visita
+func (m model) naoLido(c domain.Chamado) bool {
+	v, ok := m.vistos[c.ID]
+	return !ok || v.DateMod != c.DateMod
+}
+
+// marcarLeitura atualiza os marcadores de não lido e de respostas novas nos itens de todas as filas
+func (m *model) marcarLeitura() {
+	for i := range m.filas {
+		for j, it := range m.filas[i].list.Items() {
+			ic, ok := it.(itemChamado)
+			if !ok {
+				continue
+			}
+			naoLido, respostas := m.naoLido(ic.Chamado), m.novasRespostas[ic.ID]
+			if ic.naoLido != naoLido || ic.respostas != respostas {
+				ic.naoLido, ic.respostas = naoLido, respostas
+				m.filas[i].list.SetItem(j, ic)
+			}
+		}
+	}
+}
+
+// totalNovidades conta os chamados com novidade em qualquer fila ou com respostas novas
+func (m model) totalNovidades() int {
+	ids := map[int]bool{}
+	for _, f := range m.filas {
+		for id := range f.naoLidos {
+			ids[id] = true
+		}
+	}
+	for id := range m.novasRespostas {
+		ids[id] = true
+	}
+	return len(ids)
+}
+
+// atualizarTituloJanela mostra o contador de novidades no título do terminal
+func (m *model) atualizarTituloJanela() tea.Cmd {
+	titulo := "GLPI"
+	if m.perfil != "" {
+		titulo += " · " + m.perfil
+	}
+	if n := m.totalNovidades(); n > 0 {
+		titulo = fmt.Sprintf("(%d) GLPI", n)
+	}
+	if titulo == m.tituloJanela {
+		return nil
+	}
+	m.tituloJanela = titulo
+	return tea.SetWindowTitle(titulo)
+}
+
+// tocarSino avisa no terminal que chegou novidade (se habilitado em GLPI_BELL)
+func (m model) tocarSino() tea.Cmd {
+	if !m.sino {
+		return nil
+	}
+	return func() tea.Msg {
+		// A saída da TUI é o stdout; o BEL no stderr não interfere no desenho da tela
+		fmt.Fprint(os.Stderr, "\a")
+		return nil
+	}
+}
+
+// limiteNovidades devolve quantos itens do topo da timeline do chamado aberto vão acima do
+// divisor de novidades: até o último acompanhamento de outra pessoa que chegou depois da visita
+// anterior. Sem visita anterior (chamado nunca aberto) não há divisor.
+func (m model) limiteNovidades() int {
+	if m.chamadoSelecionado == nil || m.vistoAnterior == nil {
+		return 0
+	}
+	limite := 0
+	for i, it := range m.chamadoSelecionado.Timeline {
+		f := it.Followup
+		if it.Type == domain.TimelineFollowup && f.ID > m.vistoAnterior.LastFollowup && f.User.ID != m.client.MyUserID() {
+			limite = i + 1
+		}
+	}
+	return limite
+}
@@ -0,0 +1,186 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"glpi-tui/internal/api"
+	"glpi-tui/internal/domain"
+
+	"github.com/charmbracelet/bubbles/textarea"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Campos do diálogo de solução, na ordem de navegação
+const (
+	focoSolucaoTipo = iota
+	focoSolucaoModelo
+	focoSolucaoTexto
+	totalFocosSolucao
+)
+
+// dialogoSolucao é o diálogo de solução do chamado (tecla "S" nos detalhes)
+type dialogoSolucao struct {
+	tipos     []domain.SolutionType
+	modelos   []domain.SolutionTemplate
+	tipoIdx   int // -1 = sem tipo
+	modeloIdx int // -1 = nenhum modelo
+	texto     textarea.Model
+	foco      int
+
+	carregandoOpcoes bool
+	erro             string
+	enviando         bool
+}
+
+func novoDialogoSolucao(largura int) dialogoSolucao {
+	ta := textarea.New()
+	ta.Placeholder = "Descreva a solução aplicada..."
+	ta.CharLimit = 0
+	ta.ShowLineNumbers = false
+	ta.SetWidth(largura - 4)
+	ta.SetHeight(6)
+
+	d := dialogoSolucao{tipoIdx: -1, modeloIdx: -1, texto: ta, carregandoOpcoes: true}
+	d.focar(focoSolucaoTexto)
+	return d
+}
+
+func (d *dialogoSolucao) focar(f int) tea.Cmd {
+	d.foco = f
+	if f == focoSolucaoTexto {
+		return d.texto.Focus()
+	}
+	d.texto.Blur()
+	return nil
+}
+
+// aplicarOpcoes recebe os tipos e modelos carregados da API
+func (d *dialogoSolucao) aplicarOpcoes(tipos []domain.SolutionType, modelos []domain.SolutionTemplate) {
+	d.carregandoOpcoes = false
+	d.tipos = tipos
+	d.modelos = modelos
+}
+
+// usarModelo preenche o texto e o tipo a partir do modelo escolhido
+func (d *dialogoSolucao) usarModelo(i int) {
+	d.modeloIdx = i
+	if i < 0 {
+		return
+	}
+	m := d.modelos[i]
+	d.texto.SetValue(m.GetCleanContent())
+	for j, t := range d.tipos {
+		if t.ID == m.SolutionType.ID {
+			d.tipoIdx = j
+		}
+	}
+}
+
+func (d dialogoSolucao) update(msg tea.Msg) (dialogoSolucao, tea.Cmd) {
+	if k, ok := msg.(tea.KeyMsg); ok {
+		switch k.String() {
+		case "tab":
+			return d, d.focar((d.foco + 1) % totalFocosSolucao)
+		case "shift+tab":
+			return d, d.focar((d.foco + totalFocosSolucao - 1) % totalFocosSolucao)
+		case "left", "right":
+			delta := 1
+			if k.String() == "left" {
+				delta = -1
+			}
+			switch d.foco {
+			case focoSolucaoTipo:
+				d.tipoIdx = ciclar(d.tipoIdx, delta, len(d.tipos))
+				return d, nil
+			case focoSolucaoModelo:
+				d.usarModelo(ciclar(d.modeloIdx, delta, len(d.modelos)))
+				return d, nil
+			}
+		}
+	}
+
+	if d.foco != focoSolucaoTexto {
+		return d, nil
+	}
+	var cmd tea.Cmd
+	d.texto, cmd = d.texto.Update(msg)
+	return d, cmd
+}
+
+// ciclar percorre -1..n-1 (onde -1 representa "nenhum")
+func ciclar(i, delta, n int) int {
+	i += delta
+	if i >= n {
+		return -1
+	}
+	if i < -1 {
+		return n - 1
+	}
+	return i
+}
+
+// input monta a solução a enviar
+func (d dialogoSolucao) input() (api.SolutionInput, error) {
+	in := api.SolutionInput{Content: strings.TrimSpace(d.texto.Value())}
+	if in.Content == "" {
+		return in, fmt.Errorf("descreva a solução antes de enviar")
+	}
+	if d.tipoIdx >= 0 {
+		in.SolutionTypeID = d.tipos[d.tipoIdx].ID
+	}
+	return in, nil
+}
+
+func (d dialogoSolucao) view() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("#04B575")).
+		Padding(0, 1).
+		MarginTop(1)
+	labelStyle := lipgloss.NewStyle().Width(10).Foreground(lipgloss.Color("241"))
+	focoStyle := labelStyle.Foreground(lipgloss.Color("69")).Bold(true)
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	label := func(f int, texto string) string {
+		if d.foco == f {
+			return focoStyle.Render("› " + texto)
+		}
+		return labelStyle.Render("  " + texto)
+	}
+
+	tipo, modelo := "Sem tipo", "Nenhum"
+	if d.carregandoOpcoes {
+		tipo, modelo = "Carregando...", "Carregando..."
+	}
+	if d.tipoIdx >= 0 {
+		tipo = d.tipos[d.tipoIdx].Name
+	}
+	if d.modeloIdx >= 0 {
+		modelo = d.modelos[d.modeloIdx].Name
+	}
+	if d.foco == focoSolucaoTipo {
+		tipo = "◀ " + tipo + " ▶"
+	}
+	if d.foco == focoSolucaoModelo {
+		modelo = "◀ " + modelo + " ▶"
+	}
+
+	body := fmt.Sprintf("✅ Solucionar chamado\n%s%s\n%s%s\n%s",
+		label(focoSolucaoTipo, "Tipo"), tipo,
+		label(focoSolucaoModelo, "Modelo"), modelo,
+		d.texto.View(),
+	)
+
+	var rodape string
+	switch {
+	case d.enviando:
+		rodape = lipgloss.NewStyle().Foreground(lipgloss.Color("208")).Bold(true).Render("Enviando solução... aguarde.")
+	case d.erro != "":
+		rodape = lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("⚠ " + d.erro)
+	default:
+		rodape = hint.Render("Tab: Navegar • ←/→: Tipo/Modelo • Ctrl+S: Solucionar • Esc: Cancelar")
+	}
+	return boxStyle.Render(body) + "\n" + rodape
+}
@@ -0,0 +1,72 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"glpi-tui/internal/domain"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// seletorStatus é o menu de troca de status (tecla "s" nos detalhes).
+// Só oferece os destinos permitidos pela máquina de estados do domain.
+type seletorStatus struct {
+	atual  int
+	opcoes []domain.StatusTransition
+	cursor int
+}
+
+func novoSeletorStatus(atual int) seletorStatus {
+	return seletorStatus{atual: atual, opcoes: domain.NextStatuses(atual)}
+}
+
+// update move o cursor; devolve a transição escolhida quando o usuário confirma com Enter
+func (s seletorStatus) update(msg tea.KeyMsg) (seletorStatus, *domain.StatusTransition) {
+	switch msg.String() {
+	case "up", "k":
+		if s.cursor > 0 {
+			s.cursor--
+		}
+	case "down", "j":
+		if s.cursor < len(s.opcoes)-1 {
+			s.cursor++
+		}
+	case "enter":
+		if len(s.opcoes) > 0 {
+			t := s.opcoes[s.cursor]
+			return s, &t
+		}
+	}
+	return s, nil
+}
+
+func (s seletorStatus) view() string {
+	boxStyle := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("69")).
+		Padding(0, 1).
+		MarginTop(1)
+	hint := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Mudar status (atual: %s)\n", domain.StatusLabel(s.atual)))
+	if len(s.opcoes) == 0 {
+		sb.WriteString(hint.Render("Nenhuma transição disponível a partir deste status."))
+	}
+	for i, t := range s.opcoes {
+		label := domain.StatusLabel(t.To)
+		if t.Reopen {
+			label = "Reabrir como " + label
+		}
+		line := lipgloss.NewStyle().Foreground(domain.StatusColor(t.To)).Render(label)
+		if i == s.cursor {
+			sb.WriteString(lipgloss.NewStyle().Bold(true).Render("› ") + line + "\n")
+		} else {
+			sb.WriteString("  " + line + "\n")
+		}
+	}
+
+	return boxStyle.Render(strings.TrimRight(sb.String(), "\n")) + "\n" + hint.Render("↑/↓: Escolher • Enter: Confirmar • Esc: Cancelar")
+}
@@ -0,0 +1,126 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"glpi-tui/internal/domain"
+	"glpi-tui/internal/richtext"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// estiloTimeline define ícone, rótulo e cor de cada tipo de item da timeline
+var estiloTimeline = map[domain.TimelineType]struct {
+	icone  string
+	rotulo string
+	cor    lipgloss.Color
+}{
+	domain.TimelineFollowup:   {"💬", "Acompanhamento", lipgloss.Color("#00D7D7")},
+	domain.TimelineTask:       {"⏱", "Tarefa", lipgloss.Color("#FFC107")},
+	domain.TimelineSolution:   {"✅", "Solução", lipgloss.Color("#04B575")},
+	domain.TimelineDocument:   {"📎", "Documento", lipgloss.Color("#C678DD")},
+	domain.TimelineValidation: {"🗳", "Validação", lipgloss.Color("#FF8800")},
+}
+
+// renderTimeline desenha os itens na ordem recebida (já ordenados pelo domain, mais recente
+// primeiro). Os novos primeiros itens são o que chegou desde a última visita e ficam acima de um divisor.
+func renderTimeline(items []domain.TimelineItem, largura, novos int) string {
+	var sb strings.Builder
+	for i, it := range items {
+		if novos > 0 && i == novos {
+			sb.WriteString("\n" + renderDivisorNovidades(largura) + "\n")
+		}
+		sb.WriteString("\n" + renderTimelineItem(it, largura) + "\n")
+	}
+	if novos > 0 && novos == len(items) {
+		sb.WriteString("\n" + renderDivisorNovidades(largura) + "\n")
+	}
+	return sb.String()
+}
+
+// renderDivisorNovidades separa o que chegou desde a última visita do que já tinha sido lido
+func renderDivisorNovidades(largura int) string {
+	rotulo := " ⬆ Novo desde sua última visita "
+	traco := max(0, largura-lipgloss.Width(rotulo)) / 2
+	return lipgloss.NewStyle().Foreground(lipgloss.Color("#FF5F87")).Bold(true).
+		Render(strings.Repeat("─", traco) + rotulo + strings.Repeat("─", traco))
+}
+
+func renderTimelineItem(it domain.TimelineItem, largura int) string {
+	est := estiloTimeline[it.Type]
+	infoStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("241"))
+	dividerStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	// Estilo do cabeçalho do item (Tipo, Quem e Quando)
+	autor := lipgloss.NewStyle().Bold(true).Foreground(est.cor).Render(it.Author())
+	meta := []string{domain.FormatDate(it.RawDate()), est.rotulo}
+
+	// Solução pendente vai dentro de uma moldura (borda + padding), que come 6 colunas
+	pendente := it.Type == domain.TimelineSolution && it.Solution.IsPendingApproval()
+	larguraCorpo := largura
+	if pendente {
+		larguraCorpo = max(20, largura-4) - 2
+	}
+
+	var corpo string
+	switch it.Type {
+	case domain.TimelineFollowup:
+		corpo = richtext.Render(it.Followup.Content, larguraCorpo)
+
+	case domain.TimelineTask:
+		t := it.Task
+		meta = append(meta, domain.FormatDuration(t.Duration()), domain.TaskStateLabel(t.State))
+		if plan := t.GetPlanning(); plan != "" {
+			meta = append(meta, "Plan.: "+plan)
+		}
+		corpo = richtext.Render(t.Content, larguraCorpo)
+
+	case domain.TimelineSolution:
+		s := it.Solution
+		if s.Type.Name != "" {
+			meta = append(meta, s.Type.Name)
+		}
+		if label := s.StatusLabel(); label != "" {
+			meta = append(meta, label)
+		}
+		corpo = richtext.Render(s.Content, larguraCorpo)
+
+	case domain.TimelineDocument:
+		d := it.Document
+		corpo = fmt.Sprintf("%s (%s)", d.Filename, d.Mime)
+		if d.Name != "" && d.Name != d.Filename {
+			corpo = d.Name + " — " + corpo
+		}
+
+	case domain.TimelineValidation:
+		v := it.Validation
+		if label := v.StatusLabel(); label != "" {
+			meta = append(meta, label)
+		}
+		corpo = fmt.Sprintf("Aprovador: %s", v.Approver.Name)
+		if c := richtext.Render(v.CommentSubmission, larguraCorpo); c != "" {
+			corpo += "\n" + c
+		}
+		if c := richtext.Render(v.CommentValidation, larguraCorpo-2); c != "" {
+			corpo += "\n↳ " + c
+		}
+	}
+
+	bloco := fmt.Sprintf("%s %s %s\n%s\n%s",
+		est.icone, autor, infoStyle.Render("• "+strings.Join(meta, " • ")),
+		dividerStyle.Render(strings.Repeat("-", 20)),
+		corpo,
+	)
+
+	// Solução aguardando aprovação ganha moldura e atalhos
+	if pendente {
+		bloco = lipgloss.NewStyle().
+			Border(lipgloss.ThickBorder()).
+			BorderForeground(lipgloss.Color("#FFC107")).
+			Padding(0, 1).
+			Width(max(20, largura-4)).
+			Render("⏳ AGUARDANDO APROVAÇÃO • [A] Aprovar • [X] Recusar\n" + bloco)
+	}
+	return bloco
+}
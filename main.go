@@ -1,34 +1,132 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
+	"path/filepath"
+	"time"
 
 	"glpi-tui/internal/api"
+	"glpi-tui/internal/cache"
+	"glpi-tui/internal/cli"
 	"glpi-tui/internal/config"
+	"glpi-tui/internal/fake"
+	"glpi-tui/internal/secrets"
 	"glpi-tui/internal/tui"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 func main() {
-	// 1. Carrega Configurações (valida .env e variáveis obrigatórias)
-	cfg, err := config.Load()
-	if err != nil {
+	perfil := flag.String("profile", "", "perfil do arquivo de configuração (padrão: GLPI_PROFILE ou default_profile)")
+	demo := flag.Bool("demo", false, "abre a interface com um GLPI de demonstração em memória, sem servidor")
+	flag.Usage = func() { cli.Usage(os.Stderr) }
+	flag.Parse()
+
+	// 0. Subcomandos (glpi-tui list, show...) rodam sem abrir a TUI
+	if flag.NArg() > 0 {
+		os.Exit(cli.Run(flag.Args(), *perfil, os.Stdin, os.Stdout, os.Stderr))
+	}
+
+	// 1. Carrega Configurações (valida .env, arquivo de perfis e variáveis obrigatórias).
+	// No modo demo a primeira TUI roda sem servidor; uma troca de perfil nela segue para o servidor escolhido.
+	var cfg *config.Config
+	var err error
+	if *demo {
+		if cfg, err = rodarDemo(); err != nil {
+			fmt.Printf("Erro fatal na TUI: %v\n", err)
+			os.Exit(1)
+		}
+	} else if cfg, err = config.LoadProfile(*perfil); err != nil {
 		fmt.Printf("Erro de Configuração: %v\n", err)
 		os.Exit(1)
 	}
 
-	// 2. Cria o Cliente API (já com timeout e base URL configurados)
-	client := api.NewClient(cfg)
+	// 2. Roda a TUI; a troca de perfil encerra a atual e abre outra com o novo servidor
+	for cfg != nil {
+		if cfg, err = rodarTUI(cfg); err != nil {
+			fmt.Printf("Erro fatal na TUI: %v\n", err)
+			os.Exit(1)
+		}
+	}
+}
+
+// rodarTUI abre cliente e cache do perfil e roda a interface até o usuário sair.
+// Devolve a configuração do próximo perfil se o usuário pediu a troca.
+func rodarTUI(cfg *config.Config) (*config.Config, error) {
+	// Cria o Cliente API da versão do servidor (v2 ou a v1 legada), já com timeout e base URL configurados
+	client, err := api.New(cfg)
+	if err != nil {
+		return nil, err
+	}
 
-	// 3. Inicia o Modelo TUI (Injetando o cliente)
-	m := tui.InitialModel(client)
+	// Login pelo navegador ou por código: acontece antes da TUI, com o terminal livre para as instruções
+	if client.Interactive() {
+		client.SetInteract(func(instrucoes string) { fmt.Fprintln(os.Stderr, instrucoes) })
+		if err := client.Login(); err != nil {
+			fmt.Fprintf(os.Stderr, "Aviso: %v\n", err)
+		}
+		client.SetInteract(nil) // Dentro da TUI não há como mostrar as instruções
+	}
 
-	// 4. Roda o Programa
-	p := tea.NewProgram(m, tea.WithAltScreen())
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Erro fatal na TUI: %v\n", err)
-		os.Exit(1)
+	// A v2 renova o refresh token no meio do uso; com o arquivo cifrado a frase-senha é pedida
+	// agora, porque dentro da TUI o terminal não está livre para isso
+	if cfg.API == config.APIV2 {
+		if err := cfg.UnlockSecrets(); err != nil {
+			fmt.Fprintf(os.Stderr, "Aviso: a sessão renovada não será guardada: %v\n", err)
+		}
+	}
+
+	// Abre o cache offline; sem ele o programa funciona normalmente, só não mostra nada antes do servidor responder
+	var store *cache.Store
+	if cfg.CacheDir != "" {
+		store, err = cache.Open(cache.Path(cfg.CacheDir, cfg.BaseURL, cfg.Username))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Aviso: cache offline indisponível: %v\n", err)
+		}
+	}
+	defer store.Close()
+
+	return executarTUI(client, cfg, store)
+}
+
+// rodarDemo roda a interface sobre o GLPI em memória do pacote fake, com um pouco de latência
+// para que os indicadores de carregamento apareçam. Nada é gravado em cache.
+func rodarDemo() (*config.Config, error) {
+	backend := fake.Demo()
+	backend.Latency = 300 * time.Millisecond
+	cfg := &config.Config{
+		Profile:      "demo",
+		API:          config.APIV2,
+		Theme:        "auto",
+		DownloadDir:  filepath.Join(os.TempDir(), "glpi-tui-demo"),
+		PollInterval: config.DefaultPollInterval,
+	}
+	return executarTUI(backend, cfg, nil)
+}
+
+// executarTUI roda o programa Bubble Tea até o usuário sair e devolve o próximo perfil, se houver
+func executarTUI(client api.Backend, cfg *config.Config, store *cache.Store) (*config.Config, error) {
+	// A TUI é dona do terminal: a sessão renovada que não puder ser guardada vira aviso na tela
+	falhas := make(chan error, 1)
+	cfg.OnSaveError = func(err error) {
+		select {
+		case falhas <- err:
+		default: // Já há um aviso pendente
+		}
+	}
+	defer func() { cfg.OnSaveError = nil }()
+
+	// Inicia o Modelo TUI (Injetando o cliente e o cache)
+	m := tui.InitialModel(client, cfg, store, tui.WithSaveErrors(falhas))
+
+	// Roda o Programa; enquanto ele estiver na tela ninguém lê do terminal
+	secrets.AllowPrompt(false)
+	defer secrets.AllowPrompt(true)
+	final, err := tea.NewProgram(m, tea.WithAltScreen()).Run()
+	if err != nil {
+		return nil, err
 	}
+	return tui.NextProfile(final), nil
 }